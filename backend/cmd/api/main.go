@@ -17,7 +17,9 @@ import (
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/config"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/clock"
 	jwt "github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/jwt" // NOTE: lowercase 'jwt'
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/mail"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/s3client"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/pubsub"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository/postgres"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/service"
 	httpx "github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/transport/http"
@@ -56,8 +58,23 @@ func main() {
 	if err != nil {
 		log.Fatal("s3 init failed", zap.Error(err))
 	}
-	jwtSigner := jwt.New([]byte(cfg.JWTSecret))
+	// Listing watch, saved search and notification events go out through pub/sub; only the
+	// postgres driver reaches the WebSocket server.
+	bus, err := pubsub.NewBroker(cfg.PubSubDriver, pool, log)
+	if err != nil {
+		log.Fatal("pub/sub init failed", zap.Error(err))
+	}
+	defer bus.Close()
+	jwtOpts, err := cfg.JWTOpts()
+	if err != nil {
+		log.Fatal("jwt config invalid", zap.Error(err))
+	}
 	clk := clock.Real{}
+	jwtOpts.Clock = clk
+	jwtSigner, err := jwt.New(jwtOpts)
+	if err != nil {
+		log.Fatal("jwt init failed", zap.Error(err))
+	}
 	v := validator.New()
 
 	// 4) Repositories
@@ -66,13 +83,58 @@ func main() {
 	reportRepo := postgres.NewReportRepo(pool)
 	adminRepo := postgres.NewAdminRepo(pool)
 	authRepo := postgres.NewAuthRepo(pool)
-	// chatRepo := postgres.NewChatRepo(pool)
+	sessionRepo := postgres.NewSessionRepo(pool)
+	chatRepo := postgres.NewChatRepo(pool)
+	offerRepo := postgres.NewOfferRepo(pool)
+	reviewRepo := postgres.NewReviewRepo(pool)
+	favoriteRepo := postgres.NewFavoriteRepo(pool)
+	savedSearchRepo := postgres.NewSavedSearchRepo(pool)
+	notificationRepo := postgres.NewNotificationRepo(pool)
+	loginAttemptRepo := postgres.NewLoginAttemptRepo(pool)
+	auditRepo := postgres.NewAuditRepo(pool)
 
 	// 5) Services (business)
-	authSvc := service.NewAuthService(authRepo, jwtSigner, clk, time.Duration(cfg.PresignExpiry)*time.Minute)
-	reportSvc := service.NewReportService(reportRepo)
-	adminSvc := service.NewAdminService(adminRepo)
-	// chatSvc := service.NewChatService(chatRepo)
+	notificationSvc := service.NewNotificationService(notificationRepo, bus, log)
+	authSvc := service.NewAuthService(authRepo, sessionRepo, jwtSigner, clk,
+		time.Duration(cfg.PresignExpiry)*time.Minute, time.Duration(cfg.RefreshDays)*24*time.Hour)
+	authSvc.EnableLoginThrottle(loginAttemptRepo, auditRepo, service.LoginPolicy{
+		MaxFailures:   cfg.LoginMaxFailures,
+		IPMaxFailures: cfg.LoginIPMaxFailures,
+		Lockout:       time.Duration(cfg.LoginLockoutMin) * time.Minute,
+	})
+	mailer, err := mail.New(cfg.MailOpts(), log)
+	if err != nil {
+		log.Fatal("mailer init failed", zap.Error(err))
+	}
+	authSvc.EnablePasswordReset(service.PasswordReset{
+		Mailer:   mailer,
+		LinkURL:  cfg.ResetURL,
+		Requests: loginAttemptRepo,
+		Logger:   log,
+	})
+	if cfg.EmailVerify {
+		authSvc.EnableEmailVerification(service.EmailVerification{
+			AllowedDomains: cfg.EmailDomains(),
+			Mailer:         mailer,
+			LinkURL:        cfg.VerifyURL,
+		})
+	}
+	favoriteSvc := service.NewFavoriteService(favoriteRepo, listingsRepo, bus, log)
+	listingSvc := service.NewListingService(listingsRepo, favoriteSvc)
+	reportSvc := service.NewReportService(reportRepo, notificationSvc)
+	adminSvc := service.NewAdminService(adminRepo, listingSvc, authSvc)
+	chatSvc := service.NewChatService(chatRepo, listingsRepo, clk)
+	reviewSvc := service.NewReviewService(reviewRepo, listingsRepo)
+	userSvc := service.NewUserService(authRepo, reviewRepo, listingsRepo)
+	userSvc.EnableAvatars(s3c)
+	savedSearchSvc := service.NewSavedSearchService(savedSearchRepo)
+	offerSvc := service.NewOfferService(offerRepo, listingsRepo, clk, time.Duration(cfg.ReserveHours)*time.Hour, notificationSvc)
+
+	// Background jobs, stopped on shutdown
+	jobsCtx, stopJobs := context.WithCancel(ctx)
+	defer stopJobs()
+	go service.NewReservationSweeper(listingsRepo, clk, 0, log).Run(jobsCtx)
+	go service.NewSavedSearchMatcher(savedSearchRepo, listingsRepo, bus, clk, 0, log).Run(jobsCtx)
 
 	// 6) Router with full deps
 	r := httpx.NewRouter(httpx.Deps{
@@ -82,13 +144,22 @@ func main() {
 		AuthRepo: authRepo,
 		Reports:  reportRepo,
 		Admin:    adminRepo,
-		// Chat:     chatRepo,
+		Chat:     chatRepo,
+		Offers:   offerRepo,
+		Reviews:  reviewRepo,
 
 		// services
-		AuthSvc:   authSvc,
-		ReportSvc: reportSvc,
-		AdminSvc:  adminSvc,
-		// ChatSvc:   chatSvc,
+		AuthSvc:         authSvc,
+		ListingSvc:      listingSvc,
+		ReportSvc:       reportSvc,
+		AdminSvc:        adminSvc,
+		ChatSvc:         chatSvc,
+		OfferSvc:        offerSvc,
+		ReviewSvc:       reviewSvc,
+		UserSvc:         userSvc,
+		FavoriteSvc:     favoriteSvc,
+		SavedSearchSvc:  savedSearchSvc,
+		NotificationSvc: notificationSvc,
 
 		// infra
 		Validate:  v,
@@ -96,10 +167,18 @@ func main() {
 		ExpiryMin: cfg.PresignExpiry,
 
 		// auth config for middleware
-		JWTSecret: []byte(cfg.JWTSecret),
-		Env:       cfg.Env,
+		JWT:         jwtSigner,
+		Revocations: authSvc,
+		Verified:    authSvc,
+		Env:         cfg.Env,
 	})
 
+	// Only listed proxies may set X-Forwarded-For; otherwise anyone could
+	// pick the IP that sign-in throttling counts against.
+	if err := r.SetTrustedProxies(cfg.Proxies()); err != nil {
+		log.Fatal("invalid TRUSTED_PROXIES", zap.Error(err))
+	}
+
 	// 7) HTTP server + graceful shutdown
 	srv := &http.Server{Addr: ":" + cfg.HTTPPort, Handler: r}
 	go func() {
@@ -112,6 +191,7 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
+	stopJobs()
 
 	ctxShut, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/config"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/jwt"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository/postgres"
 )
 
@@ -111,6 +114,40 @@ func main() {
 		}
 	}
 
+	// Step 4: Issue a token and verify it the way the API and WS server do
+	fmt.Println("\nStep 4: Testing token signing and verification...")
+	jwtOpts, err := cfg.JWTOpts()
+	if err != nil {
+		fmt.Printf("❌ JWT config invalid: %v\n", err)
+		os.Exit(1)
+	}
+	tokens, err := jwt.New(jwtOpts)
+	if err != nil {
+		fmt.Printf("❌ JWT init failed: %v\n", err)
+		os.Exit(1)
+	}
+	tok, err := tokens.SignJWT(user, "", uuid.NewString(), time.Now().Add(time.Minute))
+	if err != nil {
+		fmt.Printf("❌ Signing failed: %v\n", err)
+		os.Exit(1)
+	}
+	if _, err := tokens.Verify(tok); err != nil {
+		fmt.Printf("❌ Freshly signed token does not verify: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Token signed with key %q verifies (issuer %q, audience %q)\n", jwtOpts.Keys[0].ID, jwtOpts.Issuer, jwtOpts.Audience)
+
+	// A token to check can be passed as the first argument
+	if len(os.Args) > 1 {
+		claims, err := tokens.Verify(os.Args[1])
+		if err != nil {
+			fmt.Printf("❌ Given token rejected: %v\n", err)
+		} else {
+			fmt.Printf("✅ Given token valid: sub=%s role=%s jti=%s sid=%s exp=%s\n",
+				claims.Sub, claims.Role, claims.ID, claims.Sid, claims.ExpiresAt.Time.Format(time.RFC3339))
+		}
+	}
+
 	fmt.Println("\n=== Authentication Debug Complete ===")
 }
 
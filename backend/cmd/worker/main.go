@@ -20,21 +20,16 @@ func main() {
 		log.Fatal("config load failed", zap.Error(err))
 	}
 
-	apiKey := cfg.OpenAIKey
-	if cfg.GeminiKey != "" {
-		apiKey = cfg.GeminiKey
-	}
-
-	if apiKey == "" {
-		log.Fatal("GEMINI_API_KEY or OPENAI_API_KEY is required")
-	}
-
-	// Only show first 10 chars for security
-	maskedKey := apiKey
-	if len(apiKey) > 10 {
-		maskedKey = apiKey[:10] + "..." + apiKey[len(apiKey)-4:]
+	llm, err := service.NewLLMProvider(service.LLMConfig{
+		Provider: cfg.LLMProvider,
+		APIKey:   cfg.LLMKey(),
+		Model:    cfg.LLMModel,
+		BaseURL:  cfg.LLMBaseURL,
+	})
+	if err != nil {
+		log.Fatal("LLM provider init failed (set GEMINI_API_KEY/OPENAI_API_KEY or LLM_PROVIDER=offline)", zap.Error(err))
 	}
-	log.Info("API key loaded for Gemini", zap.String("key_preview", maskedKey))
+	log.Info("LLM provider ready", zap.String("provider", llm.Name()))
 
 	ctx := context.Background()
 
@@ -46,26 +41,62 @@ func main() {
 
 	// Initialize repositories
 	listingsRepo := postgres.NewListingRepo(pool)
+	imagesRepo := postgres.NewImageRepo(pool)
 
 	// Initialize agent service
-	agentService := service.NewAgentService(apiKey, listingsRepo, log)
+	agentService := service.NewAgentServiceFull(llm, listingsRepo, imagesRepo, nil, cfg.PresignExpiry, log)
+	agentService.EnableSavedSearches(service.NewSavedSearchService(postgres.NewSavedSearchRepo(pool)))
 
-	// Initialize pub/sub bus (shared with WS server via external broker in production)
-	// For now, this is a separate in-memory bus - in production, use Redis/NATS
-	bus := pubsub.New()
+	// The worker only sees requests from cmd/ws over a shared broker
+	// (PUBSUB_DRIVER=postgres); an in-memory bus is private to this process.
+	bus, err := pubsub.NewBroker(cfg.PubSubDriver, pool, log)
+	if err != nil {
+		log.Fatal("pub/sub init failed", zap.Error(err))
+	}
+	if cfg.PubSubDriver != pubsub.DriverPostgres {
+		log.Warn("PUBSUB_DRIVER is not postgres; this worker will not receive requests from the ws server")
+	}
 
 	log.Info("agent worker started, waiting for requests...")
 
-	// Subscribe to agent requests
-	requestChan := bus.Subscribe("agent.request")
+	// Subscribe to agent and chat requests
+	agentChan := bus.Subscribe("agent.request")
+	chatChan := bus.Subscribe("chat.request")
+	resetChan := bus.Subscribe("agent.reset")
 
 	// Process requests
-	for msg := range requestChan {
-		go handleAgentRequest(ctx, msg, agentService, bus, log)
+	for {
+		select {
+		case msg, ok := <-agentChan:
+			if !ok {
+				return
+			}
+			go handleAgentRequest(ctx, msg, agentService, bus, log)
+		case msg, ok := <-chatChan:
+			if !ok {
+				return
+			}
+			go handleChatRequest(ctx, msg, agentService, bus, log)
+		case msg, ok := <-resetChan:
+			if !ok {
+				return
+			}
+			handleConversationReset(msg, agentService, log)
+		}
 	}
 }
 
-func handleAgentRequest(ctx context.Context, msg pubsub.Message, agentService *service.AgentService, bus *pubsub.Bus, log *zap.Logger) {
+func handleConversationReset(msg pubsub.Message, agentService *service.AgentService, log *zap.Logger) {
+	req, ok := msg.Payload.(pubsub.ConversationReset)
+	if !ok {
+		log.Error("invalid conversation reset payload")
+		return
+	}
+	agentService.ResetConversation(service.ConversationKey{UserID: req.UserID, SessionID: req.SessionID})
+	log.Debug("conversation reset", zap.String("userId", req.UserID), zap.String("sessionId", req.SessionID))
+}
+
+func handleAgentRequest(ctx context.Context, msg pubsub.Message, agentService *service.AgentService, bus pubsub.Broker, log *zap.Logger) {
 	req, ok := msg.Payload.(pubsub.AgentRequest)
 	if !ok {
 		log.Error("invalid agent request payload")
@@ -79,7 +110,21 @@ func handleAgentRequest(ctx context.Context, msg pubsub.Message, agentService *s
 	)
 
 	// Process query with ChatGPT and DB search
-	answer, results, err := agentService.ProcessQuery(ctx, req.Query)
+	conv := service.ConversationKey{UserID: req.UserID, SessionID: req.SessionID}
+	var (
+		answer  string
+		results []pubsub.ListingInfo
+		err     error
+	)
+	if req.Stream {
+		seq := 0
+		answer, results, err = agentService.ProcessQueryStream(ctx, conv, req.Query, func(d service.AnswerDelta) {
+			bus.Publish("agent.response.delta", pubsub.ResponseDelta{UserID: req.UserID, RequestID: req.RequestID, Seq: seq, Delta: d.Text, Replace: d.Replace})
+			seq++
+		})
+	} else {
+		answer, results, err = agentService.ProcessQuery(ctx, conv, req.Query)
+	}
 	if err != nil {
 		log.Error("failed to process query",
 			zap.Error(err),
@@ -96,6 +141,7 @@ func handleAgentRequest(ctx context.Context, msg pubsub.Message, agentService *s
 		RequestID: req.RequestID,
 		Answer:    answer,
 		Results:   results,
+		Streamed:  req.Stream,
 	}
 
 	bus.Publish("agent.response", response)
@@ -106,3 +152,49 @@ func handleAgentRequest(ctx context.Context, msg pubsub.Message, agentService *s
 		zap.Int("resultCount", len(results)),
 	)
 }
+
+func handleChatRequest(ctx context.Context, msg pubsub.Message, agentService *service.AgentService, bus pubsub.Broker, log *zap.Logger) {
+	req, ok := msg.Payload.(pubsub.ChatRequest)
+	if !ok {
+		log.Error("invalid chat request payload")
+		return
+	}
+
+	log.Info("processing chat request",
+		zap.String("userId", req.UserID),
+		zap.String("requestId", req.RequestID),
+		zap.String("text", req.Text),
+	)
+
+	conv := service.ConversationKey{UserID: req.UserID, SessionID: req.SessionID}
+	var (
+		answer  string
+		results []pubsub.ListingInfo
+		err     error
+	)
+	if req.Stream {
+		seq := 0
+		answer, results, err = agentService.ProcessChatStream(ctx, conv, req.Text, func(d service.AnswerDelta) {
+			bus.Publish("chat.response.delta", pubsub.ResponseDelta{UserID: req.UserID, RequestID: req.RequestID, Seq: seq, Delta: d.Text, Replace: d.Replace})
+			seq++
+		})
+	} else {
+		answer, results, err = agentService.ProcessChat(ctx, conv, req.Text)
+	}
+	if err != nil {
+		log.Error("chat processing failed",
+			zap.Error(err),
+			zap.String("requestId", req.RequestID),
+		)
+		answer = "Sorry, I had trouble with that. Try rephrasing?"
+		results = nil
+	}
+
+	bus.Publish("chat.response", pubsub.ChatResponse{
+		UserID:    req.UserID,
+		RequestID: req.RequestID,
+		Answer:    answer,
+		Results:   results,
+		Streamed:  req.Stream,
+	})
+}
@@ -2,13 +2,16 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"go.uber.org/zap"
 
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/config"
-	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/jwt" // <— NEW
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/clock"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/jwt"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/pubsub"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository/postgres"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/service"
@@ -33,64 +36,98 @@ func main() {
 		log.Fatal("config load failed", zap.Error(err))
 	}
 
-	// pub/sub
-	bus := pubsub.New()
-
-	// hub
-	hub := ws.NewHub(bus, log)
-	go hub.Run()
-	log.Info("websocket hub started")
+	jwtOpts, err := cfg.JWTOpts()
+	if err != nil {
+		log.Fatal("jwt config invalid", zap.Error(err))
+	}
+	tokens, err := jwt.New(jwtOpts)
+	if err != nil {
+		log.Fatal("jwt init failed", zap.Error(err))
+	}
 
-	// DB + agent service
+	// DB + agent/chat services
 	ctx := context.Background()
+	var chatSvc *service.ChatService
+	var notifier service.Notifier     // stays nil without a DB
+	var revocations ws.RevocationList // nil without a DB: revoked tokens still connect
 
 	pool, err := postgres.NewPool(ctx, cfg.DBDSN)
 	if err != nil {
 		log.Warn("db connection failed, agent/chat will not work", zap.Error(err))
 	} else {
 		defer pool.Close()
+	}
 
-		// choose AI key
-		apiKey := cfg.OpenAIKey
-		if cfg.GeminiKey != "" {
-			apiKey = cfg.GeminiKey
-		}
-		if apiKey == "" {
-			log.Warn("No API key configured (OPENAI_API_KEY or GEMINI_API_KEY)")
+	// pub/sub
+	bus, err := pubsub.NewBroker(cfg.PubSubDriver, pool, log)
+	if err != nil {
+		log.Fatal("pub/sub init failed", zap.Error(err))
+	}
+	log.Info("pub/sub ready", zap.String("driver", cfg.PubSubDriver))
+
+	if pool != nil {
+		notifier = service.NewNotificationService(postgres.NewNotificationRepo(pool), bus, log)
+		revocations = service.NewAuthService(postgres.NewAuthRepo(pool), postgres.NewSessionRepo(pool),
+			tokens, clock.Real{}, time.Duration(cfg.PresignExpiry)*time.Minute,
+			time.Duration(cfg.RefreshDays)*24*time.Hour)
+
+		// choose LLM provider
+		llm, err := service.NewLLMProvider(service.LLMConfig{
+			Provider: cfg.LLMProvider,
+			APIKey:   cfg.LLMKey(),
+			Model:    cfg.LLMModel,
+			BaseURL:  cfg.LLMBaseURL,
+		})
+		if err != nil {
+			log.Warn("no LLM provider, agent answers will use heuristics only", zap.Error(err))
 		} else {
-			masked := apiKey
-			if len(apiKey) > 10 {
-				masked = apiKey[:10] + "..." + apiKey[len(apiKey)-4:]
-			}
-			log.Info("API key loaded for Gemini", zap.String("key_preview", masked))
+			log.Info("LLM provider ready", zap.String("provider", llm.Name()))
 		}
 
 		listingsRepo := postgres.NewListingRepo(pool)
 		imagesRepo := postgres.NewImageRepo(pool)
+		chatSvc = service.NewChatService(postgres.NewChatRepo(pool), listingsRepo, clock.Real{})
 
 		agentSvc := service.NewAgentServiceFull(
-			apiKey,
+			llm,
 			listingsRepo,
 			imagesRepo,
 			nil,
 			cfg.PresignExpiry,
 			log,
 		)
+		agentSvc.EnableSavedSearches(service.NewSavedSearchService(postgres.NewSavedSearchRepo(pool)))
 
-		go startAgentWorker(bus, agentSvc, log)
-		go startChatWorker(bus, agentSvc, log)
+		// With a shared broker the standalone cmd/worker answers requests;
+		// running workers here too would answer every request twice.
+		if cfg.PubSubDriver == pubsub.DriverPostgres {
+			log.Info("agent & chat requests are handled by cmd/worker")
+		} else {
+			go startAgentWorker(bus, agentSvc, log)
+			go startChatWorker(bus, agentSvc, log)
+			go startResetWorker(bus, agentSvc, log)
 
-		log.Info("agent & chat workers started")
+			log.Info("agent & chat workers started")
+		}
 	}
 
+	// hub
+	hub := ws.NewHub(bus, chatSvc, notifier, log)
+	go hub.Run()
+	log.Info("websocket hub started")
+
 	mux := http.NewServeMux()
 	mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
-		serveWs(hub, w, r, []byte(cfg.JWTSecret), log)
+		serveWs(hub, w, r, tokens, revocations, log)
 	})
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
 	})
+	mux.HandleFunc("/stats/pubsub", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"dropped": bus.Dropped()})
+	})
 
 	addr := ":" + cfg.WSPort
 	srv := &http.Server{Addr: addr, Handler: mux}
@@ -103,7 +140,7 @@ func main() {
 
 // --- AUTHENTICATED WEBSOCKET UPGRADE ---
 
-func serveWs(hub *ws.Hub, w http.ResponseWriter, r *http.Request, jwtSecret []byte, log *zap.Logger) {
+func serveWs(hub *ws.Hub, w http.ResponseWriter, r *http.Request, tokens *jwt.Signer, revocations ws.RevocationList, log *zap.Logger) {
 	tokenStr := r.URL.Query().Get("token")
 	if tokenStr == "" {
 		http.Error(w, "missing token", http.StatusUnauthorized)
@@ -111,7 +148,7 @@ func serveWs(hub *ws.Hub, w http.ResponseWriter, r *http.Request, jwtSecret []by
 	}
 
 	// Validate JWT
-	claims, err := jwt.ParseAndValidate(string(jwtSecret), tokenStr)
+	claims, err := ws.ValidateToken(r.Context(), tokenStr, tokens, revocations)
 	if err != nil {
 		log.Warn("WS auth failed", zap.Error(err))
 		http.Error(w, "invalid token", http.StatusUnauthorized)
@@ -141,21 +178,38 @@ func serveWs(hub *ws.Hub, w http.ResponseWriter, r *http.Request, jwtSecret []by
 
 // --- WORKERS ---
 
-func startAgentWorker(bus *pubsub.Bus, agentService *service.AgentService, log *zap.Logger) {
+func startAgentWorker(bus pubsub.Broker, agentService *service.AgentService, log *zap.Logger) {
 	ch := bus.Subscribe("agent.request")
 	for msg := range ch {
 		go handleAgentRequest(context.Background(), msg, agentService, bus, log)
 	}
 }
 
-func startChatWorker(bus *pubsub.Bus, agentService *service.AgentService, log *zap.Logger) {
+func startChatWorker(bus pubsub.Broker, agentService *service.AgentService, log *zap.Logger) {
 	ch := bus.Subscribe("chat.request")
 	for msg := range ch {
 		go handleChatRequest(context.Background(), msg, agentService, bus, log)
 	}
 }
 
-func handleAgentRequest(ctx context.Context, msg pubsub.Message, agentService *service.AgentService, bus *pubsub.Bus, log *zap.Logger) {
+func startResetWorker(bus pubsub.Broker, agentService *service.AgentService, log *zap.Logger) {
+	ch := bus.Subscribe("agent.reset")
+	for msg := range ch {
+		handleConversationReset(msg, agentService, log)
+	}
+}
+
+func handleConversationReset(msg pubsub.Message, agentService *service.AgentService, log *zap.Logger) {
+	req, ok := msg.Payload.(pubsub.ConversationReset)
+	if !ok {
+		log.Error("invalid conversation reset payload")
+		return
+	}
+	agentService.ResetConversation(service.ConversationKey{UserID: req.UserID, SessionID: req.SessionID})
+	log.Debug("conversation reset", zap.String("userId", req.UserID), zap.String("sessionId", req.SessionID))
+}
+
+func handleAgentRequest(ctx context.Context, msg pubsub.Message, agentService *service.AgentService, bus pubsub.Broker, log *zap.Logger) {
 	req, ok := msg.Payload.(pubsub.AgentRequest)
 	if !ok {
 		log.Error("invalid agent request payload")
@@ -168,7 +222,21 @@ func handleAgentRequest(ctx context.Context, msg pubsub.Message, agentService *s
 		zap.String("query", req.Query),
 	)
 
-	answer, results, err := agentService.ProcessQuery(ctx, req.Query)
+	conv := service.ConversationKey{UserID: req.UserID, SessionID: req.SessionID}
+	var (
+		answer  string
+		results []pubsub.ListingInfo
+		err     error
+	)
+	if req.Stream {
+		seq := 0
+		answer, results, err = agentService.ProcessQueryStream(ctx, conv, req.Query, func(d service.AnswerDelta) {
+			bus.Publish("agent.response.delta", pubsub.ResponseDelta{UserID: req.UserID, RequestID: req.RequestID, Seq: seq, Delta: d.Text, Replace: d.Replace})
+			seq++
+		})
+	} else {
+		answer, results, err = agentService.ProcessQuery(ctx, conv, req.Query)
+	}
 	if err != nil {
 		log.Error("failed to process query", zap.Error(err), zap.String("requestId", req.RequestID))
 		answer = "Sorry, I encountered an error processing your request. Please try again."
@@ -180,10 +248,11 @@ func handleAgentRequest(ctx context.Context, msg pubsub.Message, agentService *s
 		RequestID: req.RequestID,
 		Answer:    answer,
 		Results:   results,
+		Streamed:  req.Stream,
 	})
 }
 
-func handleChatRequest(ctx context.Context, msg pubsub.Message, agent *service.AgentService, bus *pubsub.Bus, log *zap.Logger) {
+func handleChatRequest(ctx context.Context, msg pubsub.Message, agent *service.AgentService, bus pubsub.Broker, log *zap.Logger) {
 	req, ok := msg.Payload.(pubsub.ChatRequest)
 	if !ok {
 		log.Error("invalid chat request payload")
@@ -196,7 +265,21 @@ func handleChatRequest(ctx context.Context, msg pubsub.Message, agent *service.A
 		zap.String("text", req.Text),
 	)
 
-	answer, results, err := agent.ProcessChat(ctx, req.Text)
+	conv := service.ConversationKey{UserID: req.UserID, SessionID: req.SessionID}
+	var (
+		answer  string
+		results []pubsub.ListingInfo
+		err     error
+	)
+	if req.Stream {
+		seq := 0
+		answer, results, err = agent.ProcessChatStream(ctx, conv, req.Text, func(d service.AnswerDelta) {
+			bus.Publish("chat.response.delta", pubsub.ResponseDelta{UserID: req.UserID, RequestID: req.RequestID, Seq: seq, Delta: d.Text, Replace: d.Replace})
+			seq++
+		})
+	} else {
+		answer, results, err = agent.ProcessChat(ctx, conv, req.Text)
+	}
 	if err != nil {
 		log.Error("chat processing failed", zap.Error(err), zap.String("requestId", req.RequestID))
 		answer = "Sorry, I had trouble with that. Try rephrasing?"
@@ -208,5 +291,6 @@ func handleChatRequest(ctx context.Context, msg pubsub.Message, agent *service.A
 		RequestID: req.RequestID,
 		Answer:    answer,
 		Results:   results,
+		Streamed:  req.Stream,
 	})
 }
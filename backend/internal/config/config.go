@@ -2,8 +2,13 @@ package config
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/jwt"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/mail"
 )
 
 type Config struct {
@@ -19,6 +24,106 @@ type Config struct {
 	S3Endpoint    string `mapstructure:"S3_ENDPOINT"`
 	S3PathStyle   bool   `mapstructure:"S3_PATH_STYLE"`
 	PresignExpiry int    `mapstructure:"PRESIGN_EXPIRY"`
+	PubSubDriver  string `mapstructure:"PUBSUB_DRIVER"` // "memory" or "postgres" (needed when cmd/worker runs separately)
+	LLMProvider   string `mapstructure:"LLM_PROVIDER"`  // "gemini", "openai" or "offline"
+	LLMModel      string `mapstructure:"LLM_MODEL"`     // provider default when empty
+	LLMBaseURL    string `mapstructure:"LLM_BASE_URL"`  // OpenAI-compatible endpoint, e.g. http://localhost:11434/v1
+
+	ReserveHours int `mapstructure:"RESERVATION_HOURS"`  // how long an accepted offer holds a listing
+	RefreshDays  int `mapstructure:"REFRESH_TOKEN_DAYS"` // how long a session lasts without a refresh
+
+	// Failed sign-ins back off and then lock the account for
+	// LOGIN_LOCKOUT_MINUTES; an IP only gets locked, after more failures.
+	LoginMaxFailures   int `mapstructure:"LOGIN_MAX_FAILURES"`
+	LoginIPMaxFailures int `mapstructure:"LOGIN_IP_MAX_FAILURES"`
+	LoginLockoutMin    int `mapstructure:"LOGIN_LOCKOUT_MINUTES"`
+
+	// TRUSTED_PROXIES (comma separated IPs or CIDRs) may set
+	// X-Forwarded-For; with none, the client IP is the connection's peer.
+	TrustedProxies string `mapstructure:"TRUSTED_PROXIES"`
+
+	// JWT_SECRET signs as JWT_KEY_ID; JWT_PREVIOUS_KEYS ("kid:secret,...")
+	// are still accepted so tokens survive a key rotation until they expire.
+	JWTKeyID     string `mapstructure:"JWT_KEY_ID"`
+	JWTOldKeys   string `mapstructure:"JWT_PREVIOUS_KEYS"`
+	JWTIssuer    string `mapstructure:"JWT_ISSUER"`
+	JWTAudience  string `mapstructure:"JWT_AUDIENCE"`
+	JWTLeewaySec int    `mapstructure:"JWT_LEEWAY_SECONDS"` // clock skew allowed on token times
+
+	// Sign-up is limited to ALLOWED_EMAIL_DOMAINS (comma separated, empty
+	// allows any) and must be confirmed through a link to VERIFY_URL+token.
+	EmailVerify    bool   `mapstructure:"EMAIL_VERIFICATION"`
+	AllowedDomains string `mapstructure:"ALLOWED_EMAIL_DOMAINS"`
+	VerifyURL      string `mapstructure:"VERIFY_URL"`
+	ResetURL       string `mapstructure:"RESET_URL"`   // password reset links are RESET_URL+token
+	MailDriver     string `mapstructure:"MAIL_DRIVER"` // "log" (writes to MAIL_DIR if set) or "smtp"
+	MailFrom       string `mapstructure:"MAIL_FROM"`
+	MailDir        string `mapstructure:"MAIL_DIR"`
+	SMTPHost       string `mapstructure:"SMTP_HOST"`
+	SMTPPort       int    `mapstructure:"SMTP_PORT"`
+	SMTPUsername   string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword   string `mapstructure:"SMTP_PASSWORD"`
+}
+
+// LLMKey returns the API key for the configured provider. Gemini keeps
+// accepting OPENAI_API_KEY, which older setups used for the Gemini key.
+func (c Config) LLMKey() string {
+	if c.LLMProvider == "openai" {
+		return c.OpenAIKey
+	}
+	if c.GeminiKey != "" {
+		return c.GeminiKey
+	}
+	return c.OpenAIKey
+}
+
+// JWTOpts builds the token signer/verifier settings shared by every binary.
+func (c Config) JWTOpts() (jwt.Opts, error) {
+	previous, err := jwt.ParseKeys(c.JWTOldKeys)
+	if err != nil {
+		return jwt.Opts{}, err
+	}
+	return jwt.Opts{
+		Keys:     append([]jwt.Key{{ID: c.JWTKeyID, Secret: []byte(c.JWTSecret)}}, previous...),
+		Issuer:   c.JWTIssuer,
+		Audience: c.JWTAudience,
+		Leeway:   time.Duration(c.JWTLeewaySec) * time.Second,
+	}, nil
+}
+
+// EmailDomains splits ALLOWED_EMAIL_DOMAINS.
+func (c Config) EmailDomains() []string {
+	var out []string
+	for _, d := range strings.Split(c.AllowedDomains, ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// Proxies splits TRUSTED_PROXIES; nil when unset.
+func (c Config) Proxies() []string {
+	var out []string
+	for _, p := range strings.Split(c.TrustedProxies, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// MailOpts builds the outgoing mail settings.
+func (c Config) MailOpts() mail.Opts {
+	return mail.Opts{
+		Driver:   c.MailDriver,
+		From:     c.MailFrom,
+		Host:     c.SMTPHost,
+		Port:     c.SMTPPort,
+		Username: c.SMTPUsername,
+		Password: c.SMTPPassword,
+		Dir:      c.MailDir,
+	}
 }
 
 func Load() (Config, error) {
@@ -33,6 +138,32 @@ func Load() (Config, error) {
 	v.SetDefault("WS_PORT", "8081")
 	v.SetDefault("PRESIGN_EXPIRY", 15)
 	v.SetDefault("S3_PATH_STYLE", false)
+	v.SetDefault("PUBSUB_DRIVER", "memory")
+	v.SetDefault("LLM_PROVIDER", "gemini")
+	v.SetDefault("LLM_MODEL", "")
+	v.SetDefault("LLM_BASE_URL", "")
+	v.SetDefault("RESERVATION_HOURS", 48)
+	v.SetDefault("REFRESH_TOKEN_DAYS", 30)
+	v.SetDefault("LOGIN_MAX_FAILURES", 5)
+	v.SetDefault("LOGIN_IP_MAX_FAILURES", 50)
+	v.SetDefault("LOGIN_LOCKOUT_MINUTES", 15)
+	v.SetDefault("TRUSTED_PROXIES", "")
+	v.SetDefault("JWT_KEY_ID", "k1")
+	v.SetDefault("JWT_PREVIOUS_KEYS", "")
+	v.SetDefault("JWT_ISSUER", "campushub")
+	v.SetDefault("JWT_AUDIENCE", "campushub")
+	v.SetDefault("JWT_LEEWAY_SECONDS", 30)
+	v.SetDefault("EMAIL_VERIFICATION", true)
+	v.SetDefault("ALLOWED_EMAIL_DOMAINS", "sjsu.edu")
+	v.SetDefault("VERIFY_URL", "http://localhost:5173/verify?token=")
+	v.SetDefault("RESET_URL", "http://localhost:5173/reset-password?token=")
+	v.SetDefault("MAIL_DRIVER", "log")
+	v.SetDefault("MAIL_FROM", "no-reply@campushub.local")
+	v.SetDefault("MAIL_DIR", "")
+	v.SetDefault("SMTP_HOST", "")
+	v.SetDefault("SMTP_PORT", 587)
+	v.SetDefault("SMTP_USERNAME", "")
+	v.SetDefault("SMTP_PASSWORD", "")
 
 	var c Config
 	if err := v.Unmarshal(&c); err != nil {
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit actions.
+const (
+	AuditLoginLocked   = "login.locked"
+	AuditLoginUnlocked = "login.unlocked"
+)
+
+type AuditEntry struct {
+	ID        uuid.UUID  `json:"id"`
+	Action    string     `json:"action"`
+	ActorID   *uuid.UUID `json:"actorId,omitempty"` // nil: done by the system
+	Subject   string     `json:"subject"`           // e.g. "account:alice@sjsu.edu" or "ip:10.0.0.5"
+	Detail    string     `json:"detail,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// LoginAttempts counts consecutive failed sign-ins for an account or an IP.
+type LoginAttempts struct {
+	Failures     int
+	LastFailedAt time.Time
+	LockedUntil  *time.Time
+}
@@ -1 +1,30 @@
 package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Conversation struct {
+	ID           uuid.UUID   `json:"id"`
+	ListingID    *uuid.UUID  `json:"listingId,omitempty"`
+	CreatedBy    uuid.UUID   `json:"createdBy"`
+	Participants []uuid.UUID `json:"participants"`
+	LastMessage  *Message    `json:"lastMessage,omitempty"`
+	UnreadCount  int         `json:"unreadCount,omitempty"`
+	CreatedAt    time.Time   `json:"createdAt"`
+}
+
+type Message struct {
+	ID             uuid.UUID `json:"id"`
+	ConversationID uuid.UUID `json:"conversationId"`
+	SenderID       uuid.UUID `json:"senderId"`
+	Body           string    `json:"body"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+type UnreadCount struct {
+	ConversationID uuid.UUID `json:"conversationId"`
+	Unread         int       `json:"unread"`
+}
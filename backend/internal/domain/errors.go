@@ -1 +1,11 @@
 package domain
+
+import "errors"
+
+var (
+	ErrNotFound  = errors.New("not found")
+	ErrForbidden = errors.New("forbidden")
+	// ErrConflict means the row changed (or a unique rule was hit) between
+	// reading it and writing it.
+	ErrConflict = errors.New("conflict")
+)
@@ -9,11 +9,24 @@ import (
 type ListingStatus string
 
 const (
-	ListingActive  ListingStatus = "active"
-	ListingSold    ListingStatus = "sold"
-	ListingRemoved ListingStatus = "removed"
+	ListingActive   ListingStatus = "active"
+	ListingReserved ListingStatus = "reserved" // an offer was accepted; held for the buyer
+	ListingSold     ListingStatus = "sold"
+	ListingRemoved  ListingStatus = "removed"
 )
 
+// ListingStatusChange is one entry in a listing's status history. ChangedBy
+// is nil for changes the system made, like an expired reservation.
+type ListingStatusChange struct {
+	ID         uuid.UUID     `json:"id"`
+	ListingID  uuid.UUID     `json:"listingId"`
+	FromStatus ListingStatus `json:"fromStatus"`
+	ToStatus   ListingStatus `json:"toStatus"`
+	ChangedBy  *uuid.UUID    `json:"changedBy,omitempty"`
+	Reason     string        `json:"reason,omitempty"`
+	CreatedAt  time.Time     `json:"createdAt"`
+}
+
 type Condition string
 
 const (
@@ -34,4 +47,39 @@ type Listing struct {
 	Status      ListingStatus `json:"status"`
 	CreatedAt   time.Time     `json:"createdAt"`
 	UpdatedAt   time.Time     `json:"updatedAt"`
+
+	// ReservedBy is the buyer holding a reserved listing (kept once it is
+	// sold to them); ReservedUntil is when an unsold reservation lapses.
+	ReservedBy    *uuid.UUID `json:"reservedBy,omitempty"`
+	ReservedUntil *time.Time `json:"reservedUntil,omitempty"`
+
+	// Set by full-text search: matched terms wrapped in <mark>.
+	Highlight *ListingHighlight `json:"highlight,omitempty"`
+}
+
+type ListingHighlight struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// ListingFacets counts the listings matching a query per value of each
+// filterable field. Every facet ignores its own filter, so the counts show
+// what picking another value would return.
+type ListingFacets struct {
+	Category  []FacetCount       `json:"category"`
+	Condition []FacetCount       `json:"condition"`
+	Price     []PriceBucketCount `json:"price"`
+	Status    []FacetCount       `json:"status"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// PriceBucketCount covers Min <= price < Max; the last bucket has no Max.
+type PriceBucketCount struct {
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max,omitempty"`
+	Count int      `json:"count"`
 }
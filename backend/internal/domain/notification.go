@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification kinds.
+const (
+	NotifyChatMessage  = "chat.message"  // a message arrived while the user was offline
+	NotifyOffer        = "offer"         // an offer on the user's listing, or a reply to theirs
+	NotifyReportStatus = "report.status" // a report the user filed was resolved or dismissed
+)
+
+// Notification is an in-app message for one user. Data holds kind-specific
+// ids (listingId, offerId, ...) for the client to link to.
+type Notification struct {
+	ID        uuid.UUID       `json:"id"`
+	UserID    uuid.UUID       `json:"userId"`
+	Kind      string          `json:"kind"`
+	Title     string          `json:"title"`
+	Body      string          `json:"body,omitempty"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	ReadAt    *time.Time      `json:"readAt,omitempty"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OfferStatus string
+
+const (
+	OfferPending   OfferStatus = "pending"   // waiting for the seller
+	OfferCountered OfferStatus = "countered" // the seller proposed Amount; waiting for the buyer
+	OfferAccepted  OfferStatus = "accepted"
+	OfferRejected  OfferStatus = "rejected"
+	OfferWithdrawn OfferStatus = "withdrawn"
+	OfferExpired   OfferStatus = "expired"   // accepted, but the reservation ran out unsold
+	OfferCancelled OfferStatus = "cancelled" // accepted, but the seller or an admin ended the reservation
+)
+
+// Active offers are still being negotiated. A buyer has at most one per
+// listing.
+func (s OfferStatus) Active() bool { return s == OfferPending || s == OfferCountered }
+
+// Offer is a buyer's price proposal on a listing. Counters replace Amount and
+// Message, so the offer always shows the latest proposal.
+type Offer struct {
+	ID        uuid.UUID   `json:"id"`
+	ListingID uuid.UUID   `json:"listingId"`
+	BuyerID   uuid.UUID   `json:"buyerId"`
+	SellerID  uuid.UUID   `json:"sellerId"`
+	Amount    float64     `json:"amount"`
+	Message   string      `json:"message,omitempty"`
+	Status    OfferStatus `json:"status"`
+	CreatedAt time.Time   `json:"createdAt"`
+	UpdatedAt time.Time   `json:"updatedAt"`
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Review is a buyer's rating of the seller for one sold listing. The seller
+// may answer once with Reply.
+type Review struct {
+	ID        uuid.UUID  `json:"id"`
+	ListingID uuid.UUID  `json:"listingId"`
+	SellerID  uuid.UUID  `json:"sellerId"`
+	BuyerID   uuid.UUID  `json:"buyerId"`
+	Rating    int        `json:"rating"` // 1..5
+	Text      string     `json:"text,omitempty"`
+	Reply     string     `json:"reply,omitempty"`
+	RepliedAt *time.Time `json:"repliedAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// RatingSummary aggregates the reviews a seller received. Average is 0 when
+// Count is 0.
+type RatingSummary struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SavedSearch is a listing query a user wants to hear about: new active
+// listings that match it are recorded and pushed to the user.
+type SavedSearch struct {
+	ID            uuid.UUID `json:"id"`
+	UserID        uuid.UUID `json:"userId"`
+	Name          string    `json:"name"`
+	Q             string    `json:"q,omitempty"`
+	Category      string    `json:"category,omitempty"`
+	Condition     string    `json:"condition,omitempty"`
+	PriceMin      *float64  `json:"priceMin,omitempty"`
+	PriceMax      *float64  `json:"priceMax,omitempty"`
+	LastCheckedAt time.Time `json:"lastCheckedAt"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// SavedSearchMatch is a new listing found for a saved search.
+type SavedSearchMatch struct {
+	SearchID   uuid.UUID `json:"searchId"`
+	SearchName string    `json:"searchName"`
+	Listing    Listing   `json:"listing"`
+	MatchedAt  time.Time `json:"matchedAt"`
+}
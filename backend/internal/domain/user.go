@@ -11,5 +11,25 @@ type User struct {
 	Name      string    `json:"name"`
 	Email     string    `json:"email"`
 	Role      string    `json:"role"`
-	CreatedAt time.Time `json:"createdAt"`
+	Bio       string    `json:"bio"`
+	AvatarKey string    `json:"avatarKey,omitempty"`
+	// EmailVerified is false until the user follows the link mailed at
+	// sign-up; unverified users cannot create listings.
+	EmailVerified bool      `json:"emailVerified"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// PublicProfile is what anyone may see about a user. Email is only filled in
+// for admins and for the user themselves; AvatarURL is signed by the handler.
+type PublicProfile struct {
+	ID             uuid.UUID     `json:"id"`
+	Name           string        `json:"name"`
+	Email          string        `json:"email,omitempty"`
+	Bio            string        `json:"bio"`
+	AvatarKey      string        `json:"-"`
+	AvatarURL      string        `json:"avatarUrl,omitempty"`
+	MemberSince    time.Time     `json:"memberSince"`
+	ActiveListings int           `json:"activeListings"`
+	SoldCount      int           `json:"soldCount"`
+	Rating         RatingSummary `json:"rating"`
 }
@@ -2,73 +2,155 @@ package jwt
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/clock"
 )
 
-type Signer struct {
+// Key is one HMAC-SHA256 key, named in token headers by its kid.
+type Key struct {
+	ID     string
 	Secret []byte
 }
 
-func (s *Signer) Verify(tokenStr string) (any, any) {
-	panic("unimplemented")
+// Opts configures a Signer. Keys[0] signs new tokens; every key verifies, so
+// a rotated-out key can stay listed until the tokens it signed expire.
+type Opts struct {
+	Keys     []Key
+	Issuer   string
+	Audience string
+	Leeway   time.Duration // clock skew tolerated on exp, nbf and iat
+	Clock    clock.Clock   // stamps iat and checks times; nil is the real clock
+}
+
+// Signer issues access tokens and is the one place they are verified.
+type Signer struct {
+	keys     map[string][]byte
+	signing  Key
+	issuer   string
+	audience string
+	clk      clock.Clock
+	parser   *jwt.Parser
 }
 
 type Claims struct {
 	Sub   string `json:"sub"`   // user ID
 	Email string `json:"email"` // email
 	Role  string `json:"role"`  // buyer/seller/admin
+	Sid   string `json:"sid"`   // session the token was issued for; jti is RegisteredClaims.ID
 	jwt.RegisteredClaims
 }
 
-// ParseAndValidate validates a JWT and returns Claims.
-func ParseAndValidate(secret string, tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(t *jwt.Token) (interface{}, error) {
-		// Ensure HMAC SHA256
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
+func New(o Opts) (*Signer, error) {
+	if len(o.Keys) == 0 {
+		return nil, errors.New("jwt: no keys")
+	}
+	keys := make(map[string][]byte, len(o.Keys))
+	for _, k := range o.Keys {
+		if k.ID == "" || len(k.Secret) == 0 {
+			return nil, errors.New("jwt: every key needs an id and a secret")
+		}
+		if _, dup := keys[k.ID]; dup {
+			return nil, fmt.Errorf("jwt: duplicate key id %q", k.ID)
+		}
+		keys[k.ID] = k.Secret
+	}
+	if o.Clock == nil {
+		o.Clock = clock.Real{}
+	}
+	popts := []jwt.ParserOption{
+		jwt.WithTimeFunc(o.Clock.Now),
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(o.Leeway),
+	}
+	if o.Issuer != "" {
+		popts = append(popts, jwt.WithIssuer(o.Issuer))
+	}
+	if o.Audience != "" {
+		popts = append(popts, jwt.WithAudience(o.Audience))
+	}
+	return &Signer{
+		keys:     keys,
+		signing:  o.Keys[0],
+		issuer:   o.Issuer,
+		audience: o.Audience,
+		clk:      o.Clock,
+		parser:   jwt.NewParser(popts...),
+	}, nil
+}
+
+// ParseKeys reads "kid:secret" pairs separated by commas, as used for keys
+// that still verify but no longer sign.
+func ParseKeys(spec string) ([]Key, error) {
+	var keys []Key
+	for i, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, secret, ok := strings.Cut(part, ":")
+		if !ok || id == "" || secret == "" {
+			return nil, fmt.Errorf("jwt: key %d is not kid:secret", i+1)
+		}
+		keys = append(keys, Key{ID: id, Secret: []byte(secret)})
+	}
+	return keys, nil
+}
+
+// SignJWT issues an access token for user. jti names this token on the
+// revocation list; sid is the session it belongs to.
+func (s *Signer) SignJWT(user domain.User, sid, jti string, exp time.Time) (string, error) {
+	claims := Claims{
+		Sub:   user.ID.String(),
+		Email: user.Email,
+		Role:  user.Role,
+		Sid:   sid,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Issuer:    s.issuer,
+			ExpiresAt: jwt.NewNumericDate(exp),
+			IssuedAt:  jwt.NewNumericDate(s.clk.Now()),
+		},
+	}
+	if s.audience != "" {
+		claims.Audience = jwt.ClaimStrings{s.audience}
+	}
+	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	t.Header["kid"] = s.signing.ID
+	return t.SignedString(s.signing.Secret)
+}
+
+// Verify checks the token's algorithm, key, issuer, audience and times, and
+// that it names a user, a role and a jti.
+func (s *Signer) Verify(tokenStr string) (*Claims, error) {
+	token, err := s.parser.ParseWithClaims(tokenStr, &Claims{}, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		secret, ok := s.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
 		}
-		return []byte(secret), nil
+		return secret, nil
 	})
 	if err != nil {
 		return nil, err
 	}
-
-	// Cast claims
 	claims, ok := token.Claims.(*Claims)
 	if !ok || !token.Valid {
 		return nil, errors.New("invalid token")
 	}
-
-	// Verify exp explicitly
-	if claims.ExpiresAt != nil {
-		if time.Now().After(claims.ExpiresAt.Time) {
-			return nil, errors.New("token expired")
-		}
-	}
-
-	// Ensure required fields
-	if claims.Sub == "" {
+	switch {
+	case claims.Sub == "":
 		return nil, errors.New("missing sub")
-	}
-	if claims.Role == "" {
+	case claims.Role == "":
 		return nil, errors.New("missing role")
+	case claims.ID == "":
+		return nil, errors.New("missing jti")
 	}
-
 	return claims, nil
 }
-
-func New(secret []byte) *Signer { return &Signer{Secret: secret} }
-
-func (s *Signer) SignJWT(user domain.User, exp time.Time) (string, error) {
-	claims := jwt.MapClaims{
-		"sub":   user.ID,
-		"email": user.Email,
-		"role":  user.Role,
-		"exp":   exp.Unix(),
-	}
-	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return t.SignedString(s.Secret)
-}
@@ -0,0 +1,152 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+)
+
+type fixedClock struct{ now time.Time }
+
+func (c *fixedClock) Now() time.Time { return c.now }
+
+var (
+	currentKey  = Key{ID: "k2", Secret: []byte("current-secret")}
+	previousKey = Key{ID: "k1", Secret: []byte("previous-secret")}
+)
+
+const (
+	testIssuer   = "campushub"
+	testAudience = "campushub-api"
+	testLeeway   = 30 * time.Second
+)
+
+func newTestSigner(t *testing.T, clk *fixedClock, keys ...Key) *Signer {
+	t.Helper()
+	s, err := New(Opts{Keys: keys, Issuer: testIssuer, Audience: testAudience, Leeway: testLeeway, Clock: clk})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s
+}
+
+// validClaims are what SignJWT would issue at now, good for an hour.
+func validClaims(now time.Time) Claims {
+	return Claims{
+		Sub:  uuid.NewString(),
+		Role: "buyer",
+		Sid:  uuid.NewString(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    testIssuer,
+			Audience:  jwt.ClaimStrings{testAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, key Key, c Claims) string {
+	t.Helper()
+	tok := jwt.NewWithClaims(method, c)
+	tok.Header["kid"] = key.ID
+	var secret any = key.Secret
+	if method == jwt.SigningMethodNone {
+		secret = jwt.UnsafeAllowNoneSignatureType
+	}
+	s, err := tok.SignedString(secret)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return s
+}
+
+func TestVerify(t *testing.T) {
+	issued := time.Date(2025, 10, 20, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		method  jwt.SigningMethod
+		key     Key
+		edit    func(*Claims)
+		at      time.Time // when Verify runs; zero is issued
+		wantErr bool
+	}{
+		{name: "valid", method: jwt.SigningMethodHS256, key: currentKey},
+		{name: "alg none", method: jwt.SigningMethodNone, key: currentKey, wantErr: true},
+		{name: "alg HS512", method: jwt.SigningMethodHS512, key: currentKey, wantErr: true},
+		{name: "unknown kid", method: jwt.SigningMethodHS256, key: Key{ID: "k9", Secret: currentKey.Secret}, wantErr: true},
+		{name: "kid with the wrong secret", method: jwt.SigningMethodHS256, key: Key{ID: currentKey.ID, Secret: previousKey.Secret}, wantErr: true},
+		{name: "rotated previous key", method: jwt.SigningMethodHS256, key: previousKey},
+		{name: "wrong issuer", method: jwt.SigningMethodHS256, key: currentKey,
+			edit: func(c *Claims) { c.Issuer = "someone-else" }, wantErr: true},
+		{name: "wrong audience", method: jwt.SigningMethodHS256, key: currentKey,
+			edit: func(c *Claims) { c.Audience = jwt.ClaimStrings{"other-api"} }, wantErr: true},
+		{name: "expired inside leeway", method: jwt.SigningMethodHS256, key: currentKey,
+			at: issued.Add(time.Hour + testLeeway - time.Second)},
+		{name: "expired outside leeway", method: jwt.SigningMethodHS256, key: currentKey,
+			at: issued.Add(time.Hour + testLeeway + time.Second), wantErr: true},
+		{name: "no exp", method: jwt.SigningMethodHS256, key: currentKey,
+			edit: func(c *Claims) { c.ExpiresAt = nil }, wantErr: true},
+		{name: "missing sub", method: jwt.SigningMethodHS256, key: currentKey,
+			edit: func(c *Claims) { c.Sub = "" }, wantErr: true},
+		{name: "missing role", method: jwt.SigningMethodHS256, key: currentKey,
+			edit: func(c *Claims) { c.Role = "" }, wantErr: true},
+		{name: "missing jti", method: jwt.SigningMethodHS256, key: currentKey,
+			edit: func(c *Claims) { c.ID = "" }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := &fixedClock{now: issued}
+			s := newTestSigner(t, clk, currentKey, previousKey)
+
+			c := validClaims(issued)
+			if tt.edit != nil {
+				tt.edit(&c)
+			}
+			tok := sign(t, tt.method, tt.key, c)
+			if !tt.at.IsZero() {
+				clk.now = tt.at
+			}
+
+			got, err := s.Verify(tok)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.Sub != c.Sub {
+				t.Errorf("sub = %q, want %q", got.Sub, c.Sub)
+			}
+		})
+	}
+}
+
+func TestSignJWTUsesClock(t *testing.T) {
+	issued := time.Date(2025, 10, 20, 18, 0, 0, 0, time.UTC)
+	clk := &fixedClock{now: issued}
+	s := newTestSigner(t, clk, currentKey, previousKey)
+	user := domain.User{ID: uuid.New(), Email: "alex@sjsu.edu", Role: "seller"}
+
+	tok, err := s.SignJWT(user, uuid.NewString(), uuid.NewString(), issued.Add(15*time.Minute))
+	if err != nil {
+		t.Fatalf("SignJWT: %v", err)
+	}
+	c, err := s.Verify(tok)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if !c.IssuedAt.Time.Equal(issued) {
+		t.Errorf("iat = %v, want the clock's %v", c.IssuedAt.Time, issued)
+	}
+	if c.Sub != user.ID.String() || c.Role != user.Role {
+		t.Errorf("claims = %+v, want sub %s role %s", c, user.ID, user.Role)
+	}
+
+	// Signed with the current key, so dropping the previous one still verifies.
+	if _, err := newTestSigner(t, clk, currentKey).Verify(tok); err != nil {
+		t.Errorf("Verify without the previous key: %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends email. SMTP delivers it; Log is a local stand-in.
+type Mailer interface {
+	Send(ctx context.Context, m Message) error
+}
+
+// Drivers accepted by New.
+const (
+	DriverLog  = "log"
+	DriverSMTP = "smtp"
+)
+
+type Opts struct {
+	Driver   string // DriverLog (default) or DriverSMTP
+	From     string
+	Host     string // SMTP only
+	Port     int
+	Username string
+	Password string
+	Dir      string // log only: also write each message to a file here
+}
+
+func New(o Opts, logger *zap.Logger) (Mailer, error) {
+	switch o.Driver {
+	case "", DriverLog:
+		return NewLog(o.From, o.Dir, logger), nil
+	case DriverSMTP:
+		if o.Host == "" || o.From == "" {
+			return nil, fmt.Errorf("mail: smtp needs a host and a from address")
+		}
+		return NewSMTP(o), nil
+	default:
+		return nil, fmt.Errorf("mail: unknown driver %q", o.Driver)
+	}
+}
+
+// SMTP sends through a relay with PLAIN auth (STARTTLS when offered).
+type SMTP struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewSMTP(o Opts) *SMTP {
+	port := o.Port
+	if port == 0 {
+		port = 587
+	}
+	s := &SMTP{addr: net.JoinHostPort(o.Host, fmt.Sprint(port)), from: o.From}
+	if o.Username != "" {
+		s.auth = smtp.PlainAuth("", o.Username, o.Password, o.Host)
+	}
+	return s
+}
+
+func (s *SMTP) Send(_ context.Context, m Message) error {
+	return smtp.SendMail(s.addr, s.auth, s.from, []string{m.To}, render(s.from, m))
+}
+
+// Log writes messages to the log, and to Dir as .eml files if set, so local
+// setups can follow verification links without a mail server.
+type Log struct {
+	from   string
+	dir    string
+	logger *zap.Logger
+}
+
+func NewLog(from, dir string, logger *zap.Logger) *Log {
+	if from == "" {
+		from = "campushub@localhost"
+	}
+	return &Log{from: from, dir: dir, logger: logger}
+}
+
+func (l *Log) Send(_ context.Context, m Message) error {
+	l.logger.Info("mail (not sent)", zap.String("to", m.To), zap.String("subject", m.Subject), zap.String("body", m.Body))
+	if l.dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(l.dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405"), uuid.NewString()[:8])
+	return os.WriteFile(filepath.Join(l.dir, name), render(l.from, m), 0o644)
+}
+
+func render(from string, m Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", m.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", m.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(m.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,44 @@
+package pubsub
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// Broker is implemented by every pub/sub backend. Subscribers receive the
+// payload with its concrete type (e.g. AgentRequest), whichever backend
+// carried it.
+type Broker interface {
+	Subscribe(topic string) chan Message
+	SubscribeWith(topic string, opts SubscribeOptions) chan Message
+	Publish(topic string, payload interface{})
+	Unsubscribe(topic string, ch chan Message)
+	// Dropped reports, per topic, messages a subscriber's policy gave up on.
+	Dropped() map[string]uint64
+	// Close releases the backend and closes every subscriber channel.
+	Close()
+}
+
+const (
+	DriverMemory   = "memory"
+	DriverPostgres = "postgres"
+)
+
+// NewBroker picks a backend by driver name. "memory" only reaches subscribers
+// in this process; "postgres" reaches every process sharing the database.
+func NewBroker(driver string, pool *pgxpool.Pool, logger *zap.Logger) (Broker, error) {
+	switch driver {
+	case "", DriverMemory:
+		return New(), nil
+	case DriverPostgres:
+		if pool == nil {
+			return nil, errors.New("postgres pub/sub needs a database connection")
+		}
+		return NewPGBroker(pool, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown pub/sub driver %q", driver)
+	}
+}
@@ -0,0 +1,259 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// maxNotifyPayload keeps us under Postgres' 8000-byte NOTIFY limit. Larger
+// payloads are parked in pubsub_payloads and the notification carries its id.
+const maxNotifyPayload = 7900
+
+// payloadTTL is how long parked payloads are kept for listeners to fetch.
+const payloadTTL = 10 * time.Minute
+
+// PGBroker carries messages between processes with Postgres LISTEN/NOTIFY.
+// Each topic is a notification channel; payloads travel as JSON and are
+// decoded back into their Go type (see payloadDecoders) before being handed
+// to local subscribers through an in-memory Bus.
+type PGBroker struct {
+	pool   *pgxpool.Pool
+	local  *Bus
+	logger *zap.Logger
+
+	mu     sync.Mutex
+	topics map[string]struct{}
+	wake   context.CancelFunc // interrupts the listener so it LISTENs to new topics
+
+	stop context.CancelFunc
+	done chan struct{}
+}
+
+var _ Broker = (*PGBroker)(nil)
+
+// envelope is the NOTIFY payload: the message itself, or a reference to it.
+type envelope struct {
+	Payload json.RawMessage `json:"p,omitempty"`
+	Ref     *uuid.UUID      `json:"ref,omitempty"`
+}
+
+// NewPGBroker starts listening on a dedicated pool connection. Call Close to
+// release it.
+func NewPGBroker(pool *pgxpool.Pool, logger *zap.Logger) *PGBroker {
+	ctx, stop := context.WithCancel(context.Background())
+	b := &PGBroker{
+		pool:   pool,
+		local:  New(),
+		logger: logger,
+		topics: make(map[string]struct{}),
+		stop:   stop,
+		done:   make(chan struct{}),
+	}
+	go b.listen(ctx)
+	return b
+}
+
+// Subscribe returns a channel for topic and makes sure this process LISTENs
+// to it. Messages published before the LISTEN takes effect are not seen.
+func (b *PGBroker) Subscribe(topic string) chan Message {
+	return b.SubscribeWith(topic, SubscribeOptions{})
+}
+
+// SubscribeWith is Subscribe with an explicit buffer and delivery policy; the
+// policy applies when handing notifications to this subscriber.
+func (b *PGBroker) SubscribeWith(topic string, opts SubscribeOptions) chan Message {
+	ch := b.local.SubscribeWith(topic, opts)
+
+	b.mu.Lock()
+	if _, ok := b.topics[topic]; !ok {
+		b.topics[topic] = struct{}{}
+		if b.wake != nil {
+			b.wake()
+		}
+	}
+	b.mu.Unlock()
+	return ch
+}
+
+func (b *PGBroker) Unsubscribe(topic string, ch chan Message) {
+	b.local.Unsubscribe(topic, ch)
+}
+
+func (b *PGBroker) Dropped() map[string]uint64 {
+	return b.local.Dropped()
+}
+
+// Publish sends payload to every process listening on topic, including this
+// one. Failures are logged. The caller never waits on subscribers: their
+// delivery policies apply on the listener side, when notifications arrive.
+func (b *PGBroker) Publish(topic string, payload interface{}) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := b.publish(ctx, topic, payload); err != nil {
+		b.logger.Error("pubsub publish failed", zap.String("topic", topic), zap.Error(err))
+	}
+}
+
+func (b *PGBroker) publish(ctx context.Context, topic string, payload interface{}) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	msg, err := json.Marshal(envelope{Payload: raw})
+	if err != nil {
+		return err
+	}
+
+	if len(msg) > maxNotifyPayload {
+		id := uuid.New()
+		if _, err := b.pool.Exec(ctx, `
+			INSERT INTO pubsub_payloads (id, topic, payload) VALUES ($1,$2,$3)`, id, topic, raw); err != nil {
+			return err
+		}
+		// Opportunistic cleanup; every listener has had ample time to fetch these.
+		if _, err := b.pool.Exec(ctx, `
+			DELETE FROM pubsub_payloads WHERE created_at < now() - make_interval(secs => $1)`, payloadTTL.Seconds()); err != nil {
+			b.logger.Warn("pubsub payload cleanup failed", zap.Error(err))
+		}
+		if msg, err = json.Marshal(envelope{Ref: &id}); err != nil {
+			return err
+		}
+	}
+
+	_, err = b.pool.Exec(ctx, `SELECT pg_notify($1, $2)`, topic, string(msg))
+	return err
+}
+
+// Close stops the listener, releases its connection and closes every
+// subscriber channel.
+func (b *PGBroker) Close() {
+	b.stop()
+	<-b.done
+	b.local.Close()
+}
+
+// listen keeps a LISTEN connection open, reconnecting with backoff.
+func (b *PGBroker) listen(ctx context.Context) {
+	defer close(b.done)
+
+	backoff := time.Second
+	for {
+		err := b.listenOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		b.logger.Warn("pubsub listener lost connection, retrying", zap.Duration("in", backoff), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, 30*time.Second)
+	}
+}
+
+func (b *PGBroker) listenOnce(ctx context.Context) error {
+	conn, err := b.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	listening := map[string]struct{}{}
+	for {
+		b.mu.Lock()
+		var todo []string
+		for t := range b.topics {
+			if _, ok := listening[t]; !ok {
+				todo = append(todo, t)
+			}
+		}
+		waitCtx, wake := context.WithCancel(ctx)
+		b.wake = wake
+		b.mu.Unlock()
+
+		for _, t := range todo {
+			if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{t}.Sanitize()); err != nil {
+				wake()
+				return err
+			}
+			listening[t] = struct{}{}
+		}
+
+		n, err := conn.Conn().WaitForNotification(waitCtx)
+		wake()
+		if err != nil {
+			if ctx.Err() == nil && waitCtx.Err() != nil {
+				continue // woken up for a new topic
+			}
+			return err
+		}
+		b.dispatch(ctx, n)
+	}
+}
+
+func (b *PGBroker) dispatch(ctx context.Context, n *pgconn.Notification) {
+	var env envelope
+	if err := json.Unmarshal([]byte(n.Payload), &env); err != nil {
+		b.logger.Error("pubsub: bad envelope", zap.String("topic", n.Channel), zap.Error(err))
+		return
+	}
+
+	raw := []byte(env.Payload)
+	if env.Ref != nil {
+		// Fetched on the pool, not the listener connection, which is mid-wait.
+		if err := b.pool.QueryRow(ctx, `SELECT payload FROM pubsub_payloads WHERE id=$1`, *env.Ref).Scan(&raw); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				err = errors.New("payload expired")
+			}
+			b.logger.Error("pubsub: fetch parked payload failed", zap.String("topic", n.Channel), zap.Error(err))
+			return
+		}
+	}
+
+	payload, err := decodePayload(n.Channel, raw)
+	if err != nil {
+		b.logger.Error("pubsub: decode payload failed", zap.String("topic", n.Channel), zap.Error(err))
+		return
+	}
+	b.local.Publish(n.Channel, payload)
+}
+
+// payloadDecoders restores the concrete payload type for each known topic so
+// subscribers can keep type-asserting (msg.Payload.(AgentRequest)).
+var payloadDecoders = map[string]func([]byte) (interface{}, error){
+	"agent.request":        decodeAs[AgentRequest],
+	"agent.response":       decodeAs[AgentResponse],
+	"agent.response.delta": decodeAs[ResponseDelta],
+	"agent.reset":          decodeAs[ConversationReset],
+	"chat.request":         decodeAs[ChatRequest],
+	"chat.response":        decodeAs[ChatResponse],
+	"chat.response.delta":  decodeAs[ResponseDelta],
+	"listing.watch":        decodeAs[ListingWatchEvent],
+	"search.match":         decodeAs[SavedSearchMatch],
+	"notification.new":     decodeAs[NotificationEvent],
+}
+
+func decodeAs[T any](raw []byte) (interface{}, error) {
+	var v T
+	err := json.Unmarshal(raw, &v)
+	return v, err
+}
+
+// decodePayload falls back to json.RawMessage for topics without a decoder.
+func decodePayload(topic string, raw []byte) (interface{}, error) {
+	if dec, ok := payloadDecoders[topic]; ok {
+		return dec(raw)
+	}
+	return json.RawMessage(raw), nil
+}
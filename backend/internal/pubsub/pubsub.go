@@ -2,6 +2,7 @@ package pubsub
 
 import (
 	"sync"
+	"time"
 )
 
 // Message represents a pub/sub message
@@ -10,59 +11,227 @@ type Message struct {
 	Payload interface{}
 }
 
-// Bus is an in-memory pub/sub event bus
+// Policy decides what Publish does when a subscriber's buffer is full.
+type Policy int
+
+const (
+	// DropNewest discards the message being published (the default).
+	DropNewest Policy = iota
+	// DropOldest discards the oldest buffered message to make room.
+	DropOldest
+	// Block waits up to SubscribeOptions.Timeout for room, then drops the
+	// message. Publish blocks the caller while it waits.
+	Block
+)
+
+const (
+	defaultBuffer       = 100
+	defaultBlockTimeout = time.Second
+)
+
+// SubscribeOptions tunes one subscription. The zero value is a 100-slot
+// buffer with DropNewest.
+type SubscribeOptions struct {
+	Buffer  int
+	Policy  Policy
+	Timeout time.Duration // only used by Block
+}
+
+var _ Broker = (*Bus)(nil)
+
+// Bus is an in-memory pub/sub event bus. It only connects publishers and
+// subscribers inside one process; see PGBroker to span processes.
 type Bus struct {
 	mu          sync.RWMutex
-	subscribers map[string][]chan Message
+	subscribers map[string][]*subscription
+	closed      bool
+
+	statsMu sync.Mutex
+	dropped map[string]uint64
+}
+
+// subscription owns its channel. mu serializes sends with close so a
+// publisher never writes to a closed channel; done wakes publishers that are
+// blocked on a full buffer so closing does not wait out their timeout.
+type subscription struct {
+	ch   chan Message
+	opts SubscribeOptions
+
+	mu       sync.Mutex
+	closed   bool
+	done     chan struct{}
+	doneOnce sync.Once
 }
 
 // New creates a new pub/sub bus
 func New() *Bus {
 	return &Bus{
-		subscribers: make(map[string][]chan Message),
+		subscribers: make(map[string][]*subscription),
+		dropped:     make(map[string]uint64),
 	}
 }
 
 // Subscribe returns a channel that receives messages for the given topic
 func (b *Bus) Subscribe(topic string) chan Message {
+	return b.SubscribeWith(topic, SubscribeOptions{})
+}
+
+// SubscribeWith is Subscribe with an explicit buffer size and delivery
+// policy. After Close it returns an already closed channel.
+func (b *Bus) SubscribeWith(topic string, opts SubscribeOptions) chan Message {
+	if opts.Buffer <= 0 {
+		opts.Buffer = defaultBuffer
+	}
+	if opts.Policy == Block && opts.Timeout <= 0 {
+		opts.Timeout = defaultBlockTimeout
+	}
+	sub := &subscription{
+		ch:   make(chan Message, opts.Buffer),
+		opts: opts,
+		done: make(chan struct{}),
+	}
+
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	ch := make(chan Message, 100) // buffered to prevent blocking
-	b.subscribers[topic] = append(b.subscribers[topic], ch)
-	return ch
+	if b.closed {
+		sub.close()
+		return sub.ch
+	}
+	b.subscribers[topic] = append(b.subscribers[topic], sub)
+	return sub.ch
 }
 
-// Publish sends a message to all subscribers of the topic
+// Publish sends a message to all subscribers of the topic. Messages that a
+// subscriber's policy gives up on are counted in Dropped.
 func (b *Bus) Publish(topic string, payload interface{}) {
+	// Deliver from a snapshot so a Block subscriber doesn't hold the bus lock
+	// (and stall Subscribe/Unsubscribe) while it waits.
 	b.mu.RLock()
-	defer b.mu.RUnlock()
+	subs := append([]*subscription(nil), b.subscribers[topic]...)
+	b.mu.RUnlock()
 
 	msg := Message{
 		Topic:   topic,
 		Payload: payload,
 	}
 
-	for _, ch := range b.subscribers[topic] {
-		select {
-		case ch <- msg:
-		default:
-			// Skip if channel is full (non-blocking)
+	var dropped uint64
+	for _, sub := range subs {
+		if !sub.deliver(msg) {
+			dropped++
 		}
 	}
+	if dropped > 0 {
+		b.statsMu.Lock()
+		b.dropped[topic] += dropped
+		b.statsMu.Unlock()
+	}
 }
 
-// Unsubscribe removes a subscriber channel for a topic
+// Unsubscribe removes a subscriber channel for a topic and closes it.
 func (b *Bus) Unsubscribe(topic string, ch chan Message) {
 	b.mu.Lock()
-	defer b.mu.Unlock()
-
+	var found *subscription
 	subs := b.subscribers[topic]
 	for i, sub := range subs {
-		if sub == ch {
-			b.subscribers[topic] = append(subs[:i], subs[i+1:]...)
-			close(ch)
+		if sub.ch == ch {
+			found = sub
+			b.subscribers[topic] = append(subs[:i:i], subs[i+1:]...)
 			break
 		}
 	}
+	if len(b.subscribers[topic]) == 0 {
+		delete(b.subscribers, topic)
+	}
+	b.mu.Unlock()
+
+	if found != nil {
+		found.close()
+	}
+}
+
+// Close closes every subscriber channel. Later publishes are ignored and
+// later subscriptions get a closed channel.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	subs := b.subscribers
+	b.subscribers = make(map[string][]*subscription)
+	b.closed = true
+	b.mu.Unlock()
+
+	for _, list := range subs {
+		for _, sub := range list {
+			sub.close()
+		}
+	}
+}
+
+// Dropped returns how many messages have been dropped per topic since the
+// bus was created.
+func (b *Bus) Dropped() map[string]uint64 {
+	b.statsMu.Lock()
+	defer b.statsMu.Unlock()
+
+	out := make(map[string]uint64, len(b.dropped))
+	for topic, n := range b.dropped {
+		out[topic] = n
+	}
+	return out
+}
+
+// deliver applies the subscription's policy and reports false when a message
+// was lost: msg itself, or for DropOldest the buffered message it evicted.
+func (s *subscription) deliver(msg Message) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return true // unsubscribed while we were publishing; not a drop
+	}
+
+	select {
+	case s.ch <- msg:
+		return true
+	default:
+	}
+
+	switch s.opts.Policy {
+	case DropOldest:
+		// Only publishers write to ch and they hold s.mu, so after evicting
+		// one message there is room for ours.
+		select {
+		case <-s.ch:
+		default:
+		}
+		select {
+		case s.ch <- msg:
+		default:
+		}
+		return false
+	case Block:
+		t := time.NewTimer(s.opts.Timeout)
+		defer t.Stop()
+		select {
+		case s.ch <- msg:
+			return true
+		case <-s.done:
+			return true
+		case <-t.C:
+			return false
+		}
+	default:
+		return false
+	}
+}
+
+func (s *subscription) close() {
+	s.doneOnce.Do(func() { close(s.done) })
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
 }
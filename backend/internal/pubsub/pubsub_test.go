@@ -0,0 +1,149 @@
+package pubsub
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPolicies(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        SubscribeOptions
+		readAfter   time.Duration // start reading this long after publishing begins; 0 reads only at the end
+		want        []int
+		wantDropped uint64
+	}{
+		{name: "drop newest", opts: SubscribeOptions{Buffer: 2}, want: []int{1, 2}, wantDropped: 1},
+		{name: "drop oldest", opts: SubscribeOptions{Buffer: 2, Policy: DropOldest}, want: []int{2, 3}, wantDropped: 1},
+		{name: "block times out", opts: SubscribeOptions{Buffer: 2, Policy: Block, Timeout: 20 * time.Millisecond}, want: []int{1, 2}, wantDropped: 1},
+		{name: "block waits for reader", opts: SubscribeOptions{Buffer: 2, Policy: Block, Timeout: 5 * time.Second}, readAfter: 20 * time.Millisecond, want: []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New()
+			ch := b.SubscribeWith("t", tt.opts)
+
+			var got []int
+			read := make(chan struct{})
+			if tt.readAfter > 0 {
+				go func() {
+					defer close(read)
+					time.Sleep(tt.readAfter)
+					for range tt.want {
+						got = append(got, (<-ch).Payload.(int))
+					}
+				}()
+			}
+			for i := 1; i <= 3; i++ {
+				b.Publish("t", i)
+			}
+			if tt.readAfter > 0 {
+				<-read
+			} else {
+				b.Close()
+				for m := range ch {
+					got = append(got, m.Payload.(int))
+				}
+			}
+
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("received %v, want %v", got, tt.want)
+			}
+			if d := b.Dropped()["t"]; d != tt.wantDropped {
+				t.Errorf("dropped %d, want %d", d, tt.wantDropped)
+			}
+		})
+	}
+}
+
+func TestConcurrentSubscribePublishUnsubscribe(t *testing.T) {
+	b := New()
+	defer b.Close()
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for p := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				b.Publish(fmt.Sprintf("topic-%d", i%3), p)
+			}
+		}()
+	}
+	var subs sync.WaitGroup
+	for s := range 32 {
+		subs.Add(1)
+		go func() {
+			defer subs.Done()
+			topic := fmt.Sprintf("topic-%d", s%3)
+			opts := SubscribeOptions{Buffer: 4, Policy: Policy(s % 3), Timeout: time.Millisecond}
+			for range 20 {
+				ch := b.SubscribeWith(topic, opts)
+				select {
+				case <-ch:
+				case <-time.After(time.Millisecond):
+				}
+				b.Unsubscribe(topic, ch)
+				if _, ok := <-drain(ch); ok {
+					t.Error("channel still open after Unsubscribe")
+				}
+			}
+		}()
+	}
+	subs.Wait()
+	close(stop)
+	wg.Wait()
+}
+
+// drain empties ch and returns it once closed, so a receive reports ok=false.
+func drain(ch chan Message) chan Message {
+	for range ch {
+	}
+	return ch
+}
+
+func TestCloseWhilePublishing(t *testing.T) {
+	b := New()
+	// Nobody reads these, so publishers block on them until Close.
+	for range 3 {
+		b.SubscribeWith("t", SubscribeOptions{Buffer: 1, Policy: Block, Timeout: time.Minute})
+	}
+	b.Subscribe("t")
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 10 {
+				b.Publish("t", i)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond) // let publishers fill the buffers and block
+	b.Close()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("publishers still blocked after Close")
+	}
+
+	b.Publish("t", "ignored")
+	if _, ok := <-b.Subscribe("t"); ok {
+		t.Error("Subscribe after Close returned an open channel")
+	}
+}
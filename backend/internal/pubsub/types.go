@@ -1,9 +1,13 @@
 package pubsub
 
+import "encoding/json"
+
 type AgentRequest struct {
 	UserID    string `json:"userId"`
+	SessionID string `json:"sessionId,omitempty"` // WebSocket session, scopes conversation memory
 	RequestID string `json:"requestId"`
 	Query     string `json:"query"`
+	Stream    bool   `json:"stream,omitempty"` // publish ResponseDelta chunks before the response
 }
 
 type AgentResponse struct {
@@ -11,12 +15,15 @@ type AgentResponse struct {
 	RequestID string        `json:"requestId"`
 	Answer    string        `json:"answer"`
 	Results   []ListingInfo `json:"results"`
+	Streamed  bool          `json:"streamed,omitempty"` // final message of a streamed answer
 }
 
 type ChatRequest struct {
 	UserID    string `json:"userId"`
+	SessionID string `json:"sessionId,omitempty"`
 	RequestID string `json:"requestId"`
 	Text      string `json:"text"`
+	Stream    bool   `json:"stream,omitempty"`
 }
 
 type ChatResponse struct {
@@ -24,6 +31,57 @@ type ChatResponse struct {
 	RequestID string        `json:"requestId"`
 	Answer    string        `json:"answer"`
 	Results   []ListingInfo `json:"results,omitempty"`
+	Streamed  bool          `json:"streamed,omitempty"`
+}
+
+// ResponseDelta is one chunk of a streamed answer, published on
+// "agent.response.delta" or "chat.response.delta". Seq starts at 0 so
+// subscribers can restore order if a backend reorders messages.
+type ResponseDelta struct {
+	UserID    string `json:"userId"`
+	RequestID string `json:"requestId"`
+	Seq       int    `json:"seq"`
+	Delta     string `json:"delta"`
+	Replace   bool   `json:"replace,omitempty"` // earlier chunks are void; Delta is the whole answer
+}
+
+// ConversationReset asks workers to forget a session's assistant context.
+// Published on "agent.reset".
+type ConversationReset struct {
+	UserID    string `json:"userId"`
+	SessionID string `json:"sessionId"`
+}
+
+// ListingWatchEvent tells the users who favorited a listing that its price
+// dropped or that it sold. Published on "listing.watch".
+type ListingWatchEvent struct {
+	UserIDs   []string `json:"userIds"`
+	ListingID string   `json:"listingId"`
+	Title     string   `json:"title"`
+	Kind      string   `json:"kind"` // "price_drop" or "sold"
+	OldPrice  float64  `json:"oldPrice,omitempty"`
+	Price     float64  `json:"price"`
+}
+
+// SavedSearchMatch carries new listings that match one of a user's saved
+// searches. Published on "search.match".
+type SavedSearchMatch struct {
+	UserID     string        `json:"userId"`
+	SearchID   string        `json:"searchId"`
+	SearchName string        `json:"searchName"`
+	Results    []ListingInfo `json:"results"`
+}
+
+// NotificationEvent is a stored in-app notification to push to its user.
+// Published on "notification.new".
+type NotificationEvent struct {
+	UserID    string          `json:"userId"`
+	ID        string          `json:"id"`
+	Kind      string          `json:"kind"`
+	Title     string          `json:"title"`
+	Body      string          `json:"body,omitempty"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	CreatedAt string          `json:"createdAt"`
 }
 
 type PrimaryImage struct {
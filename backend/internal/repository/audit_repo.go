@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+)
+
+type AuditRepo interface {
+	Add(ctx context.Context, e domain.AuditEntry) error
+}
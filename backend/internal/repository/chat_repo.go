@@ -1 +1,25 @@
 package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+)
+
+type ChatRepo interface {
+	// GetOrCreateConversation returns the conversation between buyer and seller
+	// about a listing, creating it (and both participants) if needed.
+	GetOrCreateConversation(ctx context.Context, listingID, buyerID, sellerID uuid.UUID) (domain.Conversation, error)
+	GetConversation(ctx context.Context, id uuid.UUID) (domain.Conversation, error)
+	ListConversations(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.Conversation, int, error)
+	AddMessage(ctx context.Context, conversationID, senderID uuid.UUID, body string) (domain.Message, error)
+	// ListMessages returns up to limit messages older than before (zero = latest), newest first.
+	ListMessages(ctx context.Context, conversationID uuid.UUID, before time.Time, limit int) ([]domain.Message, error)
+	// ListUnreadMessages returns messages sent to userID after their last_read_at, oldest first.
+	ListUnreadMessages(ctx context.Context, userID uuid.UUID, limit int) ([]domain.Message, error)
+	UnreadCounts(ctx context.Context, userID uuid.UUID) ([]domain.UnreadCount, error)
+	// MarkRead advances last_read_at; it never moves it backwards.
+	MarkRead(ctx context.Context, conversationID, userID uuid.UUID, at time.Time) error
+}
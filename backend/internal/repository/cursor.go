@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrBadCursor is returned for cursors that don't decode or were issued for
+// a different sort.
+var ErrBadCursor = errors.New("invalid cursor")
+
+// Cursor marks the last row of a page for keyset pagination. It is the sort
+// key of that row (created_at or price) plus its id as a tiebreak. Clients
+// only ever see it encoded and pass it back unchanged.
+type Cursor struct {
+	Sort      string     `json:"s"`
+	CreatedAt *time.Time `json:"t,omitempty"`
+	Price     *float64   `json:"p,omitempty"`
+	ID        uuid.UUID  `json:"id"`
+}
+
+// Cursor sorts. Anything else (e.g. relevance) pages by offset only.
+const (
+	SortCreatedDesc = "created_desc"
+	SortPriceAsc    = "price_asc"
+	SortPriceDesc   = "price_desc"
+)
+
+// CursorSort reports whether sort supports cursors, mapping "" to the
+// default created_desc.
+func CursorSort(sort string) (string, bool) {
+	switch sort {
+	case "", SortCreatedDesc:
+		return SortCreatedDesc, true
+	case SortPriceAsc, SortPriceDesc:
+		return sort, true
+	}
+	return "", false
+}
+
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses s and checks it was issued for sort.
+func DecodeCursor(s, sort string) (Cursor, error) {
+	sort, ok := CursorSort(sort)
+	if !ok {
+		return Cursor{}, ErrBadCursor
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrBadCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil || c.Sort != sort || c.ID == uuid.Nil {
+		return Cursor{}, ErrBadCursor
+	}
+	if sort == SortCreatedDesc && c.CreatedAt == nil || sort != SortCreatedDesc && c.Price == nil {
+		return Cursor{}, ErrBadCursor
+	}
+	return c, nil
+}
+
+// NextCursor returns the cursor after a page of n rows ending at last, or ""
+// when the page was short and there is nothing more to fetch.
+func NextCursor(n, limit int, last Cursor) string {
+	if n == 0 || n < limit {
+		return ""
+	}
+	return last.Encode()
+}
+
+// CreatedCursor is the cursor for rows ordered by created_at DESC, id DESC.
+func CreatedCursor(createdAt time.Time, id uuid.UUID) Cursor {
+	return Cursor{Sort: SortCreatedDesc, CreatedAt: &createdAt, ID: id}
+}
+
+// PriceCursor is the cursor for rows ordered by price, id (same direction).
+func PriceCursor(sort string, price float64, id uuid.UUID) Cursor {
+	return Cursor{Sort: sort, Price: &price, ID: id}
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+)
+
+type FavoriteRepo interface {
+	// Add and Remove are idempotent.
+	Add(ctx context.Context, userID, listingID uuid.UUID) error
+	Remove(ctx context.Context, userID, listingID uuid.UUID) error
+	// List returns the user's favorited listings, most recently saved first.
+	// Removed listings are left out.
+	List(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.Listing, int, error)
+	// Counts returns how many users favorited each listing; listings nobody
+	// favorited are missing from the map.
+	Counts(ctx context.Context, listingIDs []uuid.UUID) (map[uuid.UUID]int, error)
+	// FavoritedBy reports which of listingIDs the user favorited.
+	FavoritedBy(ctx context.Context, userID uuid.UUID, listingIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+	// Watchers lists the users who favorited the listing.
+	Watchers(ctx context.Context, listingID uuid.UUID) ([]uuid.UUID, error)
+}
@@ -2,30 +2,71 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
 )
 
 type ListParams struct {
-	Q        string
-	Category string
-	PriceMin *float64
-	PriceMax *float64
-	Status   string
-	Limit    int
-	Offset   int
-	Sort     string
-	SellerID *uuid.UUID
+	Q         string
+	Category  string
+	Condition string
+	PriceMin  *float64
+	PriceMax  *float64
+	Status    string // one status, or several separated by commas
+	Limit     int
+	Offset    int
+	Sort      string
+	SellerID  *uuid.UUID
+	// Cursor, when set, replaces Offset: the page starts after the row it
+	// encodes. Only the created_desc and price sorts support it.
+	Cursor string
+	// CreatedAfter keeps listings created strictly after it (saved search
+	// matching).
+	CreatedAfter *time.Time
 }
 
 type ListingRepo interface {
 	Create(ctx context.Context, in CreateListing) (domain.Listing, error)
 	Get(ctx context.Context, id uuid.UUID) (domain.Listing, error)
 	List(ctx context.Context, p ListParams) ([]domain.Listing, int, error)
+	Facets(ctx context.Context, p ListParams) (domain.ListingFacets, error)
 	UpdatePartial(ctx context.Context, id uuid.UUID, patch UpdateListing) (domain.Listing, error)
-	MarkSold(ctx context.Context, id uuid.UUID) error
-	SoftDelete(ctx context.Context, id uuid.UUID) error
+	// ChangeStatus applies c, including its Patch, and records it in the
+	// listing's status history, all in one transaction. Ending a reservation other than by selling cancels the accepted offer
+	// behind it. It returns domain.ErrConflict if the listing is no longer in
+	// c.From. Callers are expected to have checked the transition is allowed.
+	ChangeStatus(ctx context.Context, c StatusChange) (domain.Listing, error)
+	// StatusHistory lists the listing's status changes, newest first.
+	StatusHistory(ctx context.Context, id uuid.UUID, limit, offset int) ([]domain.ListingStatusChange, int, error)
+	// ExpireReservations returns reservations that ran out by now to active,
+	// expiring the accepted offers behind them, and reports which listings
+	// were released.
+	ExpireReservations(ctx context.Context, now time.Time) ([]uuid.UUID, error)
+	// CountBySeller counts the seller's listings per status.
+	CountBySeller(ctx context.Context, sellerID uuid.UUID) (map[domain.ListingStatus]int, error)
+}
+
+// StatusChange moves a listing from one status to another. ReservedBy and
+// ReservedUntil are used when To is reserved; when To is sold, ReservedBy
+// records the buyer if the listing had none.
+type StatusChange struct {
+	ListingID     uuid.UUID
+	From, To      domain.ListingStatus
+	ChangedBy     *uuid.UUID // nil for the system
+	Reason        string
+	ReservedBy    *uuid.UUID
+	ReservedUntil *time.Time
+	Patch         UpdateListing // field edits made in the same transaction
+}
+
+// ListingCursor is the cursor after l for the given List sort.
+func ListingCursor(sort string, l domain.Listing) Cursor {
+	if sort, _ := CursorSort(sort); sort != SortCreatedDesc {
+		return PriceCursor(sort, l.Price, l.ID)
+	}
+	return CreatedCursor(l.CreatedAt, l.ID)
 }
 
 type CreateListing struct {
@@ -43,5 +84,4 @@ type UpdateListing struct {
 	Category    *string
 	Price       *float64
 	Condition   *domain.Condition
-	Status      *domain.ListingStatus
 }
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+)
+
+// LoginAttemptRepo tracks failed sign-ins by key ("account:<email>" or
+// "ip:<addr>"), and password reset requests the same way ("reset:<email>"
+// or "reset-ip:<addr>"). Times come from the caller so a fake clock drives
+// them.
+type LoginAttemptRepo interface {
+	// Get returns the key's counters, zero if it has none.
+	Get(ctx context.Context, key string) (domain.LoginAttempts, error)
+	// RecordFailure counts a failure at at and returns the new count. A key
+	// whose last failure is before staleBefore starts over from one.
+	RecordFailure(ctx context.Context, key string, at, staleBefore time.Time) (int, error)
+	// Lock locks the key until until, unless it is still locked at now, and
+	// reports whether it did.
+	Lock(ctx context.Context, key string, now, until time.Time) (bool, error)
+	Clear(ctx context.Context, key string) error
+}
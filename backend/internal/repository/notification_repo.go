@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+)
+
+type NotificationRepo interface {
+	Create(ctx context.Context, in CreateNotification) (domain.Notification, error)
+	// List returns the user's notifications, newest first, with the total
+	// matching and the user's unread count. Cursor works like ListParams'.
+	List(ctx context.Context, p NotificationListParams) (items []domain.Notification, total, unread int, err error)
+	// MarkRead marks one of the user's notifications read; ErrNotFound if it
+	// is not theirs.
+	MarkRead(ctx context.Context, userID, id uuid.UUID) (domain.Notification, error)
+	// MarkAllRead marks every unread notification of the user read and
+	// returns how many there were.
+	MarkAllRead(ctx context.Context, userID uuid.UUID) (int, error)
+}
+
+type CreateNotification struct {
+	UserID uuid.UUID
+	Kind   string
+	Title  string
+	Body   string
+	Data   json.RawMessage
+}
+
+type NotificationListParams struct {
+	UserID     uuid.UUID
+	UnreadOnly bool
+	Cursor     string
+	Limit      int
+	Offset     int
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+)
+
+type OfferRepo interface {
+	// Create returns domain.ErrConflict when the buyer already has an active
+	// offer on the listing.
+	Create(ctx context.Context, in CreateOffer) (domain.Offer, error)
+	Get(ctx context.Context, id uuid.UUID) (domain.Offer, error)
+	List(ctx context.Context, p OfferListParams) ([]domain.Offer, int, error)
+	// UpdateStatus moves the offer from one status to another, replacing the
+	// amount and message when amount is set. It returns domain.ErrConflict if
+	// the offer is no longer in from.
+	UpdateStatus(ctx context.Context, id uuid.UUID, from, to domain.OfferStatus, amount *float64, message *string) (domain.Offer, error)
+	// Accept accepts the offer, reserves its listing for the buyer until the
+	// given time and rejects the listing's other active offers, in one
+	// transaction, recording by as the user who made the listing change. It
+	// returns the accepted offer and the ones it rejected, or
+	// domain.ErrConflict if the offer is no longer in from or the listing is
+	// no longer active.
+	Accept(ctx context.Context, id uuid.UUID, from domain.OfferStatus, by uuid.UUID, reservedUntil time.Time) (accepted domain.Offer, rejected []domain.Offer, err error)
+}
+
+type CreateOffer struct {
+	ListingID uuid.UUID
+	BuyerID   uuid.UUID
+	SellerID  uuid.UUID
+	Amount    float64
+	Message   string
+}
+
+// OfferListParams filters offers; unset fields match everything. Newest first.
+type OfferListParams struct {
+	ListingID *uuid.UUID
+	BuyerID   *uuid.UUID
+	SellerID  *uuid.UUID
+	Status    string
+	Limit     int
+	Offset    int
+}
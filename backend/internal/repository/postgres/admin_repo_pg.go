@@ -3,7 +3,7 @@ package postgres
 import (
 	"context"
 
-	"github.com/google/uuid"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/service"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -34,10 +34,19 @@ func (r *AdminRepoPG) CountReportsByStatus(ctx context.Context) (int, int, int,
 	return open, rev, res, dis, err
 }
 
-func (r *AdminRepoPG) ListUsers(ctx context.Context, limit, offset int) ([]service.AdminUserRow, int, error) {
+func (r *AdminRepoPG) ListUsers(ctx context.Context, cursor string, limit, offset int) ([]service.AdminUserRow, int, error) {
+	afterAt, afterID, err := createdKeyset(cursor)
+	if err != nil {
+		return nil, 0, err
+	}
+	if afterAt != nil {
+		offset = 0
+	}
 	rows, err := r.db.Query(ctx, `
 		SELECT id, name, email, role, created_at
-		FROM users ORDER BY created_at DESC LIMIT $1 OFFSET $2`, limit, offset)
+		FROM users
+		WHERE $3::timestamptz IS NULL OR (created_at, id) < ($3::timestamptz, $4::uuid)
+		ORDER BY created_at DESC, id DESC LIMIT $1 OFFSET $2`, limit, offset, afterAt, afterID)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -57,8 +66,27 @@ func (r *AdminRepoPG) ListUsers(ctx context.Context, limit, offset int) ([]servi
 	return out, total, nil
 }
 
-func (r *AdminRepoPG) ForceRemoveListing(ctx context.Context, listingID uuid.UUID) error {
-	_, err := r.db.Exec(ctx, `
-		UPDATE listings SET status='removed', updated_at=now() WHERE id=$1`, listingID)
-	return err
+func (r *AdminRepoPG) ListAudit(ctx context.Context, cursor string, limit int) ([]domain.AuditEntry, error) {
+	afterAt, afterID, err := createdKeyset(cursor)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.Query(ctx, `
+		SELECT id, action, actor_id, subject, detail, created_at
+		FROM audit_log
+		WHERE $2::timestamptz IS NULL OR (created_at, id) < ($2::timestamptz, $3::uuid)
+		ORDER BY created_at DESC, id DESC LIMIT $1`, limit, afterAt, afterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.AuditEntry{}
+	for rows.Next() {
+		var e domain.AuditEntry
+		if err := rows.Scan(&e.ID, &e.Action, &e.ActorID, &e.Subject, &e.Detail, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
 }
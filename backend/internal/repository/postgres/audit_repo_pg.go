@@ -0,0 +1,24 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+)
+
+type AuditRepoPG struct{ db *pgxpool.Pool }
+
+func NewAuditRepo(db *pgxpool.Pool) *AuditRepoPG { return &AuditRepoPG{db: db} }
+
+func (r *AuditRepoPG) Add(ctx context.Context, e domain.AuditEntry) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO audit_log (id, action, actor_id, subject, detail, created_at)
+		VALUES ($1,$2,$3,$4,$5,$6)`, e.ID, e.Action, e.ActorID, e.Subject, e.Detail, e.CreatedAt)
+	return err
+}
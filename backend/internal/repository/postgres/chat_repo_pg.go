@@ -1 +1,224 @@
 package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+)
+
+type ChatRepoPG struct{ db *pgxpool.Pool }
+
+func NewChatRepo(db *pgxpool.Pool) *ChatRepoPG { return &ChatRepoPG{db: db} }
+
+const conversationCols = `
+	c.id, c.listing_id, c.created_by, c.created_at,
+	(SELECT array_agg(cp.user_id ORDER BY cp.joined_at) FROM conversation_participants cp WHERE cp.conversation_id = c.id)`
+
+func (r *ChatRepoPG) GetOrCreateConversation(ctx context.Context, listingID, buyerID, sellerID uuid.UUID) (domain.Conversation, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.Conversation{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialize concurrent "first message" races for the same listing+buyer.
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, listingID.String()+buyerID.String()); err != nil {
+		return domain.Conversation{}, err
+	}
+
+	var id uuid.UUID
+	err = tx.QueryRow(ctx, `
+		SELECT c.id
+		FROM conversations c
+		JOIN conversation_participants p ON p.conversation_id = c.id AND p.user_id = $2 AND p.role = 'buyer'
+		WHERE c.listing_id = $1
+		LIMIT 1`, listingID, buyerID).Scan(&id)
+	switch {
+	case err == nil:
+	case errors.Is(err, pgx.ErrNoRows):
+		id = uuid.New()
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO conversations (id, listing_id, created_by) VALUES ($1,$2,$3)`, id, listingID, buyerID); err != nil {
+			return domain.Conversation{}, err
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO conversation_participants (conversation_id, user_id, role)
+			VALUES ($1,$2,'buyer'), ($1,$3,'seller')`, id, buyerID, sellerID); err != nil {
+			return domain.Conversation{}, err
+		}
+	default:
+		return domain.Conversation{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.Conversation{}, err
+	}
+	return r.GetConversation(ctx, id)
+}
+
+func (r *ChatRepoPG) GetConversation(ctx context.Context, id uuid.UUID) (domain.Conversation, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+conversationCols+` FROM conversations c WHERE c.id = $1`, id)
+	var c domain.Conversation
+	err := row.Scan(&c.ID, &c.ListingID, &c.CreatedBy, &c.CreatedAt, &c.Participants)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Conversation{}, domain.ErrNotFound
+	}
+	return c, err
+}
+
+func (r *ChatRepoPG) ListConversations(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.Conversation, int, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+conversationCols+`,
+		       lm.id, lm.sender_id, lm.body, lm.created_at,
+		       (SELECT COUNT(*) FROM messages um
+		        WHERE um.conversation_id = c.id AND um.sender_id <> me.user_id
+		          AND um.created_at > COALESCE(rd.last_read_at, me.joined_at))
+		FROM conversations c
+		JOIN conversation_participants me ON me.conversation_id = c.id AND me.user_id = $1
+		LEFT JOIN conversation_reads rd ON rd.conversation_id = c.id AND rd.user_id = me.user_id
+		LEFT JOIN LATERAL (
+		  SELECT m.id, m.sender_id, m.body, m.created_at
+		  FROM messages m WHERE m.conversation_id = c.id
+		  ORDER BY m.created_at DESC LIMIT 1
+		) lm ON TRUE
+		ORDER BY COALESCE(lm.created_at, c.created_at) DESC
+		LIMIT $2 OFFSET $3`, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var out []domain.Conversation
+	for rows.Next() {
+		var (
+			c        domain.Conversation
+			msgID    *uuid.UUID
+			senderID *uuid.UUID
+			body     *string
+			sentAt   *time.Time
+		)
+		if err := rows.Scan(&c.ID, &c.ListingID, &c.CreatedBy, &c.CreatedAt, &c.Participants,
+			&msgID, &senderID, &body, &sentAt, &c.UnreadCount); err != nil {
+			return nil, 0, err
+		}
+		if msgID != nil {
+			c.LastMessage = &domain.Message{
+				ID: *msgID, ConversationID: c.ID, SenderID: *senderID, Body: *body, CreatedAt: *sentAt,
+			}
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM conversation_participants WHERE user_id = $1`, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return out, total, nil
+}
+
+func (r *ChatRepoPG) AddMessage(ctx context.Context, conversationID, senderID uuid.UUID, body string) (domain.Message, error) {
+	m := domain.Message{ID: uuid.New(), ConversationID: conversationID, SenderID: senderID, Body: body}
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO messages (id, conversation_id, sender_id, body)
+		VALUES ($1,$2,$3,$4)
+		RETURNING created_at`, m.ID, conversationID, senderID, body).Scan(&m.CreatedAt)
+	return m, err
+}
+
+func (r *ChatRepoPG) ListMessages(ctx context.Context, conversationID uuid.UUID, before time.Time, limit int) ([]domain.Message, error) {
+	var beforeArg *time.Time
+	if !before.IsZero() {
+		beforeArg = &before
+	}
+	rows, err := r.db.Query(ctx, `
+		SELECT id, conversation_id, sender_id, body, created_at
+		FROM messages
+		WHERE conversation_id = $1 AND ($2::timestamptz IS NULL OR created_at < $2)
+		ORDER BY created_at DESC
+		LIMIT $3`, conversationID, beforeArg, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Message
+	for rows.Next() {
+		var m domain.Message
+		if err := rows.Scan(&m.ID, &m.ConversationID, &m.SenderID, &m.Body, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+// unreadFrom joins each of the user's conversations to the messages they have
+// not read yet. Without a conversation_reads row, joined_at is the baseline.
+const unreadFrom = `
+	FROM conversation_participants p
+	LEFT JOIN conversation_reads r ON r.conversation_id = p.conversation_id AND r.user_id = p.user_id
+	JOIN messages m ON m.conversation_id = p.conversation_id
+	  AND m.sender_id <> p.user_id
+	  AND m.created_at > COALESCE(r.last_read_at, p.joined_at)
+	WHERE p.user_id = $1`
+
+func (r *ChatRepoPG) ListUnreadMessages(ctx context.Context, userID uuid.UUID, limit int) ([]domain.Message, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT m.id, m.conversation_id, m.sender_id, m.body, m.created_at`+unreadFrom+`
+		ORDER BY m.created_at ASC
+		LIMIT $2`, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Message
+	for rows.Next() {
+		var m domain.Message
+		if err := rows.Scan(&m.ID, &m.ConversationID, &m.SenderID, &m.Body, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+func (r *ChatRepoPG) UnreadCounts(ctx context.Context, userID uuid.UUID) ([]domain.UnreadCount, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT p.conversation_id, COUNT(*)`+unreadFrom+`
+		GROUP BY p.conversation_id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.UnreadCount
+	for rows.Next() {
+		var u domain.UnreadCount
+		if err := rows.Scan(&u.ConversationID, &u.Unread); err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+func (r *ChatRepoPG) MarkRead(ctx context.Context, conversationID, userID uuid.UUID, at time.Time) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO conversation_reads (conversation_id, user_id, last_read_at)
+		VALUES ($1,$2,$3)
+		ON CONFLICT (conversation_id, user_id)
+		DO UPDATE SET last_read_at = GREATEST(conversation_reads.last_read_at, EXCLUDED.last_read_at)`,
+		conversationID, userID, at)
+	return err
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
 )
 
 func NewPool(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
@@ -25,3 +28,16 @@ func NewPool(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
 	}
 	return pool, nil
 }
+
+// createdKeyset decodes a created_desc cursor for tables paged by
+// (created_at, id) DESC. An empty cursor gives a nil time: the first page.
+func createdKeyset(cursor string) (*time.Time, uuid.UUID, error) {
+	if cursor == "" {
+		return nil, uuid.Nil, nil
+	}
+	cur, err := repository.DecodeCursor(cursor, repository.SortCreatedDesc)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+	return cur.CreatedAt, cur.ID, nil
+}
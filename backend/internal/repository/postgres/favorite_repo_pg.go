@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+)
+
+type FavoriteRepoPG struct{ db *pgxpool.Pool }
+
+func NewFavoriteRepo(db *pgxpool.Pool) *FavoriteRepoPG { return &FavoriteRepoPG{db: db} }
+
+func (r *FavoriteRepoPG) Add(ctx context.Context, userID, listingID uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO favorites (user_id, listing_id) VALUES ($1,$2)
+		ON CONFLICT DO NOTHING`, userID, listingID)
+	return err
+}
+
+func (r *FavoriteRepoPG) Remove(ctx context.Context, userID, listingID uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM favorites WHERE user_id=$1 AND listing_id=$2`, userID, listingID)
+	return err
+}
+
+func (r *FavoriteRepoPG) List(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.Listing, int, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+listingCols+` FROM (
+		  SELECT l.*, f.created_at AS favorited_at
+		  FROM favorites f JOIN listings l ON l.id = f.listing_id
+		  WHERE f.user_id=$1 AND l.status <> 'removed'
+		) fl
+		ORDER BY favorited_at DESC, id DESC
+		LIMIT $2 OFFSET $3`, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	out := []domain.Listing{}
+	for rows.Next() {
+		l, err := scanListing(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		out = append(out, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	var total int
+	if err := r.db.QueryRow(ctx, `
+		SELECT count(*) FROM favorites f JOIN listings l ON l.id = f.listing_id
+		WHERE f.user_id=$1 AND l.status <> 'removed'`, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return out, total, nil
+}
+
+func (r *FavoriteRepoPG) Counts(ctx context.Context, listingIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	out := map[uuid.UUID]int{}
+	if len(listingIDs) == 0 {
+		return out, nil
+	}
+	rows, err := r.db.Query(ctx, `
+		SELECT listing_id, count(*) FROM favorites
+		WHERE listing_id = ANY($1) GROUP BY listing_id`, listingIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id uuid.UUID
+		var n int
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, err
+		}
+		out[id] = n
+	}
+	return out, rows.Err()
+}
+
+func (r *FavoriteRepoPG) FavoritedBy(ctx context.Context, userID uuid.UUID, listingIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	out := map[uuid.UUID]bool{}
+	if len(listingIDs) == 0 {
+		return out, nil
+	}
+	rows, err := r.db.Query(ctx, `
+		SELECT listing_id FROM favorites
+		WHERE user_id=$1 AND listing_id = ANY($2)`, userID, listingIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out[id] = true
+	}
+	return out, rows.Err()
+}
+
+func (r *FavoriteRepoPG) Watchers(ctx context.Context, listingID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(ctx, `SELECT user_id FROM favorites WHERE listing_id=$1`, listingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
@@ -2,12 +2,14 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
@@ -16,6 +18,8 @@ import (
 
 type ListingRepoPG struct{ db *pgxpool.Pool }
 
+const listingCols = "id, seller_id, title, description, category, price, condition, status, reserved_by, reserved_until, created_at, updated_at"
+
 func NewListingRepo(db *pgxpool.Pool) *ListingRepoPG { return &ListingRepoPG{db: db} }
 
 func (r *ListingRepoPG) Create(ctx context.Context, in repository.CreateListing) (domain.Listing, error) {
@@ -34,66 +38,130 @@ func (r *ListingRepoPG) Create(ctx context.Context, in repository.CreateListing)
 
 func (r *ListingRepoPG) Get(ctx context.Context, id uuid.UUID) (domain.Listing, error) {
 	row := r.db.QueryRow(ctx, `
-		SELECT id, seller_id, title, description, category, price, condition, status, created_at, updated_at
+		SELECT `+listingCols+`
 		FROM listings WHERE id = $1
 	`, id)
-	return scanListing(row)
+	l, err := scanListing(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Listing{}, domain.ErrNotFound
+	}
+	return l, err
 }
 
-func (r *ListingRepoPG) List(ctx context.Context, p repository.ListParams) ([]domain.Listing, int, error) {
-	var (
-		where []string
-		args  []any
-		i     = 1
-	)
-
-	if p.Status != "" {
-		where = append(where, fmt.Sprintf("status = $%d", i))
-		args = append(args, p.Status)
-		i++
+// listFilter is the WHERE clause shared by List and Facets.
+type listFilter struct {
+	where []string
+	args  []any
+	// tsq is the parsed search query; it is reused by the relevance sort and
+	// the highlights, all bound to the same parameter.
+	tsq string
+}
+
+func (f *listFilter) arg(v any) string {
+	f.args = append(f.args, v)
+	return fmt.Sprintf("$%d", len(f.args))
+}
+
+func (f *listFilter) clause() string {
+	// If no filters at all, use a harmless TRUE so WHERE clause is valid
+	if len(f.where) == 0 {
+		return "TRUE"
+	}
+	return strings.Join(f.where, " AND ")
+}
+
+// buildListFilter turns p into SQL. skip names one facet dimension
+// ("category", "condition", "price" or "status") whose own filter is left
+// out, so that facet counts the alternatives to the current selection.
+func buildListFilter(p repository.ListParams, skip string) listFilter {
+	var f listFilter
+
+	if p.Status != "" && skip != "status" {
+		// "active,reserved" matches either.
+		if statuses := strings.Split(p.Status, ","); len(statuses) > 1 {
+			f.where = append(f.where, "status = ANY("+f.arg(statuses)+")")
+		} else {
+			f.where = append(f.where, "status = "+f.arg(p.Status))
+		}
 	}
 
 	if p.SellerID != nil {
-		where = append(where, fmt.Sprintf("seller_id = $%d", i))
-		args = append(args, *p.SellerID)
-		i++
+		f.where = append(f.where, "seller_id = "+f.arg(*p.SellerID))
 	}
 
-	if p.Category != "" {
-		where = append(where, fmt.Sprintf("category = $%d", i))
-		args = append(args, p.Category)
-		i++
+	if p.Category != "" && skip != "category" {
+		f.where = append(f.where, "category = "+f.arg(p.Category))
+	}
+
+	if p.Condition != "" && skip != "condition" {
+		f.where = append(f.where, "condition = "+f.arg(p.Condition))
 	}
 
 	if p.Q != "" {
-		where = append(where, fmt.Sprintf("(title ILIKE $%d OR description ILIKE $%d)", i, i+1))
-		args = append(args, "%"+p.Q+"%", "%"+p.Q+"%")
-		i += 2
+		f.tsq = "websearch_to_tsquery('english', " + f.arg(p.Q) + ")"
+		f.where = append(f.where, "search_tsv @@ "+f.tsq)
 	}
 
-	if p.PriceMin != nil {
-		where = append(where, fmt.Sprintf("price >= $%d", i))
-		args = append(args, *p.PriceMin)
-		i++
+	if p.PriceMin != nil && skip != "price" {
+		f.where = append(f.where, "price >= "+f.arg(*p.PriceMin))
 	}
 
-	if p.PriceMax != nil {
-		where = append(where, fmt.Sprintf("price <= $%d", i))
-		args = append(args, *p.PriceMax)
-		i++
+	if p.PriceMax != nil && skip != "price" {
+		f.where = append(f.where, "price <= "+f.arg(*p.PriceMax))
 	}
 
-	// If no filters at all, add a harmless TRUE so WHERE clause is valid
-	if len(where) == 0 {
-		where = append(where, "TRUE")
+	if p.CreatedAfter != nil {
+		f.where = append(f.where, "created_at > "+f.arg(*p.CreatedAfter))
 	}
 
-	order := "created_at DESC"
+	return f
+}
+
+// keysetClause selects the rows after cur in the matching List order.
+func keysetClause(f *listFilter, cur repository.Cursor) string {
+	switch cur.Sort {
+	case repository.SortPriceAsc:
+		return fmt.Sprintf("(price, id) > (%s::numeric, %s::uuid)", f.arg(*cur.Price), f.arg(cur.ID))
+	case repository.SortPriceDesc:
+		return fmt.Sprintf("(price, id) < (%s::numeric, %s::uuid)", f.arg(*cur.Price), f.arg(cur.ID))
+	default:
+		return fmt.Sprintf("(created_at, id) < (%s::timestamptz, %s::uuid)", f.arg(*cur.CreatedAt), f.arg(cur.ID))
+	}
+}
+
+func (r *ListingRepoPG) List(ctx context.Context, p repository.ListParams) ([]domain.Listing, int, error) {
+	f := buildListFilter(p, "")
+	tsq := f.tsq
+
+	order := "created_at DESC, id DESC"
 	switch p.Sort {
 	case "price_asc":
-		order = "price ASC, created_at DESC"
+		order = "price ASC, id ASC"
 	case "price_desc":
-		order = "price DESC, created_at DESC"
+		order = "price DESC, id DESC"
+	case "relevance":
+		if tsq != "" {
+			order = "ts_rank(search_tsv, " + tsq + ") DESC, created_at DESC, id DESC"
+		}
+	}
+
+	// The keyset condition only narrows the page; total below is counted
+	// without it so it still covers every page.
+	where := f.clause()
+	nFilterArgs := len(f.args)
+	if p.Cursor != "" {
+		cur, err := repository.DecodeCursor(p.Cursor, p.Sort)
+		if err != nil {
+			return nil, 0, err
+		}
+		where += " AND " + keysetClause(&f, cur)
+	}
+
+	cols := listingCols
+	if tsq != "" {
+		cols += fmt.Sprintf(`,
+		  ts_headline('english', title, %[1]s, 'HighlightAll=true,StartSel=<mark>,StopSel=</mark>'),
+		  ts_headline('english', description, %[1]s, 'StartSel=<mark>,StopSel=</mark>,MinWords=10,MaxWords=30,MaxFragments=2')`, tsq)
 	}
 
 	limit := 20
@@ -101,7 +169,7 @@ func (r *ListingRepoPG) List(ctx context.Context, p repository.ListParams) ([]do
 		limit = p.Limit
 	}
 	offset := 0
-	if p.Offset > 0 {
+	if p.Offset > 0 && p.Cursor == "" {
 		offset = p.Offset
 	}
 
@@ -109,13 +177,13 @@ func (r *ListingRepoPG) List(ctx context.Context, p repository.ListParams) ([]do
 		WITH base AS (
 		  SELECT * FROM listings WHERE %s
 		)
-		SELECT id, seller_id, title, description, category, price, condition, status, created_at, updated_at
+		SELECT %s
 		FROM base
 		ORDER BY %s
 		LIMIT %d OFFSET %d
-	`, strings.Join(where, " AND "), order, limit, offset)
+	`, where, cols, order, limit, offset)
 
-	rows, err := r.db.Query(ctx, sql, args...)
+	rows, err := r.db.Query(ctx, sql, f.args...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -123,7 +191,12 @@ func (r *ListingRepoPG) List(ctx context.Context, p repository.ListParams) ([]do
 
 	var out []domain.Listing
 	for rows.Next() {
-		l, err := scanListing(rows)
+		var l domain.Listing
+		if tsq != "" {
+			l, err = scanListingWithHighlight(rows)
+		} else {
+			l, err = scanListing(rows)
+		}
 		if err != nil {
 			return nil, 0, err
 		}
@@ -133,17 +206,109 @@ func (r *ListingRepoPG) List(ctx context.Context, p repository.ListParams) ([]do
 		return nil, 0, err
 	}
 
-	// total count (same WHERE, same args)
-	countSQL := fmt.Sprintf(`SELECT count(*) FROM listings WHERE %s`, strings.Join(where, " AND "))
+	// total count (same WHERE minus the cursor); the cursor args come last,
+	// so the filter's own placeholders are unchanged.
+	countSQL := fmt.Sprintf(`SELECT count(*) FROM listings WHERE %s`, f.clause())
 	var total int
-	if err := r.db.QueryRow(ctx, countSQL, args...).Scan(&total); err != nil {
+	if err := r.db.QueryRow(ctx, countSQL, f.args[:nFilterArgs]...).Scan(&total); err != nil {
 		return nil, 0, err
 	}
 
 	return out, total, nil
 }
 
+// priceBucketBounds splits prices into [0,25) [25,50) ... [500,∞).
+var priceBucketBounds = []float64{25, 50, 100, 250, 500}
+
+// Facets counts listings matching p by category, condition, price bucket and
+// status. Paging and sort are ignored.
+func (r *ListingRepoPG) Facets(ctx context.Context, p repository.ListParams) (domain.ListingFacets, error) {
+	var out domain.ListingFacets
+	var err error
+
+	if out.Category, err = r.facetCounts(ctx, p, "category"); err != nil {
+		return domain.ListingFacets{}, err
+	}
+	if out.Condition, err = r.facetCounts(ctx, p, "condition"); err != nil {
+		return domain.ListingFacets{}, err
+	}
+	if out.Status, err = r.facetCounts(ctx, p, "status"); err != nil {
+		return domain.ListingFacets{}, err
+	}
+	if out.Price, err = r.priceFacet(ctx, p); err != nil {
+		return domain.ListingFacets{}, err
+	}
+	return out, nil
+}
+
+// facetCounts groups by col, which must be one of the trusted facet columns.
+func (r *ListingRepoPG) facetCounts(ctx context.Context, p repository.ListParams, col string) ([]domain.FacetCount, error) {
+	f := buildListFilter(p, col)
+	rows, err := r.db.Query(ctx, fmt.Sprintf(`
+		SELECT %[1]s, count(*) FROM listings
+		WHERE %[2]s
+		GROUP BY %[1]s
+		ORDER BY count(*) DESC, %[1]s`, col, f.clause()), f.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []domain.FacetCount{}
+	for rows.Next() {
+		var fc domain.FacetCount
+		if err := rows.Scan(&fc.Value, &fc.Count); err != nil {
+			return nil, err
+		}
+		out = append(out, fc)
+	}
+	return out, rows.Err()
+}
+
+// priceFacet returns every bucket, including empty ones.
+func (r *ListingRepoPG) priceFacet(ctx context.Context, p repository.ListParams) ([]domain.PriceBucketCount, error) {
+	f := buildListFilter(p, "price")
+	bounds := f.arg(priceBucketBounds)
+	rows, err := r.db.Query(ctx, fmt.Sprintf(`
+		SELECT width_bucket(price, %s::numeric[]), count(*) FROM listings
+		WHERE %s
+		GROUP BY 1`, bounds, f.clause()), f.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]domain.PriceBucketCount, len(priceBucketBounds)+1)
+	for i := range out {
+		if i > 0 {
+			out[i].Min = priceBucketBounds[i-1]
+		}
+		if i < len(priceBucketBounds) {
+			m := priceBucketBounds[i]
+			out[i].Max = &m
+		}
+	}
+	for rows.Next() {
+		var bucket, n int
+		if err := rows.Scan(&bucket, &n); err != nil {
+			return nil, err
+		}
+		if bucket >= 0 && bucket < len(out) {
+			out[bucket].Count = n
+		}
+	}
+	return out, rows.Err()
+}
+
 func (r *ListingRepoPG) UpdatePartial(ctx context.Context, id uuid.UUID, patch repository.UpdateListing) (domain.Listing, error) {
+	if err := updateListingFields(ctx, r.db, id, patch); err != nil {
+		return domain.Listing{}, err
+	}
+	return r.Get(ctx, id)
+}
+
+// updateListingFields applies patch on q; an empty patch changes nothing.
+func updateListingFields(ctx context.Context, q querier, id uuid.UUID, patch repository.UpdateListing) error {
 	var sets []string
 	var args []any
 	i := 1
@@ -173,40 +338,205 @@ func (r *ListingRepoPG) UpdatePartial(ctx context.Context, id uuid.UUID, patch r
 		args = append(args, string(*patch.Condition))
 		i++
 	}
-	if patch.Status != nil {
-		sets = append(sets, fmt.Sprintf("status=$%d", i))
-		args = append(args, string(*patch.Status))
-		i++
-	}
 
 	if len(sets) == 0 {
-		return r.Get(ctx, id)
+		return nil
 	}
 
 	args = append(args, id)
 	sql := fmt.Sprintf(`UPDATE listings SET %s, updated_at=now() WHERE id=$%d`, strings.Join(sets, ", "), len(args))
-	_, err := r.db.Exec(ctx, sql, args...)
+	_, err := q.Exec(ctx, sql, args...)
+	return err
+}
+
+// querier is what both the pool and a transaction offer.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func (r *ListingRepoPG) ChangeStatus(ctx context.Context, c repository.StatusChange) (domain.Listing, error) {
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return domain.Listing{}, err
 	}
-	return r.Get(ctx, id)
+	defer tx.Rollback(ctx)
+
+	if err := changeListingStatus(ctx, tx, c); err != nil {
+		return domain.Listing{}, err
+	}
+	if err := updateListingFields(ctx, tx, c.ListingID, c.Patch); err != nil {
+		return domain.Listing{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return domain.Listing{}, err
+	}
+	return r.Get(ctx, c.ListingID)
 }
 
-func (r *ListingRepoPG) MarkSold(ctx context.Context, id uuid.UUID) error {
-	_, err := r.db.Exec(ctx, `UPDATE listings SET status='sold', updated_at=now() WHERE id=$1`, id)
+// changeListingStatus updates the listing if it is still in c.From and adds
+// the history row, both on q so callers can make it part of a larger
+// transaction (accepting an offer).
+func changeListingStatus(ctx context.Context, q querier, c repository.StatusChange) error {
+	if c.From == domain.ListingReserved && c.To != domain.ListingSold {
+		// Runs first, while the listing still names the buyer. If the listing
+		// update below conflicts, the caller rolls this back with it.
+		if _, err := q.Exec(ctx, `
+			UPDATE offers o SET status='cancelled', updated_at=now()
+			FROM listings l
+			WHERE l.id=$1 AND l.status='reserved'
+			  AND o.listing_id=l.id AND o.buyer_id=l.reserved_by AND o.status='accepted'`, c.ListingID); err != nil {
+			return err
+		}
+	}
+	sets := []string{"status=$3", "updated_at=now()"}
+	args := []any{c.ListingID, string(c.From), string(c.To)}
+	switch {
+	case c.To == domain.ListingReserved:
+		sets = append(sets, "reserved_by=$4", "reserved_until=$5")
+		args = append(args, c.ReservedBy, c.ReservedUntil)
+	case c.To == domain.ListingSold && c.ReservedBy != nil:
+		sets = append(sets, "reserved_by=COALESCE(reserved_by, $4)", "reserved_until=NULL")
+		args = append(args, c.ReservedBy)
+	default:
+		sets = append(sets, reservationReset(c.To)...)
+	}
+	tag, err := q.Exec(ctx, fmt.Sprintf(`UPDATE listings SET %s WHERE id=$1 AND status=$2`, strings.Join(sets, ", ")), args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		var exists bool
+		if err := q.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM listings WHERE id=$1)`, c.ListingID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return domain.ErrNotFound
+		}
+		return domain.ErrConflict
+	}
+	_, err = q.Exec(ctx, `
+		INSERT INTO listing_status_history (id, listing_id, from_status, to_status, changed_by, reason)
+		VALUES ($1,$2,$3,$4,$5,$6)`, uuid.New(), c.ListingID, string(c.From), string(c.To), c.ChangedBy, c.Reason)
 	return err
 }
 
-func (r *ListingRepoPG) SoftDelete(ctx context.Context, id uuid.UUID) error {
-	_, err := r.db.Exec(ctx, `UPDATE listings SET status='removed', updated_at=now() WHERE id=$1`, id)
-	return err
+// reservationReset clears what no longer applies once a listing leaves
+// reserved: the expiry always, and the buyer unless it was sold to them.
+func reservationReset(to domain.ListingStatus) []string {
+	if to == domain.ListingSold {
+		return []string{"reserved_until=NULL"}
+	}
+	return []string{"reserved_by=NULL", "reserved_until=NULL"}
+}
+
+func (r *ListingRepoPG) StatusHistory(ctx context.Context, id uuid.UUID, limit, offset int) ([]domain.ListingStatusChange, int, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, listing_id, from_status, to_status, changed_by, reason, created_at
+		FROM listing_status_history
+		WHERE listing_id=$1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`, id, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	out := []domain.ListingStatusChange{}
+	for rows.Next() {
+		var h domain.ListingStatusChange
+		var from, to string
+		if err := rows.Scan(&h.ID, &h.ListingID, &from, &to, &h.ChangedBy, &h.Reason, &h.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		h.FromStatus, h.ToStatus = domain.ListingStatus(from), domain.ListingStatus(to)
+		out = append(out, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT count(*) FROM listing_status_history WHERE listing_id=$1`, id).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return out, total, nil
+}
+
+// ExpireReservations releases lapsed reservations, expires the accepted
+// offers behind them and records each release as a system change, all in the
+// same statement.
+func (r *ListingRepoPG) ExpireReservations(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(ctx, `
+		WITH lapsed AS (
+		  SELECT id, reserved_by FROM listings
+		  WHERE status='reserved' AND reserved_until <= $1
+		  FOR UPDATE
+		), expired AS (
+		  UPDATE listings l SET status='active', reserved_by=NULL, reserved_until=NULL, updated_at=now()
+		  FROM lapsed WHERE l.id=lapsed.id
+		  RETURNING l.id
+		), offers_expired AS (
+		  UPDATE offers o SET status='expired', updated_at=now()
+		  FROM lapsed
+		  WHERE o.listing_id=lapsed.id AND o.buyer_id=lapsed.reserved_by AND o.status='accepted'
+		), logged AS (
+		  INSERT INTO listing_status_history (id, listing_id, from_status, to_status, reason)
+		  SELECT gen_random_uuid(), id, 'reserved', 'active', 'reservation expired' FROM expired
+		)
+		SELECT id FROM expired`, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *ListingRepoPG) CountBySeller(ctx context.Context, sellerID uuid.UUID) (map[domain.ListingStatus]int, error) {
+	rows, err := r.db.Query(ctx, `SELECT status, count(*) FROM listings WHERE seller_id=$1 GROUP BY status`, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := map[domain.ListingStatus]int{}
+	for rows.Next() {
+		var status string
+		var n int
+		if err := rows.Scan(&status, &n); err != nil {
+			return nil, err
+		}
+		out[domain.ListingStatus(status)] = n
+	}
+	return out, rows.Err()
+}
+
+func scanListingWithHighlight(row pgx.Row) (domain.Listing, error) {
+	var l domain.Listing
+	var cond, status string
+	var h domain.ListingHighlight
+	err := row.Scan(&l.ID, &l.SellerID, &l.Title, &l.Description, &l.Category, &l.Price, &cond, &status,
+		&l.ReservedBy, &l.ReservedUntil, &l.CreatedAt, &l.UpdatedAt, &h.Title, &h.Description)
+	if err != nil {
+		return domain.Listing{}, err
+	}
+	l.Condition = domain.Condition(cond)
+	l.Status = domain.ListingStatus(status)
+	l.Highlight = &h
+	return l, nil
 }
 
 func scanListing(row pgx.Row) (domain.Listing, error) {
 	var l domain.Listing
 	var cond string
 	var status string
-	err := row.Scan(&l.ID, &l.SellerID, &l.Title, &l.Description, &l.Category, &l.Price, &cond, &status, &l.CreatedAt, &l.UpdatedAt)
+	err := row.Scan(&l.ID, &l.SellerID, &l.Title, &l.Description, &l.Category, &l.Price, &cond, &status,
+		&l.ReservedBy, &l.ReservedUntil, &l.CreatedAt, &l.UpdatedAt)
 	if err != nil {
 		return domain.Listing{}, err
 	}
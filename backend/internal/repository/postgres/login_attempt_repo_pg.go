@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+)
+
+type LoginAttemptRepoPG struct{ db *pgxpool.Pool }
+
+func NewLoginAttemptRepo(db *pgxpool.Pool) *LoginAttemptRepoPG { return &LoginAttemptRepoPG{db: db} }
+
+func (r *LoginAttemptRepoPG) Get(ctx context.Context, key string) (domain.LoginAttempts, error) {
+	var a domain.LoginAttempts
+	err := r.db.QueryRow(ctx, `
+		SELECT failures, last_failed_at, locked_until
+		FROM login_attempts WHERE key=$1`, key).Scan(&a.Failures, &a.LastFailedAt, &a.LockedUntil)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.LoginAttempts{}, nil
+	}
+	return a, err
+}
+
+func (r *LoginAttemptRepoPG) RecordFailure(ctx context.Context, key string, at, staleBefore time.Time) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO login_attempts (key, failures, last_failed_at) VALUES ($1, 1, $2)
+		ON CONFLICT (key) DO UPDATE SET
+		  failures = CASE WHEN login_attempts.last_failed_at < $3 THEN 1 ELSE login_attempts.failures + 1 END,
+		  last_failed_at = $2,
+		  locked_until = CASE WHEN login_attempts.last_failed_at < $3 THEN NULL ELSE login_attempts.locked_until END
+		RETURNING failures`, key, at, staleBefore).Scan(&n)
+	return n, err
+}
+
+func (r *LoginAttemptRepoPG) Lock(ctx context.Context, key string, now, until time.Time) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE login_attempts SET locked_until=$3
+		WHERE key=$1 AND (locked_until IS NULL OR locked_until <= $2)`, key, now, until)
+	return tag.RowsAffected() > 0, err
+}
+
+func (r *LoginAttemptRepoPG) Clear(ctx context.Context, key string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM login_attempts WHERE key=$1`, key)
+	return err
+}
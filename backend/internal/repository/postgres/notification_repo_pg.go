@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+)
+
+type NotificationRepoPG struct{ db *pgxpool.Pool }
+
+func NewNotificationRepo(db *pgxpool.Pool) *NotificationRepoPG { return &NotificationRepoPG{db: db} }
+
+const notificationCols = `id, user_id, kind, title, body, data, read_at, created_at`
+
+func (r *NotificationRepoPG) Create(ctx context.Context, in repository.CreateNotification) (domain.Notification, error) {
+	// A nil RawMessage would be sent as JSON null; store SQL NULL instead.
+	var data any
+	if len(in.Data) > 0 {
+		data = string(in.Data)
+	}
+	return scanNotification(r.db.QueryRow(ctx, `
+		INSERT INTO notifications (id, user_id, kind, title, body, data)
+		VALUES ($1,$2,$3,$4,$5,$6::jsonb)
+		RETURNING `+notificationCols, uuid.New(), in.UserID, in.Kind, in.Title, in.Body, data))
+}
+
+func (r *NotificationRepoPG) List(ctx context.Context, p repository.NotificationListParams) ([]domain.Notification, int, int, error) {
+	afterAt, afterID, err := createdKeyset(p.Cursor)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	offset := p.Offset
+	if afterAt != nil {
+		offset = 0
+	}
+	rows, err := r.db.Query(ctx, `
+		SELECT `+notificationCols+`
+		FROM notifications
+		WHERE user_id=$1 AND (NOT $2 OR read_at IS NULL)
+		  AND ($5::timestamptz IS NULL OR (created_at, id) < ($5::timestamptz, $6::uuid))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4`, p.UserID, p.UnreadOnly, p.Limit, offset, afterAt, afterID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer rows.Close()
+	out := []domain.Notification{}
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		out = append(out, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, 0, err
+	}
+
+	var total, unread int
+	if err := r.db.QueryRow(ctx, `
+		SELECT count(*) FILTER (WHERE NOT $2 OR read_at IS NULL), count(*) FILTER (WHERE read_at IS NULL)
+		FROM notifications WHERE user_id=$1`, p.UserID, p.UnreadOnly).Scan(&total, &unread); err != nil {
+		return nil, 0, 0, err
+	}
+	return out, total, unread, nil
+}
+
+func (r *NotificationRepoPG) MarkRead(ctx context.Context, userID, id uuid.UUID) (domain.Notification, error) {
+	n, err := scanNotification(r.db.QueryRow(ctx, `
+		UPDATE notifications SET read_at=COALESCE(read_at, now())
+		WHERE id=$1 AND user_id=$2
+		RETURNING `+notificationCols, id, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Notification{}, domain.ErrNotFound
+	}
+	return n, err
+}
+
+func (r *NotificationRepoPG) MarkAllRead(ctx context.Context, userID uuid.UUID) (int, error) {
+	tag, err := r.db.Exec(ctx, `UPDATE notifications SET read_at=now() WHERE user_id=$1 AND read_at IS NULL`, userID)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+func scanNotification(row pgx.Row) (domain.Notification, error) {
+	var n domain.Notification
+	var data []byte
+	err := row.Scan(&n.ID, &n.UserID, &n.Kind, &n.Title, &n.Body, &data, &n.ReadAt, &n.CreatedAt)
+	if len(data) > 0 {
+		n.Data = data
+	}
+	return n, err
+}
@@ -0,0 +1,205 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+)
+
+type OfferRepoPG struct{ db *pgxpool.Pool }
+
+func NewOfferRepo(db *pgxpool.Pool) *OfferRepoPG { return &OfferRepoPG{db: db} }
+
+const offerCols = `id, listing_id, buyer_id, seller_id, amount, message, status, created_at, updated_at`
+
+func (r *OfferRepoPG) Create(ctx context.Context, in repository.CreateOffer) (domain.Offer, error) {
+	id := uuid.New()
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO offers (id, listing_id, buyer_id, seller_id, amount, message)
+		VALUES ($1,$2,$3,$4,$5,$6)`, id, in.ListingID, in.BuyerID, in.SellerID, in.Amount, in.Message)
+	if isUniqueViolation(err) {
+		return domain.Offer{}, domain.ErrConflict
+	}
+	if err != nil {
+		return domain.Offer{}, err
+	}
+	return r.Get(ctx, id)
+}
+
+func (r *OfferRepoPG) Get(ctx context.Context, id uuid.UUID) (domain.Offer, error) {
+	o, err := scanOffer(r.db.QueryRow(ctx, `SELECT `+offerCols+` FROM offers WHERE id=$1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Offer{}, domain.ErrNotFound
+	}
+	return o, err
+}
+
+func (r *OfferRepoPG) List(ctx context.Context, p repository.OfferListParams) ([]domain.Offer, int, error) {
+	var where []string
+	var args []any
+	add := func(cond string, v any) {
+		args = append(args, v)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+	if p.ListingID != nil {
+		add("listing_id = $%d", *p.ListingID)
+	}
+	if p.BuyerID != nil {
+		add("buyer_id = $%d", *p.BuyerID)
+	}
+	if p.SellerID != nil {
+		add("seller_id = $%d", *p.SellerID)
+	}
+	if p.Status != "" {
+		add("status = $%d", p.Status)
+	}
+	clause := "TRUE"
+	if len(where) > 0 {
+		clause = strings.Join(where, " AND ")
+	}
+
+	limit := 20
+	if p.Limit > 0 {
+		limit = p.Limit
+	}
+	offset := 0
+	if p.Offset > 0 {
+		offset = p.Offset
+	}
+
+	rows, err := r.db.Query(ctx, fmt.Sprintf(`
+		SELECT %s FROM offers WHERE %s
+		ORDER BY created_at DESC, id DESC
+		LIMIT %d OFFSET %d`, offerCols, clause, limit, offset), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	var out []domain.Offer
+	for rows.Next() {
+		o, err := scanOffer(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		out = append(out, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT count(*) FROM offers WHERE `+clause, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return out, total, nil
+}
+
+func (r *OfferRepoPG) UpdateStatus(ctx context.Context, id uuid.UUID, from, to domain.OfferStatus, amount *float64, message *string) (domain.Offer, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE offers
+		SET status=$3, amount=COALESCE($4, amount), message=COALESCE($5, message), updated_at=now()
+		WHERE id=$1 AND status=$2`, id, string(from), string(to), amount, message)
+	if err != nil {
+		return domain.Offer{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.Offer{}, r.missingOrConflict(ctx, id)
+	}
+	return r.Get(ctx, id)
+}
+
+func (r *OfferRepoPG) Accept(ctx context.Context, id uuid.UUID, from domain.OfferStatus, by uuid.UUID, reservedUntil time.Time) (domain.Offer, []domain.Offer, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.Offer{}, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var listingID, buyerID uuid.UUID
+	err = tx.QueryRow(ctx, `
+		UPDATE offers SET status='accepted', updated_at=now()
+		WHERE id=$1 AND status=$2
+		RETURNING listing_id, buyer_id`, id, string(from)).Scan(&listingID, &buyerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Offer{}, nil, r.missingOrConflict(ctx, id)
+	}
+	if err != nil {
+		return domain.Offer{}, nil, err
+	}
+
+	// Only an active listing can be reserved; if someone else got there
+	// first the whole acceptance is rolled back.
+	if err := changeListingStatus(ctx, tx, repository.StatusChange{
+		ListingID:     listingID,
+		From:          domain.ListingActive,
+		To:            domain.ListingReserved,
+		ChangedBy:     &by,
+		Reason:        "offer accepted",
+		ReservedBy:    &buyerID,
+		ReservedUntil: &reservedUntil,
+	}); err != nil {
+		return domain.Offer{}, nil, err
+	}
+
+	// The listing is spoken for, so nobody else's offer can be accepted.
+	rows, err := tx.Query(ctx, `
+		UPDATE offers SET status='rejected', updated_at=now()
+		WHERE listing_id=$1 AND id<>$2 AND status IN ('pending','countered')
+		RETURNING `+offerCols, listingID, id)
+	if err != nil {
+		return domain.Offer{}, nil, err
+	}
+	var rejected []domain.Offer
+	for rows.Next() {
+		o, err := scanOffer(rows)
+		if err != nil {
+			rows.Close()
+			return domain.Offer{}, nil, err
+		}
+		rejected = append(rejected, o)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return domain.Offer{}, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.Offer{}, nil, err
+	}
+	accepted, err := r.Get(ctx, id)
+	return accepted, rejected, err
+}
+
+// missingOrConflict explains a conditional update that touched no rows.
+func (r *OfferRepoPG) missingOrConflict(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.Get(ctx, id); err != nil {
+		return err
+	}
+	return domain.ErrConflict
+}
+
+func scanOffer(row pgx.Row) (domain.Offer, error) {
+	var o domain.Offer
+	var status string
+	err := row.Scan(&o.ID, &o.ListingID, &o.BuyerID, &o.SellerID, &o.Amount, &o.Message, &status, &o.CreatedAt, &o.UpdatedAt)
+	if err != nil {
+		return domain.Offer{}, err
+	}
+	o.Status = domain.OfferStatus(status)
+	return o, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
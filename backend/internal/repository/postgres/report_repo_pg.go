@@ -32,13 +32,21 @@ func (r *ReportRepoPG) GetByID(ctx context.Context, id uuid.UUID) (domain.Report
 	return out, err
 }
 
-func (r *ReportRepoPG) List(ctx context.Context, status string, limit, offset int) ([]domain.Report, int, error) {
+func (r *ReportRepoPG) List(ctx context.Context, status, cursor string, limit, offset int) ([]domain.Report, int, error) {
+	afterAt, afterID, err := createdKeyset(cursor)
+	if err != nil {
+		return nil, 0, err
+	}
+	if afterAt != nil {
+		offset = 0
+	}
 	rows, err := r.db.Query(ctx, `
 		SELECT id, listing_id, reporter_id, reason, status, created_at, updated_at
 		FROM reports
 		WHERE ($1 = '' OR status=$1)
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3`, status, limit, offset)
+		  AND ($4::timestamptz IS NULL OR (created_at, id) < ($4::timestamptz, $5::uuid))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`, status, limit, offset, afterAt, afterID)
 	if err != nil {
 		return nil, 0, err
 	}
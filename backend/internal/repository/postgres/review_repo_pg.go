@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+)
+
+type ReviewRepoPG struct{ db *pgxpool.Pool }
+
+func NewReviewRepo(db *pgxpool.Pool) *ReviewRepoPG { return &ReviewRepoPG{db: db} }
+
+const reviewCols = `id, listing_id, seller_id, buyer_id, rating, body, COALESCE(reply, ''), replied_at, created_at`
+
+func (r *ReviewRepoPG) Create(ctx context.Context, in repository.CreateReview) (domain.Review, error) {
+	id := uuid.New()
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO reviews (id, listing_id, seller_id, buyer_id, rating, body)
+		VALUES ($1,$2,$3,$4,$5,$6)`, id, in.ListingID, in.SellerID, in.BuyerID, in.Rating, in.Text)
+	if isUniqueViolation(err) {
+		return domain.Review{}, domain.ErrConflict
+	}
+	if err != nil {
+		return domain.Review{}, err
+	}
+	return r.Get(ctx, id)
+}
+
+func (r *ReviewRepoPG) Get(ctx context.Context, id uuid.UUID) (domain.Review, error) {
+	rv, err := scanReview(r.db.QueryRow(ctx, `SELECT `+reviewCols+` FROM reviews WHERE id=$1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Review{}, domain.ErrNotFound
+	}
+	return rv, err
+}
+
+func (r *ReviewRepoPG) ListBySeller(ctx context.Context, sellerID uuid.UUID, limit, offset int) ([]domain.Review, int, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+reviewCols+`
+		FROM reviews WHERE seller_id=$1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`, sellerID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	out := []domain.Review{}
+	for rows.Next() {
+		rv, err := scanReview(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		out = append(out, rv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT count(*) FROM reviews WHERE seller_id=$1`, sellerID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return out, total, nil
+}
+
+func (r *ReviewRepoPG) Reply(ctx context.Context, id uuid.UUID, text string) (domain.Review, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE reviews SET reply=$2, replied_at=now()
+		WHERE id=$1 AND reply IS NULL`, id, text)
+	if err != nil {
+		return domain.Review{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		if _, err := r.Get(ctx, id); err != nil {
+			return domain.Review{}, err
+		}
+		return domain.Review{}, domain.ErrConflict
+	}
+	return r.Get(ctx, id)
+}
+
+func (r *ReviewRepoPG) Summary(ctx context.Context, sellerID uuid.UUID) (domain.RatingSummary, error) {
+	var s domain.RatingSummary
+	err := r.db.QueryRow(ctx, `
+		SELECT COALESCE(round(avg(rating), 2), 0)::float8, count(*)
+		FROM reviews WHERE seller_id=$1`, sellerID).Scan(&s.Average, &s.Count)
+	return s, err
+}
+
+func scanReview(row pgx.Row) (domain.Review, error) {
+	var rv domain.Review
+	err := row.Scan(&rv.ID, &rv.ListingID, &rv.SellerID, &rv.BuyerID, &rv.Rating, &rv.Text, &rv.Reply, &rv.RepliedAt, &rv.CreatedAt)
+	return rv, err
+}
@@ -0,0 +1,216 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+)
+
+type SavedSearchRepoPG struct{ db *pgxpool.Pool }
+
+func NewSavedSearchRepo(db *pgxpool.Pool) *SavedSearchRepoPG { return &SavedSearchRepoPG{db: db} }
+
+const savedSearchCols = `id, user_id, name, q, category, condition, price_min::float8, price_max::float8, last_checked_at, created_at`
+
+func (r *SavedSearchRepoPG) Create(ctx context.Context, in repository.CreateSavedSearch) (domain.SavedSearch, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.SavedSearch{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	if in.Max > 0 {
+		// Holding the user's row makes their concurrent creates take turns
+		// between counting and inserting.
+		var n int
+		err := tx.QueryRow(ctx, `SELECT 1 FROM users WHERE id=$1 FOR UPDATE`, in.UserID).Scan(&n)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.SavedSearch{}, domain.ErrNotFound
+		}
+		if err != nil {
+			return domain.SavedSearch{}, err
+		}
+		if err := tx.QueryRow(ctx, `SELECT count(*) FROM saved_searches WHERE user_id=$1`, in.UserID).Scan(&n); err != nil {
+			return domain.SavedSearch{}, err
+		}
+		if n >= in.Max {
+			return domain.SavedSearch{}, domain.ErrConflict
+		}
+	}
+	s, err := scanSavedSearch(tx.QueryRow(ctx, `
+		INSERT INTO saved_searches (id, user_id, name, q, category, condition, price_min, price_max)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+		RETURNING `+savedSearchCols,
+		uuid.New(), in.UserID, in.Name, in.Q, in.Category, in.Condition, in.PriceMin, in.PriceMax))
+	if err != nil {
+		return domain.SavedSearch{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return domain.SavedSearch{}, err
+	}
+	return s, nil
+}
+
+func (r *SavedSearchRepoPG) Get(ctx context.Context, id uuid.UUID) (domain.SavedSearch, error) {
+	s, err := scanSavedSearch(r.db.QueryRow(ctx, `SELECT `+savedSearchCols+` FROM saved_searches WHERE id=$1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.SavedSearch{}, domain.ErrNotFound
+	}
+	return s, err
+}
+
+func (r *SavedSearchRepoPG) ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.SavedSearch, int, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+savedSearchCols+`
+		FROM saved_searches WHERE user_id=$1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	out, err := collectSavedSearches(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT count(*) FROM saved_searches WHERE user_id=$1`, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return out, total, nil
+}
+
+func (r *SavedSearchRepoPG) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM saved_searches WHERE id=$1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SavedSearchRepoPG) Due(ctx context.Context, cutoff time.Time, after repository.DueCursor, limit int) ([]domain.SavedSearch, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+savedSearchCols+`
+		FROM saved_searches
+		WHERE last_checked_at < $1 AND (last_checked_at, id) > ($2, $3)
+		ORDER BY last_checked_at, id
+		LIMIT $4`, cutoff, after.CheckedAt, after.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return collectSavedSearches(rows)
+}
+
+func (r *SavedSearchRepoPG) RecordMatches(ctx context.Context, searchID uuid.UUID, listingIDs []uuid.UUID, checkedAt time.Time) ([]uuid.UUID, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var fresh []uuid.UUID
+	if len(listingIDs) > 0 {
+		rows, err := tx.Query(ctx, `
+			INSERT INTO saved_search_matches (search_id, listing_id)
+			SELECT $1, unnest($2::uuid[])
+			ON CONFLICT DO NOTHING
+			RETURNING listing_id`, searchID, listingIDs)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var id uuid.UUID
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			fresh = append(fresh, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE saved_searches SET last_checked_at=$2 WHERE id=$1`, searchID, checkedAt); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return fresh, nil
+}
+
+func (r *SavedSearchRepoPG) Matches(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.SavedSearchMatch, int, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT m.search_id, s.name, m.matched_at, `+qualify("l", listingCols)+`
+		FROM saved_search_matches m
+		JOIN saved_searches s ON s.id = m.search_id
+		JOIN listings l ON l.id = m.listing_id
+		WHERE s.user_id=$1
+		ORDER BY m.matched_at DESC, m.listing_id DESC
+		LIMIT $2 OFFSET $3`, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	out := []domain.SavedSearchMatch{}
+	for rows.Next() {
+		var m domain.SavedSearchMatch
+		var cond, status string
+		l := &m.Listing
+		if err := rows.Scan(&m.SearchID, &m.SearchName, &m.MatchedAt,
+			&l.ID, &l.SellerID, &l.Title, &l.Description, &l.Category, &l.Price, &cond, &status,
+			&l.ReservedBy, &l.ReservedUntil, &l.CreatedAt, &l.UpdatedAt); err != nil {
+			return nil, 0, err
+		}
+		l.Condition = domain.Condition(cond)
+		l.Status = domain.ListingStatus(status)
+		out = append(out, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	var total int
+	if err := r.db.QueryRow(ctx, `
+		SELECT count(*) FROM saved_search_matches m
+		JOIN saved_searches s ON s.id = m.search_id
+		WHERE s.user_id=$1`, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return out, total, nil
+}
+
+// qualify prefixes each column in a comma-separated list with alias.
+func qualify(alias, cols string) string {
+	return alias + "." + strings.ReplaceAll(cols, ", ", ", "+alias+".")
+}
+
+func collectSavedSearches(rows pgx.Rows) ([]domain.SavedSearch, error) {
+	defer rows.Close()
+	out := []domain.SavedSearch{}
+	for rows.Next() {
+		s, err := scanSavedSearch(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func scanSavedSearch(row pgx.Row) (domain.SavedSearch, error) {
+	var s domain.SavedSearch
+	err := row.Scan(&s.ID, &s.UserID, &s.Name, &s.Q, &s.Category, &s.Condition, &s.PriceMin, &s.PriceMax, &s.LastCheckedAt, &s.CreatedAt)
+	return s, err
+}
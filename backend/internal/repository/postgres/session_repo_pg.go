@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+)
+
+type SessionRepoPG struct{ db *pgxpool.Pool }
+
+func NewSessionRepo(db *pgxpool.Pool) *SessionRepoPG { return &SessionRepoPG{db: db} }
+
+const sessionCols = `id, user_id, user_agent, ip, created_at, last_used_at, expires_at`
+
+// liveSession matches sessions that can still be refreshed.
+const liveSession = `revoked_at IS NULL AND expires_at > now()`
+
+func (r *SessionRepoPG) Create(ctx context.Context, in repository.CreateSession) (domain.Session, error) {
+	return scanSession(r.db.QueryRow(ctx, `
+		INSERT INTO sessions (id, user_id, refresh_hash, user_agent, ip, expires_at)
+		VALUES ($1,$2,$3,$4,$5,$6)
+		RETURNING `+sessionCols,
+		in.ID, in.UserID, in.RefreshHash, in.UserAgent, in.IP, in.ExpiresAt))
+}
+
+func (r *SessionRepoPG) Rotate(ctx context.Context, in repository.RotateSession) (domain.Session, error) {
+	s, err := scanSession(r.db.QueryRow(ctx, `
+		UPDATE sessions
+		SET prev_refresh_hash=refresh_hash, refresh_hash=$2,
+		    user_agent=$3, ip=$4, last_used_at=now(), expires_at=$5
+		WHERE refresh_hash=$1 AND `+liveSession+`
+		RETURNING `+sessionCols,
+		in.OldHash, in.NewHash, in.UserAgent, in.IP, in.ExpiresAt))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Session{}, domain.ErrNotFound
+	}
+	return s, err
+}
+
+func (r *SessionRepoPG) ByPreviousHash(ctx context.Context, hash string) (domain.Session, error) {
+	s, err := scanSession(r.db.QueryRow(ctx, `
+		SELECT `+sessionCols+` FROM sessions WHERE prev_refresh_hash=$1`, hash))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Session{}, domain.ErrNotFound
+	}
+	return s, err
+}
+
+func (r *SessionRepoPG) Active(ctx context.Context, userID uuid.UUID) ([]domain.Session, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+sessionCols+` FROM sessions
+		WHERE user_id=$1 AND `+liveSession+`
+		ORDER BY last_used_at DESC, id DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []domain.Session{}
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func (r *SessionRepoPG) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE sessions SET revoked_at=now()
+		WHERE id=$1 AND user_id=$2 AND `+liveSession, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SessionRepoPG) RevokeAll(ctx context.Context, userID, keep uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE sessions SET revoked_at=now()
+		WHERE user_id=$1 AND id<>$2 AND `+liveSession, userID, keep)
+	return err
+}
+
+func (r *SessionRepoPG) RevokeToken(ctx context.Context, jti string, exp time.Time) error {
+	return revokeToken(ctx, r.db, jti, exp)
+}
+
+func (r *SessionRepoPG) IsRevoked(ctx context.Context, jti string, sessionID uuid.UUID) (bool, error) {
+	var revoked bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti=$1)
+		    OR EXISTS (SELECT 1 FROM sessions WHERE id=$2 AND revoked_at IS NOT NULL)`, jti, sessionID).Scan(&revoked)
+	return revoked, err
+}
+
+// revokeToken records jti and, while at it, forgets tokens that have expired
+// anyway so the list stays small.
+func revokeToken(ctx context.Context, db querier, jti string, exp time.Time) error {
+	if _, err := db.Exec(ctx, `DELETE FROM revoked_tokens WHERE expires_at < now()`); err != nil {
+		return err
+	}
+	_, err := db.Exec(ctx, `
+		INSERT INTO revoked_tokens (jti, expires_at) VALUES ($1,$2)
+		ON CONFLICT (jti) DO NOTHING`, jti, exp)
+	return err
+}
+
+func scanSession(row pgx.Row) (domain.Session, error) {
+	var s domain.Session
+	err := row.Scan(&s.ID, &s.UserID, &s.UserAgent, &s.IP, &s.CreatedAt, &s.LastUsedAt, &s.ExpiresAt)
+	return s, err
+}
@@ -2,9 +2,13 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -12,11 +16,13 @@ type AuthRepoPG struct{ db *pgxpool.Pool }
 
 func NewAuthRepo(db *pgxpool.Pool) *AuthRepoPG { return &AuthRepoPG{db} }
 
-func (r *AuthRepoPG) CreateUser(ctx context.Context, name, email, role, passwordHash string) (domain.User, error) {
+const userCols = `id, name, email, role, bio, COALESCE(avatar_key, ''), email_verified_at IS NOT NULL, created_at`
+
+func (r *AuthRepoPG) CreateUser(ctx context.Context, name, email, role, passwordHash string, verified bool) (domain.User, error) {
 	id := uuid.New()
 	_, err := r.db.Exec(ctx, `
-		INSERT INTO users (id, name, email, role, password_hash)
-		VALUES ($1,$2,$3,$4,$5)`, id, name, email, role, passwordHash)
+		INSERT INTO users (id, name, email, role, password_hash, email_verified_at)
+		VALUES ($1,$2,$3,$4,$5, CASE WHEN $6::boolean THEN now() END)`, id, name, email, role, passwordHash, verified)
 	if err != nil {
 		return domain.User{}, err
 	}
@@ -27,17 +33,141 @@ func (r *AuthRepoPG) GetByEmail(ctx context.Context, email string) (domain.User,
 	var u domain.User
 	var hash string
 	err := r.db.QueryRow(ctx, `
-		SELECT id, name, email, role, created_at, password_hash
+		SELECT `+userCols+`, password_hash
 		FROM users WHERE email=$1`, email).
-		Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.CreatedAt, &hash)
+		Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.Bio, &u.AvatarKey, &u.EmailVerified, &u.CreatedAt, &hash)
 	return u, hash, err
 }
 
 func (r *AuthRepoPG) GetByID(ctx context.Context, id uuid.UUID) (domain.User, error) {
 	var u domain.User
 	err := r.db.QueryRow(ctx, `
-		SELECT id, name, email, role, created_at
+		SELECT `+userCols+`
 		FROM users WHERE id=$1`, id).
-		Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.CreatedAt)
+		Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.Bio, &u.AvatarKey, &u.EmailVerified, &u.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.User{}, domain.ErrNotFound
+	}
 	return u, err
 }
+
+func (r *AuthRepoPG) UpdateProfile(ctx context.Context, id uuid.UUID, p repository.UpdateProfile) (domain.User, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE users
+		SET name=COALESCE($2, name), bio=COALESCE($3, bio),
+		    avatar_key=CASE WHEN $4::text IS NULL THEN avatar_key ELSE NULLIF($4::text, '') END
+		WHERE id=$1`, id, p.Name, p.Bio, p.AvatarKey)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.User{}, domain.ErrNotFound
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *AuthRepoPG) CreateVerification(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO email_verifications (token_hash, user_id, expires_at)
+		VALUES ($1,$2,$3)`, tokenHash, userID, expiresAt)
+	return err
+}
+
+func (r *AuthRepoPG) ConsumeVerification(ctx context.Context, tokenHash string) (domain.User, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return domain.User{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	var userID uuid.UUID
+	err = tx.QueryRow(ctx, `
+		UPDATE email_verifications SET used_at=now()
+		WHERE token_hash=$1 AND used_at IS NULL AND expires_at > now()
+		RETURNING user_id`, tokenHash).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.User{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.User{}, err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE users SET email_verified_at=COALESCE(email_verified_at, now()) WHERE id=$1`, userID); err != nil {
+		return domain.User{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return domain.User{}, err
+	}
+	return r.GetByID(ctx, userID)
+}
+
+func (r *AuthRepoPG) PasswordHash(ctx context.Context, id uuid.UUID) (string, error) {
+	var hash string
+	err := r.db.QueryRow(ctx, `SELECT password_hash FROM users WHERE id=$1`, id).Scan(&hash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", domain.ErrNotFound
+	}
+	return hash, err
+}
+
+func (r *AuthRepoPG) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	return updatePassword(ctx, r.db, id, passwordHash)
+}
+
+func updatePassword(ctx context.Context, db querier, id uuid.UUID, passwordHash string) error {
+	tag, err := db.Exec(ctx, `UPDATE users SET password_hash=$2 WHERE id=$1`, id, passwordHash)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *AuthRepoPG) CreatePasswordReset(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO password_resets (token_hash, user_id, expires_at)
+		VALUES ($1,$2,$3)`, tokenHash, userID, expiresAt)
+	return err
+}
+
+func (r *AuthRepoPG) ConsumePasswordReset(ctx context.Context, tokenHash, passwordHash string) (uuid.UUID, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var userID uuid.UUID
+	err = tx.QueryRow(ctx, `
+		UPDATE password_resets SET used_at=now()
+		WHERE token_hash=$1 AND used_at IS NULL AND expires_at > now()
+		RETURNING user_id`, tokenHash).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	// Other links mailed before this one must not undo the new password.
+	if _, err := tx.Exec(ctx, `
+		UPDATE password_resets SET used_at=now()
+		WHERE user_id=$1 AND used_at IS NULL`, userID); err != nil {
+		return uuid.Nil, err
+	}
+	if err := updatePassword(ctx, tx, userID, passwordHash); err != nil {
+		return uuid.Nil, err
+	}
+	// Whoever asked for the reset may not be the only one holding the old
+	// password, so no session outlives it.
+	if _, err := tx.Exec(ctx, `
+		UPDATE sessions SET revoked_at=now()
+		WHERE user_id=$1 AND `+liveSession, userID); err != nil {
+		return uuid.Nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, err
+	}
+	return userID, nil
+}
@@ -9,6 +9,7 @@ import (
 
 type ReportRepo interface {
 	Create(ctx context.Context, listingID, reporterID uuid.UUID, reason string) (domain.Report, error)
-	List(ctx context.Context, status string, limit, offset int) ([]domain.Report, int, error)
+	// List pages newest first, by offset or, when cursor is set, after it.
+	List(ctx context.Context, status, cursor string, limit, offset int) ([]domain.Report, int, error)
 	UpdateStatus(ctx context.Context, id uuid.UUID, status string) (domain.Report, error)
 }
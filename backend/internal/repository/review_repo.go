@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+)
+
+type ReviewRepo interface {
+	// Create returns domain.ErrConflict if the listing was already reviewed.
+	Create(ctx context.Context, in CreateReview) (domain.Review, error)
+	Get(ctx context.Context, id uuid.UUID) (domain.Review, error)
+	// ListBySeller returns the seller's reviews, newest first.
+	ListBySeller(ctx context.Context, sellerID uuid.UUID, limit, offset int) ([]domain.Review, int, error)
+	// Reply sets the seller's answer; domain.ErrConflict if there already is one.
+	Reply(ctx context.Context, id uuid.UUID, text string) (domain.Review, error)
+	Summary(ctx context.Context, sellerID uuid.UUID) (domain.RatingSummary, error)
+}
+
+type CreateReview struct {
+	ListingID uuid.UUID
+	SellerID  uuid.UUID
+	BuyerID   uuid.UUID
+	Rating    int
+	Text      string
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+)
+
+type SavedSearchRepo interface {
+	// Create stores the search unless the user already has in.Max of them,
+	// in which case it returns domain.ErrConflict. The count and the insert
+	// are serialized per user, so concurrent creates can't overshoot.
+	Create(ctx context.Context, in CreateSavedSearch) (domain.SavedSearch, error)
+	Get(ctx context.Context, id uuid.UUID) (domain.SavedSearch, error)
+	ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.SavedSearch, int, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	// Due returns up to limit searches last checked before cutoff, oldest
+	// check first, starting after the one after names; the zero DueCursor
+	// starts at the beginning.
+	Due(ctx context.Context, cutoff time.Time, after DueCursor, limit int) ([]domain.SavedSearch, error)
+	// RecordMatches stores listingIDs as matches of the search, moves its
+	// last check to checkedAt and returns the listing ids that were new.
+	RecordMatches(ctx context.Context, searchID uuid.UUID, listingIDs []uuid.UUID, checkedAt time.Time) ([]uuid.UUID, error)
+	// Matches lists matches across the user's saved searches, newest first.
+	Matches(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.SavedSearchMatch, int, error)
+}
+
+// DueCursor is the last search of a Due page, by (last_checked_at, id).
+type DueCursor struct {
+	CheckedAt time.Time
+	ID        uuid.UUID
+}
+
+type CreateSavedSearch struct {
+	UserID    uuid.UUID
+	Max       int // searches the user may have; 0 is no limit
+	Name      string
+	Q         string
+	Category  string
+	Condition string
+	PriceMin  *float64
+	PriceMax  *float64
+}
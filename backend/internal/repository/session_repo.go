@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+)
+
+type SessionRepo interface {
+	Create(ctx context.Context, in CreateSession) (domain.Session, error)
+	// Rotate swaps a live session's refresh token for a new one and moves
+	// its expiry to ExpiresAt. ErrNotFound if no live session holds OldHash.
+	Rotate(ctx context.Context, in RotateSession) (domain.Session, error)
+	// ByPreviousHash finds the session whose refresh token was last rotated
+	// away from hash; ErrNotFound if none.
+	ByPreviousHash(ctx context.Context, hash string) (domain.Session, error)
+	// Active lists the user's live sessions, most recently used first.
+	Active(ctx context.Context, userID uuid.UUID) ([]domain.Session, error)
+	// Revoke ends the user's session. ErrNotFound if it is not theirs or
+	// already over.
+	Revoke(ctx context.Context, userID, id uuid.UUID) error
+	// RevokeAll ends every live session of the user except keep (uuid.Nil
+	// keeps none).
+	RevokeAll(ctx context.Context, userID, keep uuid.UUID) error
+	// RevokeToken adds an access token to the revocation list until exp.
+	RevokeToken(ctx context.Context, jti string, exp time.Time) error
+	// IsRevoked reports whether the token jti, or the session it was issued
+	// for, was revoked.
+	IsRevoked(ctx context.Context, jti string, sessionID uuid.UUID) (bool, error)
+}
+
+// CreateSession takes the id up front: it goes into the access token.
+type CreateSession struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	RefreshHash string
+	UserAgent   string
+	IP          string
+	ExpiresAt   time.Time
+}
+
+type RotateSession struct {
+	OldHash   string
+	NewHash   string
+	UserAgent string
+	IP        string
+	ExpiresAt time.Time
+}
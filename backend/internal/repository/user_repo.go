@@ -2,13 +2,40 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
 )
 
 type AuthRepo interface {
-	CreateUser(ctx context.Context, name, email, role, passwordHash string) (domain.User, error)
+	// CreateUser adds a user; verified marks their email as already confirmed.
+	CreateUser(ctx context.Context, name, email, role, passwordHash string, verified bool) (domain.User, error)
 	GetByEmail(ctx context.Context, email string) (domain.User, string /*hash*/, error)
 	GetByID(ctx context.Context, id uuid.UUID) (domain.User, error)
+	UpdateProfile(ctx context.Context, id uuid.UUID, p UpdateProfile) (domain.User, error)
+	// CreateVerification stores an email verification token for the user.
+	CreateVerification(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error
+	// ConsumeVerification uses up the token and marks its user's email
+	// verified. ErrNotFound if the token is unknown, used or expired.
+	ConsumeVerification(ctx context.Context, tokenHash string) (domain.User, error)
+	// PasswordHash returns the user's bcrypt hash. ErrNotFound if no user.
+	PasswordHash(ctx context.Context, id uuid.UUID) (string, error)
+	// UpdatePassword replaces the user's password hash. ErrNotFound if no user.
+	UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error
+	// CreatePasswordReset stores a password reset token for the user.
+	CreatePasswordReset(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error
+	// ConsumePasswordReset uses up the token, sets its user's password hash,
+	// voids the user's other reset tokens and revokes all of their sessions,
+	// in one transaction. ErrNotFound if the token is unknown, used or
+	// expired.
+	ConsumePasswordReset(ctx context.Context, tokenHash, passwordHash string) (uuid.UUID, error)
+}
+
+// UpdateProfile holds the profile fields a user edits; nil leaves a field
+// as is and an empty AvatarKey removes the avatar.
+type UpdateProfile struct {
+	Name      *string
+	Bio       *string
+	AvatarKey *string
 }
@@ -5,14 +5,17 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
 )
 
 type AdminRepo interface {
 	CountListings(ctx context.Context) (int, error)
 	CountUsers(ctx context.Context) (int, error)
 	CountReportsByStatus(ctx context.Context) (open, reviewing, resolved, dismissed int, err error)
-	ListUsers(ctx context.Context, limit, offset int) ([]AdminUserRow, int, error)
-	ForceRemoveListing(ctx context.Context, listingID uuid.UUID) error
+	ListUsers(ctx context.Context, cursor string, limit, offset int) ([]AdminUserRow, int, error)
+	// ListAudit pages the audit log, newest first.
+	ListAudit(ctx context.Context, cursor string, limit int) ([]domain.AuditEntry, error)
 }
 
 type AdminUserRow struct {
@@ -23,9 +26,15 @@ type AdminUserRow struct {
 	CreatedAt time.Time `json:"createdAt"`
 }
 
-type AdminService struct{ repo AdminRepo }
+type AdminService struct {
+	repo     AdminRepo
+	listings *ListingService
+	auth     *AuthService
+}
 
-func NewAdminService(r AdminRepo) *AdminService { return &AdminService{repo: r} }
+func NewAdminService(r AdminRepo, listings *ListingService, auth *AuthService) *AdminService {
+	return &AdminService{repo: r, listings: listings, auth: auth}
+}
 
 type Metrics struct {
 	Listings int `json:"listings"`
@@ -51,10 +60,22 @@ func (s *AdminService) Metrics(ctx context.Context) (Metrics, error) {
 	return m, err
 }
 
-func (s *AdminService) Users(ctx context.Context, limit, offset int) ([]AdminUserRow, int, error) {
-	return s.repo.ListUsers(ctx, limit, offset)
+func (s *AdminService) Users(ctx context.Context, cursor string, limit, offset int) ([]AdminUserRow, int, error) {
+	return s.repo.ListUsers(ctx, cursor, limit, offset)
+}
+
+// ForceRemoveListing takes a listing down through the normal status rules,
+// so the removal shows up in its history.
+func (s *AdminService) ForceRemoveListing(ctx context.Context, actor Actor, id uuid.UUID) error {
+	_, err := s.listings.ChangeStatus(ctx, actor, id, domain.ListingRemoved, "removed by admin")
+	return err
+}
+
+// UnlockUser lifts a sign-in lockout on the user's account.
+func (s *AdminService) UnlockUser(ctx context.Context, actor Actor, id uuid.UUID) error {
+	return s.auth.UnlockAccount(ctx, actor, id)
 }
 
-func (s *AdminService) ForceRemoveListing(ctx context.Context, id uuid.UUID) error {
-	return s.repo.ForceRemoveListing(ctx, id)
+func (s *AdminService) Audit(ctx context.Context, cursor string, limit int) ([]domain.AuditEntry, error) {
+	return s.repo.ListAudit(ctx, cursor, limit)
 }
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+)
+
+// ====== Follow-up handling (uses ConversationMemory) ======
+
+// followUpPhrases mark a query that refines the previous search rather than
+// starting a new one.
+var followUpPhrases = []string{
+	"cheaper", "less expensive", "lower price", "more affordable", "budget",
+	"what about", "how about", "any other", "anything else", "other ones",
+	"show more", "more like", "instead", "condition", "like new", "brand new",
+	"under", "below", "less than", "over", "above", "at least", "max",
+}
+
+func isFollowUp(l string) bool {
+	for _, p := range followUpPhrases {
+		if strings.Contains(l, p) {
+			return true
+		}
+	}
+	return extractCondition(l) != ""
+}
+
+func isCheaperRequest(l string) bool {
+	for _, p := range []string{"cheaper", "less expensive", "lower price", "more affordable"} {
+		if strings.Contains(l, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractCondition maps condition words to domain.Condition values.
+func extractCondition(l string) string {
+	switch {
+	case strings.Contains(l, "like new") || strings.Contains(l, "likenew"):
+		return string(domain.CondLikeNew)
+	case strings.Contains(l, "brand new") || strings.Contains(l, "new condition") || strings.Contains(l, "in new"):
+		return string(domain.CondNew)
+	case strings.Contains(l, "good condition") || strings.Contains(l, "in good"):
+		return string(domain.CondGood)
+	case strings.Contains(l, "fair condition") || strings.Contains(l, "in fair"):
+		return string(domain.CondFair)
+	}
+	return ""
+}
+
+// followUpIntent refines the previous search without the LLM: explicit
+// prices and conditions replace the old ones, and "cheaper" caps the price
+// below what was shown last time.
+func followUpIntent(prev *ConversationState, l string) *SearchIntent {
+	next := prev.LastIntent.clone()
+
+	if minPrice, maxPrice := extractPriceFromText(l); minPrice != nil || maxPrice != nil {
+		next.MinPrice, next.MaxPrice = minPrice, maxPrice
+	} else if isCheaperRequest(l) {
+		if ceiling, ok := cheaperCeiling(prev); ok {
+			next.MaxPrice = &ceiling
+		}
+		next.MinPrice = nil
+	}
+	if c := extractCondition(l); c != "" {
+		next.Condition = c
+	}
+	return next
+}
+
+// cheaperCeiling is just under the cheapest listing shown last time, or 80%
+// of the previous max price when nothing was shown.
+func cheaperCeiling(prev *ConversationState) (float64, bool) {
+	if len(prev.LastPrices) > 0 {
+		return slices.Min(prev.LastPrices) - 0.01, true
+	}
+	if prev.LastIntent.MaxPrice != nil {
+		return *prev.LastIntent.MaxPrice * 0.8, true
+	}
+	return 0, false
+}
+
+// intentContext describes the previous search for the intent prompt.
+func intentContext(prev *ConversationState) string {
+	if prev == nil || prev.LastIntent == nil {
+		return ""
+	}
+	last, err := json.Marshal(prev.LastIntent)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf(`The user's previous search was:
+%s
+
+%sIf the new query is a follow-up (e.g. "any cheaper ones?", "what about in Good condition?"), start from the previous search and change only what the user asks to change. If it is about something new, ignore the previous search.
+
+`, last, formatHistory(prev.Turns))
+}
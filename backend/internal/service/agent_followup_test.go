@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFollowUpStaysInSession(t *testing.T) {
+	ctx := context.Background()
+	agent, repo := newTestAgent(NewOfflineProvider())
+	tab1 := ConversationKey{UserID: "u1", SessionID: "tab-1"}
+	tab2 := ConversationKey{UserID: "u1", SessionID: "tab-2"}
+
+	if _, results, err := agent.ProcessChat(ctx, tab1, "macbook under $1000"); err != nil || len(results) != 2 {
+		t.Fatalf("first search: %d results, err %v; want 2", len(results), err)
+	}
+	first, _ := repo.lastSearch()
+	searches := len(repo.searches)
+
+	// Another tab of the same user has no search to refine.
+	if _, results, err := agent.ProcessChat(ctx, tab2, "any cheaper ones?"); err != nil || len(results) != 0 {
+		t.Fatalf("other session: %d results, err %v; want none", len(results), err)
+	}
+	if len(repo.searches) != searches {
+		t.Fatal("follow-up in another session searched the listings")
+	}
+
+	_, results, err := agent.ProcessChat(ctx, tab1, "any cheaper ones?")
+	if err != nil {
+		t.Fatalf("follow-up: %v", err)
+	}
+	// The first search keeps the category; an empty result retries without.
+	p := repo.searches[searches]
+	if p.PriceMax == nil || *p.PriceMax != 449.99 {
+		t.Fatalf("follow-up max price = %v, want 449.99 (under the cheapest shown)", p.PriceMax)
+	}
+	if p.Q != first.Q || p.Category != first.Category {
+		t.Errorf("follow-up searched %q in %q, want the previous %q in %q", p.Q, p.Category, first.Q, first.Category)
+	}
+	if len(results) != 0 {
+		t.Errorf("follow-up returned %d results, want none under $449.99", len(results))
+	}
+
+	agent.ResetConversation(tab1)
+	searches = len(repo.searches)
+	if _, _, err := agent.ProcessChat(ctx, tab1, "any cheaper ones?"); err != nil {
+		t.Fatalf("after reset: %v", err)
+	}
+	if len(repo.searches) != searches {
+		t.Error("follow-up after reset refined the forgotten search")
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ====== "Notify me" (saved searches from the assistant) ======
+
+// notifyHint is appended when a search finds nothing and the assistant can
+// save it.
+const notifyHint = ` Want me to let you know when one is listed? Just say "notify me".`
+
+var notifyPhrases = []string{"notify me", "let me know when", "tell me when", "alert me"}
+
+func isNotifyRequest(l string) bool {
+	for _, p := range notifyPhrases {
+		if strings.Contains(l, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// EnableSavedSearches lets the assistant save the previous search when the
+// user says "notify me", so the saved search matcher alerts them later.
+func (s *AgentService) EnableSavedSearches(ss *SavedSearchService) { s.savedSearches = ss }
+
+// saveLastSearch saves prev's search for the conversation's user and returns
+// the assistant's answer.
+func (s *AgentService) saveLastSearch(ctx context.Context, conv ConversationKey, prev *ConversationState) string {
+	userID, err := uuid.Parse(conv.UserID)
+	if err != nil {
+		return "Sorry, I couldn't save that search."
+	}
+	in := prev.LastIntent
+	ss, err := s.savedSearches.Create(ctx, SaveSearchCmd{
+		UserID:    userID,
+		Name:      lastUserText(prev),
+		Q:         keywordSearchQuery(in.Keywords),
+		Category:  in.Category,
+		Condition: in.Condition,
+		PriceMin:  in.MinPrice,
+		PriceMax:  in.MaxPrice,
+	})
+	switch {
+	case errors.Is(err, ErrSavedSearchLimit):
+		return fmt.Sprintf("You already have %d saved searches. Remove one and ask me again.", MaxSavedSearches)
+	case err != nil:
+		s.logger.Error("save search from assistant failed", zap.String("userId", conv.UserID), zap.Error(err))
+		return "Sorry, I couldn't save that search. Please try again later."
+	}
+	return fmt.Sprintf("Done! I'll let you know when a new listing matches %q.", ss.Name)
+}
+
+// lastUserText is the user's most recent message, cut to a saved search name.
+func lastUserText(st *ConversationState) string {
+	for i := len(st.Turns) - 1; i >= 0; i-- {
+		if st.Turns[i].Role == "user" {
+			name := []rune(strings.TrimSpace(st.Turns[i].Text))
+			if len(name) > 100 {
+				name = name[:100]
+			}
+			return string(name)
+		}
+	}
+	return ""
+}
@@ -1,34 +1,31 @@
 package service
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/clock"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/s3client"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/pubsub"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
 	"go.uber.org/zap"
 )
 
-// Using v1 API with gemini-2.5-flash
-const geminiURL = "https://generativelanguage.googleapis.com/v1/models/gemini-2.5-flash:generateContent"
-
 // AgentService handles AI-powered search queries
 type AgentService struct {
-	apiKey        string
+	llm           LLMProvider // nil: heuristics and canned answers only
 	listingsRepo  repository.ListingRepo
 	imagesRepo    repository.ImageRepo // for primary image lookup
 	s3            *s3client.Client     // for presign
 	expiryMinutes int                  // presign expiry
+	memory        *ConversationMemory  // follow-up context per user session
+	savedSearches *SavedSearchService  // nil: no "notify me"
 	logger        *zap.Logger
 }
 
@@ -169,79 +166,80 @@ func filterListingsByStrongTokens(query string, listings []domain.Listing) []dom
 	return listings
 }
 
-// ====== Robust DB search with retries ======
-
-// searchListingsWithRetries tries multiple Q variants and relaxes filters if needed.
-func (s *AgentService) searchListingsWithRetries(ctx context.Context, intent *SearchIntent) ([]domain.Listing, error) {
-	base := repository.ListParams{
-		Category: intent.Category,
-		PriceMin: intent.MinPrice,
-		PriceMax: intent.MaxPrice,
-		Status:   "active",
-		Limit:    10,
-		Offset:   0,
-		Sort:     "created_desc",
+// ====== DB search ======
+
+// searchListings runs one search for intent. Keywords and their course-code
+// variants are OR'ed into a full-text query, so listings matching only some
+// of them still come back, best matches first; ranked reports that the
+// database ordered the results that way. The category is dropped only if it
+// leaves nothing.
+func (s *AgentService) searchListings(ctx context.Context, intent *SearchIntent) (items []domain.Listing, ranked bool, err error) {
+	p := repository.ListParams{
+		Category:  intent.Category,
+		Condition: intent.Condition,
+		PriceMin:  intent.MinPrice,
+		PriceMax:  intent.MaxPrice,
+		Status:    "active",
+		Limit:     10,
+		Sort:      "created_desc",
+	}
+	if q := keywordSearchQuery(intent.Keywords); q != "" {
+		p.Q = q
+		p.Sort = "relevance"
 	}
 
-	var variants []string
-	if len(intent.Keywords) > 0 {
-		// expanded keywords first (cmpe202 / cmpe 202 / cmpe-202)
-		variants = append(variants, strings.Join(expandKeywords(intent.Keywords), " "))
-		// raw keywords
-		variants = append(variants, strings.Join(intent.Keywords, " "))
+	s.logger.Info("search",
+		zap.String("q", p.Q),
+		zap.String("category", p.Category),
+		zap.String("condition", p.Condition),
+		zap.Any("min", p.PriceMin),
+		zap.Any("max", p.PriceMax),
+	)
+	ranked = p.Q != ""
+	items, _, err = s.listingsRepo.List(ctx, p)
+	if err != nil || len(items) > 0 || p.Category == "" {
+		return items, ranked, err
 	}
-	// also try with empty q
-	variants = append(variants, "")
 
-	type attempt struct {
-		p      repository.ListParams
-		reason string
-	}
-	var tries []attempt
+	s.logger.Info("search: no results in category, retrying without it", zap.String("category", p.Category))
+	p.Category = ""
+	items, _, err = s.listingsRepo.List(ctx, p)
+	return items, ranked, err
+}
 
-	// A: category + q variants
-	for _, q := range variants {
-		p := base
-		p.Q = q
-		tries = append(tries, attempt{p, "category+q"})
-	}
-	// B: drop category, keep q
-	for _, q := range variants {
-		p := base
-		p.Category = ""
-		p.Q = q
-		tries = append(tries, attempt{p, "q-only"})
-	}
-	// C: broad — drop category, drop q
-	tries = append(tries, attempt{base, "broad-no-q-no-category"})
-	tries[len(tries)-1].p.Category = ""
-	tries[len(tries)-1].p.Q = ""
-
-	for _, t := range tries {
-		s.logger.Info("search attempt",
-			zap.String("reason", t.reason),
-			zap.String("q", t.p.Q),
-			zap.String("category", t.p.Category),
-			zap.Any("min", t.p.PriceMin),
-			zap.Any("max", t.p.PriceMax),
-		)
-		items, _, err := s.listingsRepo.List(ctx, t.p)
-		if err != nil {
-			return nil, err
+// keywordSearchQuery builds a websearch_to_tsquery expression matching any
+// keyword: `cmpe202 or "cmpe 202" or textbook`.
+func keywordSearchQuery(keywords []string) string {
+	var terms []string
+	for _, k := range expandKeywords(keywords) {
+		k = strings.Trim(strings.ReplaceAll(k, `"`, ""), "-")
+		if k == "" || k == "or" {
+			continue
 		}
-		if len(items) > 0 {
-			return items, nil
+		if strings.Contains(k, " ") {
+			k = `"` + k + `"`
 		}
+		terms = append(terms, k)
 	}
-	return nil, nil
+	return strings.Join(terms, " or ")
 }
 
-// ====== Chat entrypoint (WS event: chat.message → pubsub.chat.request → here) ======
+// ====== Chat entrypoint (WS event: chat.request → pubsub.chat.request → here) ======
 
-func (s *AgentService) ProcessChat(ctx context.Context, text string) (string, []pubsub.ListingInfo, error) {
+func (s *AgentService) ProcessChat(ctx context.Context, conv ConversationKey, text string) (string, []pubsub.ListingInfo, error) {
 	// ProcessChat now uses the same logic as ProcessQuery for consistency
-	// Both use Gemini LLM for natural conversations and product search
-	return s.ProcessQuery(ctx, text)
+	// Both use the LLM for natural conversations and product search
+	return s.ProcessQuery(ctx, conv, text)
+}
+
+// ProcessChatStream is ProcessChat with the answer streamed to onDelta.
+func (s *AgentService) ProcessChatStream(ctx context.Context, conv ConversationKey, text string, onDelta func(AnswerDelta)) (string, []pubsub.ListingInfo, error) {
+	return s.ProcessQueryStream(ctx, conv, text, onDelta)
+}
+
+// ResetConversation forgets the session's previous searches and turns.
+func (s *AgentService) ResetConversation(conv ConversationKey) {
+	s.memory.Reset(conv)
 }
 
 func isGreeting(l string) bool {
@@ -416,6 +414,7 @@ func (s *AgentService) simpleIntentFromText(l string) *SearchIntent {
 	
 	// Extract prices from the query
 	intent.MinPrice, intent.MaxPrice = extractPriceFromText(l)
+	intent.Condition = extractCondition(l)
 	
 	switch {
 	case strings.Contains(l, "textbook") || strings.Contains(l, "book"):
@@ -431,73 +430,129 @@ func (s *AgentService) simpleIntentFromText(l string) *SearchIntent {
 // ====== Constructors ======
 
 // NewAgentService creates a basic agent service (no media enrichment)
-func NewAgentService(apiKey string, listingsRepo repository.ListingRepo, logger *zap.Logger) *AgentService {
+func NewAgentService(llm LLMProvider, listingsRepo repository.ListingRepo, logger *zap.Logger) *AgentService {
 	return &AgentService{
-		apiKey:       apiKey,
+		llm:          llm,
 		listingsRepo: listingsRepo,
+		memory:       NewConversationMemory(defaultConversationTTL, clock.Real{}),
 		logger:       logger,
 	}
 }
 
 // NewAgentServiceFull creates agent service with image + S3 enrichment
-func NewAgentServiceFull(apiKey string, listingsRepo repository.ListingRepo, imagesRepo repository.ImageRepo, s3 *s3client.Client, expiryMinutes int, logger *zap.Logger) *AgentService {
+func NewAgentServiceFull(llm LLMProvider, listingsRepo repository.ListingRepo, imagesRepo repository.ImageRepo, s3 *s3client.Client, expiryMinutes int, logger *zap.Logger) *AgentService {
 	return &AgentService{
-		apiKey:        apiKey,
+		llm:           llm,
 		listingsRepo:  listingsRepo,
 		imagesRepo:    imagesRepo,
 		s3:            s3,
 		expiryMinutes: expiryMinutes,
+		memory:        NewConversationMemory(defaultConversationTTL, clock.Real{}),
 		logger:        logger,
 	}
 }
 
-// ====== Gemini types ======
-
-type GeminiRequest struct {
-	Contents []GeminiContent `json:"contents"`
+// Parsed search intent
+type SearchIntent struct {
+	Category  string   `json:"category"`
+	Keywords  []string `json:"keywords"`
+	MinPrice  *float64 `json:"minPrice"`
+	MaxPrice  *float64 `json:"maxPrice"`
+	Condition string   `json:"condition"`
 }
 
-type GeminiContent struct {
-	Parts []GeminiPart `json:"parts"`
+func (i *SearchIntent) isEmpty() bool {
+	return len(i.Keywords) == 0 && i.Category == "" && i.MinPrice == nil && i.MaxPrice == nil && i.Condition == ""
 }
 
-type GeminiPart struct {
-	Text string `json:"text"`
+func (i *SearchIntent) clone() *SearchIntent {
+	c := *i
+	c.Keywords = append([]string(nil), i.Keywords...)
+	if i.MinPrice != nil {
+		v := *i.MinPrice
+		c.MinPrice = &v
+	}
+	if i.MaxPrice != nil {
+		v := *i.MaxPrice
+		c.MaxPrice = &v
+	}
+	return &c
 }
 
-type GeminiResponse struct {
-	Candidates []struct {
-		Content struct {
-			Parts []struct {
-				Text string `json:"text"`
-			} `json:"parts"`
-		} `json:"content"`
-	} `json:"candidates"`
+// ====== Agent entrypoint (WS event: agent.search → pubsub.agent.request → here) ======
+
+// ProcessQuery returns DB results enriched for frontend consumption
+// Now uses the LLM provider for natural conversations and enhanced product search responses
+// conv scopes follow-up context ("any cheaper ones?"); the zero key makes the
+// query stateless.
+func (s *AgentService) ProcessQuery(ctx context.Context, conv ConversationKey, query string) (string, []pubsub.ListingInfo, error) {
+	return s.processQuery(ctx, conv, query, nil)
 }
 
-// Parsed search intent
-type SearchIntent struct {
-	Category string   `json:"category"`
-	Keywords []string `json:"keywords"`
-	MinPrice *float64 `json:"minPrice"`
-	MaxPrice *float64 `json:"maxPrice"`
+// AnswerDelta is one chunk of a streamed answer. Replace means the chunks
+// sent so far are void and Text is the whole answer.
+type AnswerDelta struct {
+	Text    string
+	Replace bool
 }
 
-// ====== Agent entrypoint (WS event: agent.search → pubsub.agent.request → here) ======
+// ProcessQueryStream is ProcessQuery, but hands the answer to onDelta in
+// chunks as the LLM produces it. Without a streaming provider (or when the
+// canned fallback is used) the whole answer arrives as a single chunk. The
+// returned answer is authoritative: if streaming fails part-way it is the
+// fallback text, sent as a Replace delta so it doesn't follow the partial
+// chunks.
+func (s *AgentService) ProcessQueryStream(ctx context.Context, conv ConversationKey, query string, onDelta func(AnswerDelta)) (string, []pubsub.ListingInfo, error) {
+	return s.processQuery(ctx, conv, query, onDelta)
+}
 
-// ProcessQuery returns DB results enriched for frontend consumption
-// Now uses Gemini LLM for natural conversations and enhanced product search responses
-func (s *AgentService) ProcessQuery(ctx context.Context, query string) (string, []pubsub.ListingInfo, error) {
+func (s *AgentService) processQuery(ctx context.Context, conv ConversationKey, query string, onDelta func(AnswerDelta)) (string, []pubsub.ListingInfo, error) {
 	s.logger.Info("ProcessQuery called", zap.String("query", query))
 
+	// emit appends a chunk; sent remembers that one went out.
+	var emit func(string)
+	sent := false
+	if onDelta != nil {
+		emit = func(d string) {
+			sent = true
+			onDelta(AnswerDelta{Text: d})
+		}
+	}
+
 	t := strings.TrimSpace(query)
 	l := strings.ToLower(t)
 
+	var prev *ConversationState
+	if !conv.IsZero() {
+		if st, ok := s.memory.Get(conv); ok {
+			prev = &st
+		}
+	}
+
+	// "notify me" after a search saves it instead of searching again.
+	if s.savedSearches != nil && prev != nil && prev.LastIntent != nil && isNotifyRequest(l) {
+		answer := s.saveLastSearch(ctx, conv, prev)
+		if emit != nil {
+			emit(answer)
+		}
+		s.memory.Record(conv, t, answer, nil, nil)
+		return answer, []pubsub.ListingInfo{}, nil
+	}
+
 	// Check if this is a product search query
 	isProductSearch := s.isProductSearchQuery(l)
+
+	// "any cheaper ones?" has no product words of its own but refines the
+	// previous search.
+	followUp := !isProductSearch && prev != nil && prev.LastIntent != nil && isFollowUp(l)
+	if followUp {
+		s.logger.Info("Query is a follow-up to the previous search", zap.String("query", query))
+		isProductSearch = true
+	}
+	var intent *SearchIntent
 	
 	// ALWAYS perform database search if it's a product search query
-	// This ensures users get listings even if Gemini fails
+	// This ensures users get listings even if the LLM fails
 	var listings []domain.Listing
 	var results []pubsub.ListingInfo
 	var searchErr error
@@ -506,23 +561,28 @@ func (s *AgentService) ProcessQuery(ctx context.Context, query string) (string,
 		s.logger.Info("Query appears to be a product search, proceeding with search", zap.String("query", query))
 		
 		// Start with an empty intent
-		intent := &SearchIntent{Keywords: []string{}}
+		intent = &SearchIntent{Keywords: []string{}}
 
-		// 1) Try LLM-based intent extraction (Gemini)
-		if strings.TrimSpace(s.apiKey) != "" {
-			if aiIntent, err := s.extractSearchIntent(ctx, t); err == nil {
+		// 1) Try LLM-based intent extraction
+		if s.llm != nil {
+			if aiIntent, err := s.extractSearchIntent(ctx, t, prev); err == nil {
 				intent = aiIntent
 			} else {
-				s.logger.Error("Gemini intent extraction failed in ProcessQuery, using heuristic", zap.Error(err))
+				s.logger.Error("LLM intent extraction failed in ProcessQuery, using heuristic", zap.String("provider", s.llm.Name()), zap.Error(err))
 			}
 		} else {
-			s.logger.Warn("No LLM key configured in ProcessQuery, using heuristic intent extraction")
+			s.logger.Warn("No LLM provider configured in ProcessQuery, using heuristic intent extraction")
 		}
 
 		// 2) Heuristic backup if LLM gave us nothing useful
-		if len(intent.Keywords) == 0 && intent.Category == "" && intent.MinPrice == nil && intent.MaxPrice == nil {
-			s.logger.Info("ProcessQuery: LLM intent empty, falling back to simpleIntentFromText")
-			intent = s.simpleIntentFromText(l)
+		if intent.isEmpty() || (followUp && len(intent.Keywords) == 0 && intent.Category == "") {
+			if followUp {
+				s.logger.Info("ProcessQuery: refining previous intent heuristically")
+				intent = followUpIntent(prev, l)
+			} else {
+				s.logger.Info("ProcessQuery: LLM intent empty, falling back to simpleIntentFromText")
+				intent = s.simpleIntentFromText(l)
+			}
 		} else {
 			// Even if LLM extracted some fields, try to extract prices heuristically as backup
 			if intent.MinPrice == nil && intent.MaxPrice == nil {
@@ -543,31 +603,41 @@ func (s *AgentService) ProcessQuery(ctx context.Context, query string) (string,
 			zap.Strings("keywords", intent.Keywords),
 			zap.Any("minPrice", intent.MinPrice),
 			zap.Any("maxPrice", intent.MaxPrice),
+			zap.String("condition", intent.Condition),
 		)
 
 		// 3) Use the same robust search as chat
-		listings, searchErr = s.searchListingsWithRetries(ctx, intent)
+		var ranked bool
+		listings, ranked, searchErr = s.searchListings(ctx, intent)
 		if searchErr != nil {
 			s.logger.Error("Search failed", zap.Error(searchErr))
 		} else {
 			// 4) Narrow to course-specific listings if query contains "cmpe 202" etc.
 			listings = filterListingsByStrongTokens(query, listings)
 
-			// 5) Rank and filter by relevance to ensure precise matches
-			listings = s.rankAndFilterByRelevance(query, intent, listings)
+			// 5) Without keywords the database only filtered; rank by the
+			// query's words here. Full-text results keep the database's order.
+			if !ranked {
+				listings = s.rankAndFilterByRelevance(query, intent, listings)
+			}
 
 			// 6) Map to DTOs
 			results = s.toFullListingInfos(ctx, listings)
 		}
 	}
 
-	// Generate response using Gemini LLM (for both conversational and product search responses)
+	// Generate response using the LLM (for both conversational and product search responses)
 	var answer string
-	if strings.TrimSpace(s.apiKey) != "" {
-		// Use Gemini to generate natural response
-		geminiAnswer, err := s.generateGeminiResponse(ctx, query, listings, results, isProductSearch)
+	streamed := false
+	if s.llm != nil {
+		// Use the LLM to generate natural response
+		var history []ChatTurn
+		if prev != nil {
+			history = prev.Turns
+		}
+		llmAnswer, didStream, err := s.generateLLMResponse(ctx, query, history, listings, results, isProductSearch, emit)
 		if err != nil {
-			s.logger.Error("Gemini response generation failed, using fallback", zap.Error(err))
+			s.logger.Error("LLM response generation failed, using fallback", zap.String("provider", s.llm.Name()), zap.Error(err))
 			// Fallback to simple response
 			if isProductSearch {
 				// For product searches, always provide a response that mentions the results
@@ -580,10 +650,11 @@ func (s *AgentService) ProcessQuery(ctx context.Context, query string) (string,
 				answer = s.generateFallbackConversationalResponse(l)
 			}
 		} else {
-			answer = geminiAnswer
+			answer = llmAnswer
+			streamed = didStream
 		}
 	} else {
-		// No Gemini API key, use fallback
+		// No LLM provider, use fallback
 		if isProductSearch {
 			// For product searches, always provide a response that mentions the results
 			if len(results) > 0 {
@@ -596,6 +667,27 @@ func (s *AgentService) ProcessQuery(ctx context.Context, query string) (string,
 		}
 	}
 
+	if isProductSearch && searchErr == nil && len(results) == 0 && s.savedSearches != nil && !conv.IsZero() {
+		answer += notifyHint
+		if streamed {
+			emit(notifyHint)
+		}
+	}
+
+	if onDelta != nil && !streamed {
+		// A stream that failed part-way already showed some text, which the
+		// fallback answer must replace rather than follow.
+		onDelta(AnswerDelta{Text: answer, Replace: sent})
+	}
+
+	if !conv.IsZero() {
+		var prices []float64
+		for _, r := range results {
+			prices = append(prices, r.Price)
+		}
+		s.memory.Record(conv, t, answer, intent, prices)
+	}
+
 	// CRITICAL: Always return results for product searches, even if empty
 	// This ensures the frontend can display listings
 	s.logger.Info("ProcessQuery returning",
@@ -607,46 +699,19 @@ func (s *AgentService) ProcessQuery(ctx context.Context, query string) (string,
 	return answer, results, searchErr
 }
 
-// Fallback: simple extraction + DB query (no LLM key or LLM failure)
-func (s *AgentService) processWithoutChatGPT(ctx context.Context, query string) (string, []pubsub.ListingInfo, error) {
-	ql := strings.ToLower(strings.TrimSpace(query))
-
-	// Reuse the same heuristic intent builder
-	intent := s.simpleIntentFromText(ql)
-
-	s.logger.Info("processWithoutChatGPT intent",
-		zap.String("category", intent.Category),
-		zap.Strings("keywords", intent.Keywords),
-		zap.Any("min", intent.MinPrice),
-		zap.Any("max", intent.MaxPrice),
-	)
-
-	// Use the same robust search
-	listings, err := s.searchListingsWithRetries(ctx, intent)
-	if err != nil {
-		return "", nil, err
-	}
-
-	// Narrow by strong tokens if present
-	listings = filterListingsByStrongTokens(query, listings)
-
-	// Rank and filter by relevance
-	listings = s.rankAndFilterByRelevance(query, intent, listings)
-
-	results := s.toFullListingInfos(ctx, listings)
-	answer := s.generateAnswer(query, intent, len(results))
-	return answer, results, nil
-}
-
 // ====== LLM intent extraction ======
 
-func (s *AgentService) extractSearchIntent(ctx context.Context, query string) (*SearchIntent, error) {
+// extractSearchIntent asks the LLM for search parameters. With prev, the
+// previous search and recent turns are included so follow-ups are resolved
+// against them.
+func (s *AgentService) extractSearchIntent(ctx context.Context, query string, prev *ConversationState) (*SearchIntent, error) {
 	prompt := `You are a campus marketplace assistant. Analyze the user's query and extract search parameters.
 Return ONLY a valid JSON object with these fields:
 - category: one of "Textbooks", "Electronics", "Furniture", "Clothing", "Other", or "" if not specified
 - keywords: array of relevant search terms (remove filler words like "I want", "to buy", "need")
 - minPrice: minimum price as number or null
 - maxPrice: maximum price as number or null
+- condition: one of "New", "LikeNew", "Good", "Fair", or "" if not specified
 
 IMPORTANT: Extract prices from phrases like "under $500", "under 900$", "under 1600 dollars", "below $100", "less than $200", "max $300", "maximum $400", "up to $500", "at most $600", "cheaper than $700", "over $50", "above $100", "at least $200", "minimum $300", "more than $400".
 
@@ -666,80 +731,15 @@ Query: "i need macbook pro under 1600 dollars"
 Query: "cheap desk"
 {"category":"Furniture","keywords":["desk","cheap"],"minPrice":null,"maxPrice":null}
 
-Now analyze this query: ` + query
-
-	reqBody := GeminiRequest{
-		Contents: []GeminiContent{
-			{Parts: []GeminiPart{{Text: prompt}}},
-		},
-	}
-
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, err
-	}
-	url := fmt.Sprintf("%s?key=%s", geminiURL, s.apiKey)
-
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := (&http.Client{}).Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("gemini api error: %d - %s", resp.StatusCode, string(body))
-	}
-
-	var gr GeminiResponse
-	if err := json.NewDecoder(resp.Body).Decode(&gr); err != nil {
-		return nil, err
-	}
-	if len(gr.Candidates) == 0 || len(gr.Candidates[0].Content.Parts) == 0 {
-		return nil, fmt.Errorf("no response from gemini")
-	}
-
-	content := gr.Candidates[0].Content.Parts[0].Text
-	start := strings.Index(content, "{")
-	end := strings.LastIndex(content, "}")
-	if start == -1 || end == -1 {
-		return nil, fmt.Errorf("invalid json in gemini response")
-	}
-	jsonStr := content[start : end+1]
+` + intentContext(prev) + `Now analyze this query: ` + query
 
 	var intent SearchIntent
-	if err := json.Unmarshal([]byte(jsonStr), &intent); err != nil {
-		return nil, fmt.Errorf("failed to parse intent json: %w", err)
+	if err := s.llm.ExtractJSON(ctx, prompt, &intent); err != nil {
+		return nil, err
 	}
 	return &intent, nil
 }
 
-// ====== Optional simple search (still used by some code) ======
-
-// Build repo params and query DB (simple version)
-func (s *AgentService) searchListings(ctx context.Context, intent *SearchIntent) ([]domain.Listing, error) {
-	params := repository.ListParams{
-		Category: intent.Category,
-		PriceMin: intent.MinPrice,
-		PriceMax: intent.MaxPrice,
-		Status:   "active", // must match your data
-		Limit:    10,       // top results
-		Offset:   0,
-		Sort:     "created_desc", // same as HTTP default
-	}
-	if len(intent.Keywords) > 0 {
-		params.Q = strings.Join(intent.Keywords, " ")
-	}
-	listings, _, err := s.listingsRepo.List(ctx, params)
-	return listings, err
-}
-
 // ====== Relevance ranking and filtering ======
 
 // extractProductKeywords extracts the core product keywords from a natural language query
@@ -886,14 +886,8 @@ func (s *AgentService) rankAndFilterByRelevance(query string, intent *SearchInte
 		scored = append(scored, scoredListing{listing: listing, score: score})
 	}
 	
-	// Sort by score (descending)
-	for i := 0; i < len(scored)-1; i++ {
-		for j := i + 1; j < len(scored); j++ {
-			if scored[i].score < scored[j].score {
-				scored[i], scored[j] = scored[j], scored[i]
-			}
-		}
-	}
+	// Sort by score (descending), newest first among equals
+	slices.SortStableFunc(scored, func(a, b scoredListing) int { return b.score - a.score })
 	
 	// Filter: only keep listings with a minimum score
 	// For queries with multiple keywords, require at least 2 keywords to match
@@ -1026,22 +1020,24 @@ func (s *AgentService) isProductSearchQuery(query string) bool {
 		}
 	}
 	
-	// Default: not a product search (let Gemini handle it as conversational)
+	// Default: not a product search (let the LLM handle it as conversational)
 	return false
 }
 
-// ====== Gemini LLM Response Generation ======
+// ====== LLM Response Generation ======
 
-// generateGeminiResponse uses Gemini to generate natural, conversational responses
-func (s *AgentService) generateGeminiResponse(ctx context.Context, userQuery string, listings []domain.Listing, results []pubsub.ListingInfo, isProductSearch bool) (string, error) {
+// generateLLMResponse uses the LLM to generate natural, conversational responses.
+// With onDelta set and a streaming provider, chunks go to onDelta as they
+// arrive and streamed is true.
+func (s *AgentService) generateLLMResponse(ctx context.Context, userQuery string, history []ChatTurn, listings []domain.Listing, results []pubsub.ListingInfo, isProductSearch bool, onDelta func(string)) (answer string, streamed bool, err error) {
 	var prompt string
 	
 	if isProductSearch && len(results) > 0 {
-		// Product search with results - format listings for Gemini
-		listingsText := s.formatListingsForGemini(results)
+		// Product search with results - format listings for the LLM
+		listingsText := s.formatListingsForLLM(results)
 		prompt = fmt.Sprintf(`You are a helpful AI assistant for CampusHub, a campus marketplace where students buy and sell items.
 
-The user asked: "%s"
+%sThe user asked: "%s"
 
 I found %d matching listings in our database:
 
@@ -1054,12 +1050,12 @@ Please provide a natural, friendly, and helpful response that:
 4. Encourages them to check out the listings below
 5. Keep it conversational and not too long (2-3 sentences)
 
-Be friendly and helpful, like a real assistant would be.`, userQuery, len(results), listingsText)
+Be friendly and helpful, like a real assistant would be.`, formatHistory(history), userQuery, len(results), listingsText)
 	} else if isProductSearch && len(results) == 0 {
 		// Product search with no results
 		prompt = fmt.Sprintf(`You are a helpful AI assistant for CampusHub, a campus marketplace where students buy and sell items.
 
-The user asked: "%s"
+%sThe user asked: "%s"
 
 Unfortunately, I couldn't find any matching listings in our database.
 
@@ -1069,12 +1065,12 @@ Please provide a natural, friendly, and helpful response that:
 3. Suggests they try different keywords, adjust price range, or check back later
 4. Keep it conversational and encouraging (2-3 sentences)
 
-Be friendly and helpful, like a real assistant would be.`, userQuery)
+Be friendly and helpful, like a real assistant would be.`, formatHistory(history), userQuery)
 	} else {
 		// Conversational query - no product search
 		prompt = fmt.Sprintf(`You are a helpful AI assistant for CampusHub, a campus marketplace where students buy and sell items like textbooks, electronics, furniture, and more.
 
-The user said: "%s"
+%sThe user said: "%s"
 
 Please provide a natural, friendly, and conversational response. You can:
 - Answer questions about CampusHub
@@ -1082,61 +1078,47 @@ Please provide a natural, friendly, and conversational response. You can:
 - Chat naturally about general topics
 - Guide them on how to search for products (e.g., "I want to buy iPhone 17" or "MacBook under $500")
 
-Keep your response friendly, helpful, and conversational (2-4 sentences). Don't be too formal.`, userQuery)
-	}
-	
-	reqBody := GeminiRequest{
-		Contents: []GeminiContent{
-			{Parts: []GeminiPart{{Text: prompt}}},
-		},
-	}
-	
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", err
-	}
-	
-	url := fmt.Sprintf("%s?key=%s", geminiURL, s.apiKey)
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-	
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("gemini api error: %d - %s", resp.StatusCode, string(body))
+Keep your response friendly, helpful, and conversational (2-4 sentences). Don't be too formal.`, formatHistory(history), userQuery)
 	}
 	
-	var gr GeminiResponse
-	if err := json.NewDecoder(resp.Body).Decode(&gr); err != nil {
-		return "", err
+	if st, ok := s.llm.(LLMStreamer); ok && onDelta != nil {
+		ctx, cancel := context.WithTimeout(ctx, llmStreamTimeout)
+		defer cancel()
+		answer, err = st.Stream(ctx, prompt, onDelta)
+		return answer, true, err
+	}
+	answer, err = s.llm.Complete(ctx, prompt)
+	return answer, false, err
+}
+
+// formatHistory renders recent turns for the response prompt, or "" when
+// this is the first message of the session.
+func formatHistory(turns []ChatTurn) string {
+	if len(turns) == 0 {
+		return ""
 	}
-	
-	if len(gr.Candidates) == 0 || len(gr.Candidates[0].Content.Parts) == 0 {
-		return "", fmt.Errorf("no response from gemini")
+	var sb strings.Builder
+	sb.WriteString("Conversation so far:\n")
+	for _, t := range turns {
+		role := "User"
+		if t.Role == "assistant" {
+			role = "Assistant"
+		}
+		sb.WriteString(fmt.Sprintf("%s: %s\n", role, t.Text))
 	}
-	
-	response := strings.TrimSpace(gr.Candidates[0].Content.Parts[0].Text)
-	return response, nil
+	sb.WriteString("\n")
+	return sb.String()
 }
 
-// formatListingsForGemini formats listings in a way that's easy for Gemini to understand
-func (s *AgentService) formatListingsForGemini(results []pubsub.ListingInfo) string {
+// formatListingsForLLM formats listings in a way that's easy for the LLM to understand
+func (s *AgentService) formatListingsForLLM(results []pubsub.ListingInfo) string {
 	if len(results) == 0 {
 		return "No listings found."
 	}
 	
 	var sb strings.Builder
 	for i, listing := range results {
-		if i >= 10 { // Limit to top 10 for Gemini context
+		if i >= 10 { // Limit to top 10 for LLM context
 			break
 		}
 		sb.WriteString(fmt.Sprintf("\n%d. %s", i+1, listing.Title))
@@ -1157,7 +1139,7 @@ func (s *AgentService) formatListingsForGemini(results []pubsub.ListingInfo) str
 	return sb.String()
 }
 
-// generateFallbackConversationalResponse provides a simple fallback when Gemini is not available
+// generateFallbackConversationalResponse provides a simple fallback when no LLM is available
 func (s *AgentService) generateFallbackConversationalResponse(query string) string {
 	query = strings.ToLower(query)
 	
@@ -1182,17 +1164,6 @@ func (s *AgentService) generateFallbackConversationalResponse(query string) stri
 
 // ====== Answer + DTO mapping ======
 
-// Natural language answer (fallback when Gemini is not available)
-func (s *AgentService) generateAnswer(query string, intent *SearchIntent, resultCount int) string {
-	if resultCount == 0 {
-		return fmt.Sprintf("I couldn't find any items matching '%s'. Try adjusting your search or check back later!", query)
-	}
-	if resultCount == 1 {
-		return fmt.Sprintf("I found 1 item for '%s'. Check it out below!", query)
-	}
-	return fmt.Sprintf("I found %d items for '%s'. Here are the best matches:", resultCount, query)
-}
-
 // toFullListingInfos maps domain listings and enriches image URL like the HTTP handler
 func (s *AgentService) toFullListingInfos(ctx context.Context, listings []domain.Listing) []pubsub.ListingInfo {
 	out := make([]pubsub.ListingInfo, 0, len(listings))
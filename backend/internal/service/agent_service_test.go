@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+)
+
+func testListings() []domain.Listing {
+	return []domain.Listing{
+		{ID: uuid.New(), SellerID: uuid.New(), Title: "MacBook Air M1", Category: "Electronics", Price: 450, Condition: domain.CondGood, Status: domain.ListingActive},
+		{ID: uuid.New(), SellerID: uuid.New(), Title: "MacBook Pro 16", Category: "Electronics", Price: 900, Condition: domain.CondLikeNew, Status: domain.ListingActive},
+	}
+}
+
+func newTestAgent(llm LLMProvider) (*AgentService, *fakeListingRepo) {
+	repo := &fakeListingRepo{results: testListings()}
+	return NewAgentService(llm, repo, zap.NewNop()), repo
+}
+
+func TestProcessQuery(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantSearch bool
+		wantTitles []string
+		wantAnswer string
+	}{
+		{
+			name:       "product search",
+			query:      "MacBook under $500",
+			wantSearch: true,
+			wantTitles: []string{"MacBook Air M1"},
+			wantAnswer: "Here's a MacBook for $450.",
+		},
+		{
+			name:       "no matches",
+			query:      "macbook under $100",
+			wantSearch: true,
+			wantTitles: nil,
+			wantAnswer: "Nothing yet, check back soon.",
+		},
+		{
+			name:       "conversational",
+			query:      "What is CampusHub?",
+			wantAnswer: "A marketplace for students.",
+		},
+		{
+			name:       "unscripted prompt",
+			query:      "how are you?",
+			wantAnswer: offlineAnswer,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm := NewOfflineProvider().
+				On("i found 1 matching listings", "Here's a MacBook for $450.").
+				On("couldn't find any matching listings", "Nothing yet, check back soon.").
+				On(`said: "what is campushub?"`, "A marketplace for students.")
+			agent, repo := newTestAgent(llm)
+
+			answer, results, err := agent.ProcessQuery(context.Background(), ConversationKey{}, tt.query)
+			if err != nil {
+				t.Fatalf("ProcessQuery: %v", err)
+			}
+			if answer != tt.wantAnswer {
+				t.Errorf("answer = %q, want %q", answer, tt.wantAnswer)
+			}
+			var titles []string
+			for _, r := range results {
+				titles = append(titles, r.Title)
+			}
+			if strings.Join(titles, ",") != strings.Join(tt.wantTitles, ",") {
+				t.Errorf("results = %v, want %v", titles, tt.wantTitles)
+			}
+
+			p, searched := repo.lastSearch()
+			if searched != tt.wantSearch {
+				t.Fatalf("searched = %v, want %v", searched, tt.wantSearch)
+			}
+			if !searched {
+				return
+			}
+			if p.Status != "active" || p.Sort != "relevance" || !strings.Contains(p.Q, "macbook") {
+				t.Errorf("search params = %+v, want active listings ranked for macbook", p)
+			}
+			if p.PriceMax == nil {
+				t.Errorf("search params have no max price")
+			}
+		})
+	}
+}
+
+func TestProcessChatUsesQueryPath(t *testing.T) {
+	agent, repo := newTestAgent(NewOfflineProvider())
+
+	_, results, err := agent.ProcessChat(context.Background(), ConversationKey{}, "looking for a macbook")
+	if err != nil {
+		t.Fatalf("ProcessChat: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("got %d results, want 2", len(results))
+	}
+	if _, searched := repo.lastSearch(); !searched {
+		t.Error("ProcessChat did not search the listings")
+	}
+}
+
+func TestProcessQueryStream(t *testing.T) {
+	agent, _ := newTestAgent(NewOfflineProvider().On("i found 1 matching listings", "Here's a MacBook for $450."))
+
+	var deltas []AnswerDelta
+	answer, _, err := agent.ProcessQueryStream(context.Background(), ConversationKey{}, "MacBook under $500", func(d AnswerDelta) {
+		deltas = append(deltas, d)
+	})
+	if err != nil {
+		t.Fatalf("ProcessQueryStream: %v", err)
+	}
+	if len(deltas) < 2 {
+		t.Fatalf("got %d deltas, want the answer in several chunks", len(deltas))
+	}
+	var sb strings.Builder
+	for _, d := range deltas {
+		if d.Replace {
+			t.Errorf("delta %q replaces, want only appends", d.Text)
+		}
+		sb.WriteString(d.Text)
+	}
+	if sb.String() != answer {
+		t.Errorf("chunks = %q, want the returned answer %q", sb.String(), answer)
+	}
+}
+
+// brokenStreamer streams a few words and then fails.
+type brokenStreamer struct {
+	*OfflineProvider
+}
+
+func (p brokenStreamer) Stream(_ context.Context, _ string, onChunk func(string)) (string, error) {
+	onChunk("Let me ")
+	onChunk("check ")
+	return "", errors.New("stream reset")
+}
+
+func TestProcessQueryStreamFallbackReplaces(t *testing.T) {
+	agent, _ := newTestAgent(brokenStreamer{NewOfflineProvider()})
+
+	var deltas []AnswerDelta
+	answer, results, err := agent.ProcessQueryStream(context.Background(), ConversationKey{}, "MacBook under $500", func(d AnswerDelta) {
+		deltas = append(deltas, d)
+	})
+	if err != nil {
+		t.Fatalf("ProcessQueryStream: %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("got %d results, want 1", len(results))
+	}
+	if len(deltas) != 3 {
+		t.Fatalf("got %d deltas, want 2 partial chunks and the fallback", len(deltas))
+	}
+	last := deltas[len(deltas)-1]
+	if !last.Replace || last.Text != answer {
+		t.Errorf("last delta = %+v, want a replace carrying the answer %q", last, answer)
+	}
+	if !strings.HasPrefix(answer, "I found 1 listing(s)") {
+		t.Errorf("answer = %q, want the canned fallback", answer)
+	}
+}
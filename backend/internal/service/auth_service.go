@@ -2,60 +2,518 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/mail"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
 )
 
-var ErrUnauthorized = errors.New("unauthorized")
+var (
+	ErrUnauthorized    = errors.New("unauthorized")
+	ErrEmailDomain     = errors.New("sign up with your campus email address")
+	ErrRoleNotAllowed  = errors.New("role can only be buyer or seller")
+	ErrBadVerification = errors.New("verification link is invalid or expired")
+	ErrAlreadyVerified = errors.New("email is already verified")
+	ErrBadResetToken   = errors.New("reset link is invalid or expired")
+	ErrWrongPassword   = errors.New("current password is wrong")
+	ErrResetDisabled   = errors.New("password reset is not set up")
+	ErrTooManyResets   = errors.New("too many password reset requests")
+)
+
+// signUpRoles are the roles users may pick for themselves; admin accounts
+// are seeded by migration.
+var signUpRoles = map[string]bool{"buyer": true, "seller": true}
+
+// DefaultVerificationTTL is how long a mailed verification link works.
+const DefaultVerificationTTL = 24 * time.Hour
+
+// DefaultResetTTL is how long a mailed password reset link works.
+const DefaultResetTTL = time.Hour
+
+// Default limits on reset links mailed per email and per IP in ResetWindow.
+const (
+	DefaultResetsPerEmail = 3
+	DefaultResetsPerIP    = 20
+	DefaultResetWindow    = time.Hour
+)
+
+// DefaultRefreshTTL is how long a session can go without being refreshed.
+const DefaultRefreshTTL = 30 * 24 * time.Hour
 
 type Clock interface{ Now() time.Time }
 type JWTSigner interface {
-	SignJWT(user domain.User, exp time.Time) (string, error)
+	SignJWT(user domain.User, sid, jti string, exp time.Time) (string, error)
 }
 
 type AuthService struct {
-	repo repository.AuthRepo
-	jwt  JWTSigner
-	clk  Clock
-	ttl  time.Duration
+	repo       repository.AuthRepo
+	sessions   repository.SessionRepo
+	jwt        JWTSigner
+	clk        Clock
+	ttl        time.Duration
+	refreshTTL time.Duration
+	verify     *EmailVerification // nil: any email, verified at sign-up
+	reset      *PasswordReset     // nil: forgot-password is refused
+	throttle   *loginThrottle     // nil: unlimited sign-in attempts
+}
+
+func NewAuthService(r repository.AuthRepo, sessions repository.SessionRepo, jwt JWTSigner, clk Clock, ttl, refreshTTL time.Duration) *AuthService {
+	if refreshTTL <= 0 {
+		refreshTTL = DefaultRefreshTTL
+	}
+	return &AuthService{repo: r, sessions: sessions, jwt: jwt, clk: clk, ttl: ttl, refreshTTL: refreshTTL}
 }
 
-func NewAuthService(r repository.AuthRepo, jwt JWTSigner, clk Clock, ttl time.Duration) *AuthService {
-	return &AuthService{repo: r, jwt: jwt, clk: clk, ttl: ttl}
+// EmailVerification restricts sign-up to AllowedDomains (subdomains
+// included; empty allows any) and mails a link to LinkURL with the token
+// appended, which the user must follow before creating listings.
+type EmailVerification struct {
+	AllowedDomains []string
+	Mailer         mail.Mailer
+	LinkURL        string
+	TTL            time.Duration
+}
+
+// EnableEmailVerification turns on the campus email check for new sign-ups.
+func (s *AuthService) EnableEmailVerification(v EmailVerification) {
+	if v.TTL <= 0 {
+		v.TTL = DefaultVerificationTTL
+	}
+	s.verify = &v
 }
 
 type SignUpCmd struct{ Name, Email, Role, Password string }
-type SignInCmd struct{ Email, Password string }
+
+// SignUpResult reports whether the verification email went out; if not, the
+// user can ask for another one after signing in.
+type SignUpResult struct {
+	User             domain.User
+	VerificationSent bool
+}
+type SignInCmd struct{ Email, Password, UserAgent, IP string }
+
+// SignInResult is a fresh token pair. RefreshToken is only ever shown here;
+// the server keeps its hash.
 type SignInResult struct {
-	User  domain.User
-	Token string
+	User         domain.User
+	Token        string
+	ExpiresAt    time.Time
+	RefreshToken string
 }
 
-func (s *AuthService) SignUp(ctx context.Context, cmd SignUpCmd) (domain.User, error) {
+func (s *AuthService) SignUp(ctx context.Context, cmd SignUpCmd) (SignUpResult, error) {
+	if !signUpRoles[cmd.Role] {
+		return SignUpResult{}, ErrRoleNotAllowed
+	}
+	if s.verify != nil && !emailInDomains(cmd.Email, s.verify.AllowedDomains) {
+		return SignUpResult{}, ErrEmailDomain
+	}
 	hash, err := bcrypt.GenerateFromPassword([]byte(cmd.Password), bcrypt.DefaultCost)
 	if err != nil {
-		return domain.User{}, err
+		return SignUpResult{}, err
+	}
+	u, err := s.repo.CreateUser(ctx, cmd.Name, cmd.Email, cmd.Role, string(hash), s.verify == nil)
+	if err != nil {
+		return SignUpResult{}, err
+	}
+	if s.verify == nil {
+		return SignUpResult{User: u}, nil
+	}
+	return SignUpResult{User: u, VerificationSent: s.sendVerification(ctx, u) == nil}, nil
+}
+
+// Verify confirms the email of the token's user.
+func (s *AuthService) Verify(ctx context.Context, token string) (domain.User, error) {
+	u, err := s.repo.ConsumeVerification(ctx, hashToken(token))
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.User{}, ErrBadVerification
+	}
+	return u, err
+}
+
+// ResendVerification mails the user a fresh verification link; earlier links
+// keep working until they expire.
+func (s *AuthService) ResendVerification(ctx context.Context, userID uuid.UUID) error {
+	u, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if u.EmailVerified {
+		return ErrAlreadyVerified
+	}
+	if s.verify == nil {
+		return ErrBadVerification
+	}
+	return s.sendVerification(ctx, u)
+}
+
+// IsEmailVerified reports whether the user confirmed their email.
+func (s *AuthService) IsEmailVerified(ctx context.Context, userID string) (bool, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return false, nil
+	}
+	u, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return false, nil
 	}
-	return s.repo.CreateUser(ctx, cmd.Name, cmd.Email, cmd.Role, string(hash))
+	return u.EmailVerified, err
 }
 
+func (s *AuthService) sendVerification(ctx context.Context, u domain.User) error {
+	token, hash, err := newOpaqueToken()
+	if err != nil {
+		return err
+	}
+	if err := s.repo.CreateVerification(ctx, u.ID, hash, s.clk.Now().Add(s.verify.TTL)); err != nil {
+		return err
+	}
+	return s.verify.Mailer.Send(ctx, mail.Message{
+		To:      u.Email,
+		Subject: "Verify your CampusHub email",
+		Body: fmt.Sprintf("Hi %s,\n\nConfirm your email to start listing items:\n%s%s\n\nThe link works for %s.\n",
+			u.Name, s.verify.LinkURL, token, s.verify.TTL),
+	})
+}
+
+// emailInDomains reports whether email's domain is one of domains or a
+// subdomain of one. No domains allows any email.
+func emailInDomains(email string, domains []string) bool {
+	if len(domains) == 0 {
+		return true
+	}
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	host := strings.ToLower(strings.TrimSpace(email[at+1:]))
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if d != "" && (host == d || strings.HasSuffix(host, "."+d)) {
+			return true
+		}
+	}
+	return false
+}
+
+// PasswordReset mails a link to LinkURL with a reset token appended; the
+// token works once, for TTL. Requests counts asks per email and per IP, and
+// one that reaches MaxPerEmail or MaxPerIP is refused for Window; a nil
+// Requests doesn't limit them.
+type PasswordReset struct {
+	Mailer      mail.Mailer
+	LinkURL     string
+	TTL         time.Duration
+	Requests    repository.LoginAttemptRepo
+	MaxPerEmail int
+	MaxPerIP    int
+	Window      time.Duration
+	Logger      *zap.Logger
+}
+
+// EnablePasswordReset turns on the forgot-password flow; zero limits take
+// the defaults.
+func (s *AuthService) EnablePasswordReset(r PasswordReset) {
+	if r.TTL <= 0 {
+		r.TTL = DefaultResetTTL
+	}
+	if r.MaxPerEmail <= 0 {
+		r.MaxPerEmail = DefaultResetsPerEmail
+	}
+	if r.MaxPerIP <= 0 {
+		r.MaxPerIP = DefaultResetsPerIP
+	}
+	if r.Window <= 0 {
+		r.Window = DefaultResetWindow
+	}
+	if r.Logger == nil {
+		r.Logger = zap.NewNop()
+	}
+	s.reset = &r
+}
+
+type ForgotPasswordCmd struct{ Email, IP string }
+
+// ForgotPassword mails a reset link to the email if it belongs to a user.
+// Past the request limits it returns a *ThrottledError, whether or not the
+// email has an account. Otherwise it succeeds either way, and the link is
+// stored and mailed in the background, so neither an error nor the time
+// taken tells callers which emails have accounts.
+func (s *AuthService) ForgotPassword(ctx context.Context, cmd ForgotPasswordCmd) error {
+	if s.reset == nil {
+		return ErrResetDisabled
+	}
+	if err := s.throttleReset(ctx, cmd); err != nil {
+		return err
+	}
+	u, _, err := s.repo.GetByEmail(ctx, cmd.Email)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			s.reset.Logger.Error("forgot password lookup failed", zap.Error(err))
+		}
+		return nil
+	}
+	go s.sendReset(context.WithoutCancel(ctx), u)
+	return nil
+}
+
+// throttleReset refuses the request while its email or IP is locked, and
+// counts it against both, locking whichever reaches its limit.
+func (s *AuthService) throttleReset(ctx context.Context, cmd ForgotPasswordCmd) error {
+	r := s.reset
+	if r.Requests == nil {
+		return nil
+	}
+	keys := []throttleKey{{key: "reset:" + strings.ToLower(strings.TrimSpace(cmd.Email)), maxFailures: r.MaxPerEmail}}
+	if cmd.IP != "" {
+		keys = append(keys, throttleKey{key: "reset-ip:" + cmd.IP, maxFailures: r.MaxPerIP})
+	}
+	now := s.clk.Now()
+	for _, k := range keys {
+		a, err := r.Requests.Get(ctx, k.key)
+		if err != nil {
+			return err
+		}
+		if a.LockedUntil != nil && now.Before(*a.LockedUntil) {
+			return &ThrottledError{Locked: true, RetryAt: *a.LockedUntil, RetryAfter: a.LockedUntil.Sub(now), Reason: ErrTooManyResets}
+		}
+	}
+	for _, k := range keys {
+		n, err := r.Requests.RecordFailure(ctx, k.key, now, now.Add(-r.Window))
+		if err != nil {
+			return err
+		}
+		if n < k.maxFailures {
+			continue
+		}
+		if _, err := r.Requests.Lock(ctx, k.key, now, now.Add(r.Window)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendReset stores a reset token for u and mails the link. It runs after
+// ForgotPassword has answered, so failures are only logged.
+func (s *AuthService) sendReset(ctx context.Context, u domain.User) {
+	ctx, cancel := context.WithTimeout(ctx, resetSendTimeout)
+	defer cancel()
+	if err := s.mailReset(ctx, u); err != nil {
+		s.reset.Logger.Error("password reset mail failed", zap.String("userId", u.ID.String()), zap.Error(err))
+	}
+}
+
+// resetSendTimeout bounds storing and mailing one reset link.
+const resetSendTimeout = 30 * time.Second
+
+func (s *AuthService) mailReset(ctx context.Context, u domain.User) error {
+	token, hash, err := newOpaqueToken()
+	if err != nil {
+		return err
+	}
+	if err := s.repo.CreatePasswordReset(ctx, u.ID, hash, s.clk.Now().Add(s.reset.TTL)); err != nil {
+		return err
+	}
+	return s.reset.Mailer.Send(ctx, mail.Message{
+		To:      u.Email,
+		Subject: "Reset your CampusHub password",
+		Body: fmt.Sprintf("Hi %s,\n\nSomeone asked to reset your password. If it was you, choose a new one here:\n%s%s\n\nThe link works once, for %s. If it wasn't you, ignore this email.\n",
+			u.Name, s.reset.LinkURL, token, s.reset.TTL),
+	})
+}
+
+// ResetPassword sets a new password from a mailed reset token and signs the
+// user out everywhere, both in one transaction.
+func (s *AuthService) ResetPassword(ctx context.Context, token, password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	_, err = s.repo.ConsumePasswordReset(ctx, hashToken(token), string(hash))
+	if errors.Is(err, domain.ErrNotFound) {
+		return ErrBadResetToken
+	}
+	return err
+}
+
+type ChangePasswordCmd struct {
+	UserID    uuid.UUID
+	SessionID uuid.UUID // stays signed in; uuid.Nil signs out everywhere
+	Current   string
+	New       string
+}
+
+// ChangePassword replaces a signed-in user's password after checking the
+// current one, and signs out their other sessions.
+func (s *AuthService) ChangePassword(ctx context.Context, cmd ChangePasswordCmd) error {
+	old, err := s.repo.PasswordHash(ctx, cmd.UserID)
+	if err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(old), []byte(cmd.Current)) != nil {
+		return ErrWrongPassword
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(cmd.New), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdatePassword(ctx, cmd.UserID, string(hash)); err != nil {
+		return err
+	}
+	return s.sessions.RevokeAll(ctx, cmd.UserID, cmd.SessionID)
+}
+
+// SignIn checks the password and opens a new session. With the login
+// throttle on, it first refuses accounts and IPs that failed too often with
+// a *ThrottledError.
 func (s *AuthService) SignIn(ctx context.Context, cmd SignInCmd) (SignInResult, error) {
+	if err := s.checkThrottle(ctx, cmd); err != nil {
+		return SignInResult{}, err
+	}
 	u, hash, err := s.repo.GetByEmail(ctx, cmd.Email)
-	if err != nil {
+	if err != nil || bcrypt.CompareHashAndPassword([]byte(hash), []byte(cmd.Password)) != nil {
+		if err := s.loginFailed(ctx, cmd); err != nil {
+			return SignInResult{}, err
+		}
 		return SignInResult{}, ErrUnauthorized
 	}
-	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(cmd.Password)) != nil {
+	if err := s.loginSucceeded(ctx, cmd); err != nil {
+		return SignInResult{}, err
+	}
+	refresh, refreshHash, err := newOpaqueToken()
+	if err != nil {
+		return SignInResult{}, err
+	}
+	sid, jti := uuid.New(), uuid.NewString()
+	now := s.clk.Now()
+	exp := now.Add(s.ttl)
+	tok, err := s.jwt.SignJWT(u, sid.String(), jti, exp)
+	if err != nil {
+		return SignInResult{}, err
+	}
+	if _, err := s.sessions.Create(ctx, repository.CreateSession{
+		ID:          sid,
+		UserID:      u.ID,
+		RefreshHash: refreshHash,
+		UserAgent:   cmd.UserAgent,
+		IP:          cmd.IP,
+		ExpiresAt:   now.Add(s.refreshTTL),
+	}); err != nil {
+		return SignInResult{}, err
+	}
+	return SignInResult{User: u, Token: tok, ExpiresAt: exp, RefreshToken: refresh}, nil
+}
+
+type RefreshCmd struct{ RefreshToken, UserAgent, IP string }
+
+// Refresh trades a refresh token for a new token pair; the old refresh token
+// stops working and the session lives another refreshTTL. Presenting one
+// that was already rotated away means it leaked, so the whole session is
+// revoked.
+func (s *AuthService) Refresh(ctx context.Context, cmd RefreshCmd) (SignInResult, error) {
+	oldHash := hashToken(cmd.RefreshToken)
+	refresh, refreshHash, err := newOpaqueToken()
+	if err != nil {
+		return SignInResult{}, err
+	}
+	sess, err := s.sessions.Rotate(ctx, repository.RotateSession{
+		OldHash: oldHash, NewHash: refreshHash, UserAgent: cmd.UserAgent, IP: cmd.IP,
+		ExpiresAt: s.clk.Now().Add(s.refreshTTL),
+	})
+	if errors.Is(err, domain.ErrNotFound) {
+		if reused, err := s.sessions.ByPreviousHash(ctx, oldHash); err == nil {
+			_ = s.sessions.Revoke(ctx, reused.UserID, reused.ID)
+		}
 		return SignInResult{}, ErrUnauthorized
 	}
+	if err != nil {
+		return SignInResult{}, err
+	}
+	u, err := s.repo.GetByID(ctx, sess.UserID)
+	if err != nil {
+		return SignInResult{}, err
+	}
 	exp := s.clk.Now().Add(s.ttl)
-	tok, err := s.jwt.SignJWT(u, exp)
+	tok, err := s.jwt.SignJWT(u, sess.ID.String(), uuid.NewString(), exp)
 	if err != nil {
 		return SignInResult{}, err
 	}
-	return SignInResult{User: u, Token: tok}, nil
+	return SignInResult{User: u, Token: tok, ExpiresAt: exp, RefreshToken: refresh}, nil
+}
+
+// TokenRef identifies the access token a request was made with.
+type TokenRef struct {
+	UserID    uuid.UUID
+	SessionID uuid.UUID // uuid.Nil for tokens issued without a session
+	JTI       string
+	ExpiresAt time.Time
+}
+
+// Logout revokes the caller's token and ends its session.
+func (s *AuthService) Logout(ctx context.Context, t TokenRef) error {
+	if err := s.sessions.RevokeToken(ctx, t.JTI, t.ExpiresAt); err != nil {
+		return err
+	}
+	if t.SessionID == uuid.Nil {
+		return nil
+	}
+	err := s.sessions.Revoke(ctx, t.UserID, t.SessionID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil // already over
+	}
+	return err
+}
+
+// Sessions lists the user's signed-in devices, marking the one current is
+// from.
+func (s *AuthService) Sessions(ctx context.Context, userID, current uuid.UUID) ([]domain.Session, error) {
+	items, err := s.sessions.Active(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].Current = items[i].ID == current
+	}
+	return items, nil
+}
+
+// RevokeSession signs one of the user's devices out: its refresh token stops
+// working and its access tokens are refused.
+func (s *AuthService) RevokeSession(ctx context.Context, userID, id uuid.UUID) error {
+	return s.sessions.Revoke(ctx, userID, id)
+}
+
+// IsRevoked reports whether the access token jti, issued for session sid,
+// was revoked before expiry.
+func (s *AuthService) IsRevoked(ctx context.Context, jti, sid string) (bool, error) {
+	sessionID, _ := uuid.Parse(sid) // tokens without a session only have their jti
+	return s.sessions.IsRevoked(ctx, jti, sessionID)
+}
+
+// newOpaqueToken returns a random token (refresh, verification or password
+// reset) and the hash to store.
+func newOpaqueToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
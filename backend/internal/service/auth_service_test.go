@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+)
+
+// memSessionRepo keeps sessions by refresh hash and treats one as live until
+// its expiry on clk, like SessionRepoPG does with now().
+type memSessionRepo struct {
+	repository.SessionRepo
+
+	clk      *fakeClock
+	mu       sync.Mutex
+	sessions map[string]domain.Session
+}
+
+func (r *memSessionRepo) Create(_ context.Context, in repository.CreateSession) (domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := domain.Session{ID: in.ID, UserID: in.UserID, ExpiresAt: in.ExpiresAt}
+	r.sessions[in.RefreshHash] = s
+	return s, nil
+}
+
+func (r *memSessionRepo) Rotate(_ context.Context, in repository.RotateSession) (domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sessions[in.OldHash]
+	if !ok || !r.clk.Now().Before(s.ExpiresAt) {
+		return domain.Session{}, domain.ErrNotFound
+	}
+	delete(r.sessions, in.OldHash)
+	s.ExpiresAt = in.ExpiresAt
+	r.sessions[in.NewHash] = s
+	return s, nil
+}
+
+func (r *memSessionRepo) ByPreviousHash(context.Context, string) (domain.Session, error) {
+	return domain.Session{}, domain.ErrNotFound
+}
+
+func TestRefreshSlidesSessionExpiry(t *testing.T) {
+	clk := newFakeClock()
+	refreshTTL := 30 * 24 * time.Hour
+	auth := NewAuthService(newFakeAuthRepo(t, "alice@sjsu.edu"), &memSessionRepo{clk: clk, sessions: map[string]domain.Session{}},
+		fakeSigner{}, clk, time.Hour, refreshTTL)
+	ctx := context.Background()
+
+	out, err := auth.SignIn(ctx, SignInCmd{Email: "alice@sjsu.edu", Password: testPassword})
+	if err != nil {
+		t.Fatalf("SignIn: %v", err)
+	}
+	// Refreshing every 20 days keeps the session going well past 30 days
+	// from sign-in.
+	for i := range 3 {
+		clk.Advance(20 * 24 * time.Hour)
+		if out, err = auth.Refresh(ctx, RefreshCmd{RefreshToken: out.RefreshToken}); err != nil {
+			t.Fatalf("refresh %d: %v", i+1, err)
+		}
+	}
+	clk.Advance(refreshTTL)
+	if _, err := auth.Refresh(ctx, RefreshCmd{RefreshToken: out.RefreshToken}); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("refresh after %s idle: err = %v, want ErrUnauthorized", refreshTTL, err)
+	}
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+)
+
+var (
+	ErrEmptyMessage    = errors.New("message body is empty")
+	ErrChatWithSelf    = errors.New("cannot start a conversation on your own listing")
+	ErrMissingChatPeer = errors.New("conversationId, or listingId (and toUserId for sellers), is required")
+)
+
+// maxPendingMessages caps how many unread messages are replayed on connect;
+// older ones are still available through the history endpoint.
+const maxPendingMessages = 200
+
+// ChatService persists user-to-user conversations. There is one conversation
+// per listing+buyer; the seller is the other participant.
+type ChatService struct {
+	chats    repository.ChatRepo
+	listings repository.ListingRepo
+	clk      Clock
+}
+
+func NewChatService(c repository.ChatRepo, l repository.ListingRepo, clk Clock) *ChatService {
+	return &ChatService{chats: c, listings: l, clk: clk}
+}
+
+type SendMessageCmd struct {
+	SenderID       uuid.UUID
+	ConversationID *uuid.UUID // existing conversation, or
+	ListingID      *uuid.UUID // listing to open a conversation about
+	ToUserID       *uuid.UUID // buyer, when the seller opens the conversation
+	Body           string
+}
+
+type SendMessageResult struct {
+	Message    domain.Message
+	Recipients []uuid.UUID // participants other than the sender
+}
+
+// StartConversation opens (or returns) the buyer's conversation about a listing.
+func (s *ChatService) StartConversation(ctx context.Context, buyerID, listingID uuid.UUID) (domain.Conversation, error) {
+	l, err := s.listings.Get(ctx, listingID)
+	if err != nil {
+		return domain.Conversation{}, err
+	}
+	if l.SellerID == buyerID {
+		return domain.Conversation{}, ErrChatWithSelf
+	}
+	return s.chats.GetOrCreateConversation(ctx, l.ID, buyerID, l.SellerID)
+}
+
+// SendMessage stores the message before anyone is told about it, so history
+// is complete even when delivery fails.
+func (s *ChatService) SendMessage(ctx context.Context, cmd SendMessageCmd) (SendMessageResult, error) {
+	body := strings.TrimSpace(cmd.Body)
+	if body == "" {
+		return SendMessageResult{}, ErrEmptyMessage
+	}
+
+	conv, err := s.resolveConversation(ctx, cmd)
+	if err != nil {
+		return SendMessageResult{}, err
+	}
+
+	msg, err := s.chats.AddMessage(ctx, conv.ID, cmd.SenderID, body)
+	if err != nil {
+		return SendMessageResult{}, err
+	}
+
+	var recipients []uuid.UUID
+	for _, p := range conv.Participants {
+		if p != cmd.SenderID {
+			recipients = append(recipients, p)
+		}
+	}
+	return SendMessageResult{Message: msg, Recipients: recipients}, nil
+}
+
+func (s *ChatService) resolveConversation(ctx context.Context, cmd SendMessageCmd) (domain.Conversation, error) {
+	if cmd.ConversationID != nil {
+		return s.conversationFor(ctx, cmd.SenderID, *cmd.ConversationID)
+	}
+	if cmd.ListingID == nil {
+		return domain.Conversation{}, ErrMissingChatPeer
+	}
+
+	l, err := s.listings.Get(ctx, *cmd.ListingID)
+	if err != nil {
+		return domain.Conversation{}, err
+	}
+	if l.SellerID != cmd.SenderID {
+		return s.chats.GetOrCreateConversation(ctx, l.ID, cmd.SenderID, l.SellerID)
+	}
+	// The seller is writing first, so the buyer must be named.
+	if cmd.ToUserID == nil {
+		return domain.Conversation{}, ErrMissingChatPeer
+	}
+	if *cmd.ToUserID == l.SellerID {
+		return domain.Conversation{}, ErrChatWithSelf
+	}
+	return s.chats.GetOrCreateConversation(ctx, l.ID, *cmd.ToUserID, l.SellerID)
+}
+
+func (s *ChatService) ListConversations(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.Conversation, int, error) {
+	return s.chats.ListConversations(ctx, userID, limit, offset)
+}
+
+func (s *ChatService) ListMessages(ctx context.Context, userID, conversationID uuid.UUID, before time.Time, limit int) ([]domain.Message, error) {
+	if _, err := s.conversationFor(ctx, userID, conversationID); err != nil {
+		return nil, err
+	}
+	return s.chats.ListMessages(ctx, conversationID, before, limit)
+}
+
+// PendingMessages returns what userID has not read yet, oldest first, so it
+// can be replayed when they reconnect.
+func (s *ChatService) PendingMessages(ctx context.Context, userID uuid.UUID) ([]domain.Message, error) {
+	return s.chats.ListUnreadMessages(ctx, userID, maxPendingMessages)
+}
+
+func (s *ChatService) UnreadCounts(ctx context.Context, userID uuid.UUID) ([]domain.UnreadCount, error) {
+	return s.chats.UnreadCounts(ctx, userID)
+}
+
+// MarkRead marks everything in the conversation up to at as read. A zero at
+// means "now", and so does one in the future: the read mark never moves back,
+// so a future one would hide every later message.
+func (s *ChatService) MarkRead(ctx context.Context, userID, conversationID uuid.UUID, at time.Time) error {
+	if _, err := s.conversationFor(ctx, userID, conversationID); err != nil {
+		return err
+	}
+	if now := s.clk.Now(); at.IsZero() || at.After(now) {
+		at = now
+	}
+	return s.chats.MarkRead(ctx, conversationID, userID, at)
+}
+
+// conversationFor loads a conversation and checks userID takes part in it.
+func (s *ChatService) conversationFor(ctx context.Context, userID, conversationID uuid.UUID) (domain.Conversation, error) {
+	conv, err := s.chats.GetConversation(ctx, conversationID)
+	if err != nil {
+		return domain.Conversation{}, err
+	}
+	if !slices.Contains(conv.Participants, userID) {
+		return domain.Conversation{}, domain.ErrForbidden
+	}
+	return conv, nil
+}
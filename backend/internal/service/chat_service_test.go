@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+)
+
+// fakeChatRepo holds one conversation and records read marks the way the pg
+// repo does: they only move forward.
+type fakeChatRepo struct {
+	repository.ChatRepo
+
+	conv     domain.Conversation
+	lastRead map[uuid.UUID]time.Time
+}
+
+func (r *fakeChatRepo) GetConversation(_ context.Context, id uuid.UUID) (domain.Conversation, error) {
+	if id != r.conv.ID {
+		return domain.Conversation{}, domain.ErrNotFound
+	}
+	return r.conv, nil
+}
+
+func (r *fakeChatRepo) MarkRead(_ context.Context, _, userID uuid.UUID, at time.Time) error {
+	if at.After(r.lastRead[userID]) {
+		r.lastRead[userID] = at
+	}
+	return nil
+}
+
+func TestMarkRead(t *testing.T) {
+	clk := newFakeClock()
+	now := clk.Now()
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		wantRead time.Time
+	}{
+		{name: "zero means now", wantRead: now},
+		{name: "past", at: now.Add(-time.Hour), wantRead: now.Add(-time.Hour)},
+		{name: "future is clamped to now", at: time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC), wantRead: now},
+		{name: "just ahead is clamped to now", at: now.Add(time.Second), wantRead: now},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buyer, seller := uuid.New(), uuid.New()
+			repo := &fakeChatRepo{
+				conv:     domain.Conversation{ID: uuid.New(), Participants: []uuid.UUID{buyer, seller}},
+				lastRead: map[uuid.UUID]time.Time{},
+			}
+			s := NewChatService(repo, nil, clk)
+
+			if err := s.MarkRead(context.Background(), buyer, repo.conv.ID, tt.at); err != nil {
+				t.Fatalf("MarkRead: %v", err)
+			}
+			if got := repo.lastRead[buyer]; !got.Equal(tt.wantRead) {
+				t.Errorf("last read = %v, want %v", got, tt.wantRead)
+			}
+		})
+	}
+}
+
+func TestMarkReadNeedsParticipant(t *testing.T) {
+	repo := &fakeChatRepo{
+		conv:     domain.Conversation{ID: uuid.New(), Participants: []uuid.UUID{uuid.New(), uuid.New()}},
+		lastRead: map[uuid.UUID]time.Time{},
+	}
+	s := NewChatService(repo, nil, newFakeClock())
+
+	err := s.MarkRead(context.Background(), uuid.New(), repo.conv.ID, time.Time{})
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("err = %v, want ErrForbidden", err)
+	}
+	if len(repo.lastRead) != 0 {
+		t.Errorf("read marks = %v, want none", repo.lastRead)
+	}
+}
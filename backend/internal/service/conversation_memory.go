@@ -0,0 +1,126 @@
+package service
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/clock"
+)
+
+const (
+	defaultConversationTTL = 30 * time.Minute
+	maxConversationTurns   = 6 // user + assistant messages kept for prompts
+)
+
+// ConversationKey identifies one assistant conversation: a user on one
+// WebSocket session. The zero key means "no memory".
+type ConversationKey struct {
+	UserID    string
+	SessionID string
+}
+
+func (k ConversationKey) IsZero() bool { return k.UserID == "" }
+
+type ChatTurn struct {
+	Role string // "user" or "assistant"
+	Text string
+}
+
+// ConversationState is what the assistant remembers between turns so that
+// follow-ups ("any cheaper ones?") can build on the previous search.
+type ConversationState struct {
+	LastIntent *SearchIntent
+	LastPrices []float64 // prices of the listings returned for LastIntent
+	Turns      []ChatTurn
+	updatedAt  time.Time
+}
+
+// ConversationMemory keeps ConversationState in process, evicting sessions
+// idle for longer than the TTL. Each worker has its own memory, so with
+// several workers a follow-up may land on one that has not seen the session.
+type ConversationMemory struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	clock     clock.Clock
+	sessions  map[ConversationKey]*ConversationState
+	lastSweep time.Time
+}
+
+func NewConversationMemory(ttl time.Duration, clk clock.Clock) *ConversationMemory {
+	if ttl <= 0 {
+		ttl = defaultConversationTTL
+	}
+	return &ConversationMemory{
+		ttl:      ttl,
+		clock:    clk,
+		sessions: make(map[ConversationKey]*ConversationState),
+	}
+}
+
+// Get returns a copy of the session's state, if it has one that hasn't expired.
+func (m *ConversationMemory) Get(k ConversationKey) (ConversationState, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	st, ok := m.sessions[k]
+	if !ok {
+		return ConversationState{}, false
+	}
+	if m.clock.Now().Sub(st.updatedAt) > m.ttl {
+		delete(m.sessions, k)
+		return ConversationState{}, false
+	}
+	out := *st
+	out.LastPrices = slices.Clone(st.LastPrices)
+	out.Turns = slices.Clone(st.Turns)
+	if st.LastIntent != nil {
+		out.LastIntent = st.LastIntent.clone()
+	}
+	return out, true
+}
+
+// Record appends one exchange. A nil intent (small talk) keeps the previous
+// search so a later follow-up can still refine it.
+func (m *ConversationMemory) Record(k ConversationKey, userText, answer string, intent *SearchIntent, prices []float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.clock.Now()
+	m.sweepLocked(now)
+
+	st, ok := m.sessions[k]
+	if !ok || now.Sub(st.updatedAt) > m.ttl {
+		st = &ConversationState{}
+		m.sessions[k] = st
+	}
+	if intent != nil {
+		st.LastIntent = intent.clone()
+		st.LastPrices = slices.Clone(prices)
+	}
+	st.Turns = append(st.Turns, ChatTurn{Role: "user", Text: userText}, ChatTurn{Role: "assistant", Text: answer})
+	if len(st.Turns) > maxConversationTurns {
+		st.Turns = slices.Clone(st.Turns[len(st.Turns)-maxConversationTurns:])
+	}
+	st.updatedAt = now
+}
+
+// Reset forgets the session.
+func (m *ConversationMemory) Reset(k ConversationKey) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, k)
+}
+
+// sweepLocked drops expired sessions, at most once per minute.
+func (m *ConversationMemory) sweepLocked(now time.Time) {
+	if now.Sub(m.lastSweep) < time.Minute {
+		return
+	}
+	m.lastSweep = now
+	for k, st := range m.sessions {
+		if now.Sub(st.updatedAt) > m.ttl {
+			delete(m.sessions, k)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+)
+
+// fakeListingRepo keeps listings in memory, recording the params of every
+// search and counting writes. Methods a test doesn't set up panic through
+// the nil embedded interface.
+type fakeListingRepo struct {
+	repository.ListingRepo
+
+	mu        sync.Mutex
+	results   []domain.Listing
+	searches  []repository.ListParams
+	writes    int
+	changeErr error // returned by ChangeStatus without writing
+}
+
+func (r *fakeListingRepo) List(_ context.Context, p repository.ListParams) ([]domain.Listing, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.searches = append(r.searches, p)
+
+	var out []domain.Listing
+	for _, l := range r.results {
+		if p.PriceMax != nil && l.Price > *p.PriceMax {
+			continue
+		}
+		out = append(out, l)
+	}
+	return out, len(out), nil
+}
+
+func (r *fakeListingRepo) Get(_ context.Context, id uuid.UUID) (domain.Listing, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, l := range r.results {
+		if l.ID == id {
+			return l, nil
+		}
+	}
+	return domain.Listing{}, domain.ErrNotFound
+}
+
+func (r *fakeListingRepo) UpdatePartial(_ context.Context, id uuid.UUID, patch repository.UpdateListing) (domain.Listing, error) {
+	return r.update(id, func(l *domain.Listing) { applyPatch(l, patch) })
+}
+
+func (r *fakeListingRepo) ChangeStatus(_ context.Context, c repository.StatusChange) (domain.Listing, error) {
+	if r.changeErr != nil {
+		return domain.Listing{}, r.changeErr
+	}
+	return r.update(c.ListingID, func(l *domain.Listing) {
+		l.Status = c.To
+		if c.ReservedBy != nil {
+			l.ReservedBy = c.ReservedBy
+		}
+		applyPatch(l, c.Patch)
+	})
+}
+
+func applyPatch(l *domain.Listing, patch repository.UpdateListing) {
+	if patch.Title != nil {
+		l.Title = *patch.Title
+	}
+	if patch.Price != nil {
+		l.Price = *patch.Price
+	}
+}
+
+func (r *fakeListingRepo) update(id uuid.UUID, apply func(*domain.Listing)) (domain.Listing, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.results {
+		if r.results[i].ID == id {
+			apply(&r.results[i])
+			r.writes++
+			return r.results[i], nil
+		}
+	}
+	return domain.Listing{}, domain.ErrNotFound
+}
+
+func (r *fakeListingRepo) lastSearch() (repository.ListParams, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.searches) == 0 {
+		return repository.ListParams{}, false
+	}
+	return r.searches[len(r.searches)-1], true
+}
+
+// fakeClock is a Clock that only moves when told to.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 10, 20, 18, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/pubsub"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+)
+
+// ListingEvents hears about listing changes that other users may be
+// watching. ListingService calls it after the change is saved.
+type ListingEvents interface {
+	PriceDropped(ctx context.Context, l domain.Listing, oldPrice float64)
+	Sold(ctx context.Context, l domain.Listing)
+}
+
+var _ ListingEvents = (*FavoriteService)(nil)
+
+// FavoriteService keeps users' watchlists and tells watchers when a listing
+// they saved gets cheaper or sells.
+type FavoriteService struct {
+	favorites repository.FavoriteRepo
+	listings  repository.ListingRepo
+	bus       pubsub.Broker // nil: watchers are not notified
+	logger    *zap.Logger
+}
+
+func NewFavoriteService(f repository.FavoriteRepo, l repository.ListingRepo, bus pubsub.Broker, logger *zap.Logger) *FavoriteService {
+	return &FavoriteService{favorites: f, listings: l, bus: bus, logger: logger}
+}
+
+// Add saves the listing to the user's favorites. Removed listings cannot be
+// favorited.
+func (s *FavoriteService) Add(ctx context.Context, userID, listingID uuid.UUID) error {
+	l, err := s.listings.Get(ctx, listingID)
+	if err != nil {
+		return err
+	}
+	if l.Status == domain.ListingRemoved {
+		return domain.ErrNotFound
+	}
+	return s.favorites.Add(ctx, userID, listingID)
+}
+
+func (s *FavoriteService) Remove(ctx context.Context, userID, listingID uuid.UUID) error {
+	return s.favorites.Remove(ctx, userID, listingID)
+}
+
+func (s *FavoriteService) List(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.Listing, int, error) {
+	return s.favorites.List(ctx, userID, limit, offset)
+}
+
+// Counts returns the favorite count per listing.
+func (s *FavoriteService) Counts(ctx context.Context, listingIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	return s.favorites.Counts(ctx, listingIDs)
+}
+
+// FavoritedBy reports which of listingIDs the user favorited.
+func (s *FavoriteService) FavoritedBy(ctx context.Context, userID uuid.UUID, listingIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	return s.favorites.FavoritedBy(ctx, userID, listingIDs)
+}
+
+func (s *FavoriteService) PriceDropped(ctx context.Context, l domain.Listing, oldPrice float64) {
+	s.notify(ctx, l, pubsub.ListingWatchEvent{Kind: "price_drop", OldPrice: oldPrice})
+}
+
+func (s *FavoriteService) Sold(ctx context.Context, l domain.Listing) {
+	s.notify(ctx, l, pubsub.ListingWatchEvent{Kind: "sold"})
+}
+
+// notify publishes ev to everyone watching l except its seller. Failures are
+// logged: the listing change itself already went through.
+func (s *FavoriteService) notify(ctx context.Context, l domain.Listing, ev pubsub.ListingWatchEvent) {
+	if s.bus == nil {
+		return
+	}
+	watchers, err := s.favorites.Watchers(ctx, l.ID)
+	if err != nil {
+		s.logger.Warn("load listing watchers failed", zap.String("listingId", l.ID.String()), zap.Error(err))
+		return
+	}
+	for _, id := range watchers {
+		if id != l.SellerID {
+			ev.UserIDs = append(ev.UserIDs, id.String())
+		}
+	}
+	if len(ev.UserIDs) == 0 {
+		return
+	}
+	ev.ListingID = l.ID.String()
+	ev.Title = l.Title
+	ev.Price = l.Price
+	s.bus.Publish("listing.watch", ev)
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+)
+
+// Actor is the authenticated caller, as set by middleware.JWT.
+type Actor struct {
+	UserID uuid.UUID
+	Role   string
+}
+
+func (a Actor) IsAdmin() bool { return a.Role == "admin" }
+
+var ErrBuyerMismatch = errors.New("buyer must be someone other than the seller, and the buyer holding the reservation if there is one")
+
+// ListingService guards listing mutations so only the seller (or an admin)
+// can change a listing.
+type ListingService struct {
+	repo   repository.ListingRepo
+	events ListingEvents // nil: nobody is told about price drops and sales
+}
+
+func NewListingService(r repository.ListingRepo, events ListingEvents) *ListingService {
+	return &ListingService{repo: r, events: events}
+}
+
+// Update edits the listing's fields and, when status is set to a new one,
+// changes its status like ChangeStatus. Both are written together, so a
+// failed status change leaves the fields as they were.
+func (s *ListingService) Update(ctx context.Context, actor Actor, id uuid.UUID, patch repository.UpdateListing, status *domain.ListingStatus) (domain.Listing, error) {
+	l, err := s.authorize(ctx, actor, id)
+	if err != nil {
+		return domain.Listing{}, err
+	}
+	var updated domain.Listing
+	if status != nil && *status != l.Status {
+		updated, err = s.changeStatus(ctx, actor, l, repository.StatusChange{To: *status, Patch: patch})
+	} else {
+		updated, err = s.repo.UpdatePartial(ctx, id, patch)
+	}
+	if err != nil {
+		return domain.Listing{}, err
+	}
+	// Only worth telling watchers while they can still buy it.
+	onSale := updated.Status == domain.ListingActive || updated.Status == domain.ListingReserved
+	if s.events != nil && onSale && updated.Price < l.Price {
+		s.events.PriceDropped(ctx, updated, l.Price)
+	}
+	return updated, nil
+}
+
+// MarkSold sells the listing. buyerID, if set, records who bought it so
+// they can review the seller; a reserved listing already has its buyer and
+// cannot be sold to someone else.
+func (s *ListingService) MarkSold(ctx context.Context, actor Actor, id uuid.UUID, buyerID *uuid.UUID) error {
+	l, err := s.authorize(ctx, actor, id)
+	if err != nil {
+		return err
+	}
+	if buyerID != nil {
+		if *buyerID == l.SellerID || (l.ReservedBy != nil && *l.ReservedBy != *buyerID) {
+			return ErrBuyerMismatch
+		}
+	}
+	_, err = s.changeStatus(ctx, actor, l, repository.StatusChange{To: domain.ListingSold, ReservedBy: buyerID})
+	return err
+}
+
+func (s *ListingService) Delete(ctx context.Context, actor Actor, id uuid.UUID) error {
+	_, err := s.ChangeStatus(ctx, actor, id, domain.ListingRemoved, "")
+	return err
+}
+
+// ChangeStatus moves the listing to status if listingTransitions allows the
+// actor to, and records the change in the listing's history.
+func (s *ListingService) ChangeStatus(ctx context.Context, actor Actor, id uuid.UUID, status domain.ListingStatus, reason string) (domain.Listing, error) {
+	l, err := s.authorize(ctx, actor, id)
+	if err != nil {
+		return domain.Listing{}, err
+	}
+	return s.changeStatus(ctx, actor, l, repository.StatusChange{To: status, Reason: reason})
+}
+
+// changeStatus fills in the listing and actor parts of c and applies it.
+func (s *ListingService) changeStatus(ctx context.Context, actor Actor, l domain.Listing, c repository.StatusChange) (domain.Listing, error) {
+	if l.Status == c.To {
+		return l, nil // nothing to change or record
+	}
+	if err := checkTransition(changerFor(actor), l.Status, c.To); err != nil {
+		return domain.Listing{}, err
+	}
+	c.ListingID, c.From, c.ChangedBy = l.ID, l.Status, &actor.UserID
+	out, err := s.repo.ChangeStatus(ctx, c)
+	if errors.Is(err, domain.ErrConflict) {
+		// Someone else changed the status since we checked.
+		return domain.Listing{}, fmt.Errorf("%w: status changed concurrently", ErrStatusTransition)
+	}
+	if err != nil {
+		return domain.Listing{}, err
+	}
+	if s.events != nil && out.Status == domain.ListingSold {
+		s.events.Sold(ctx, out)
+	}
+	return out, nil
+}
+
+// History returns the listing's status changes, newest first, to its seller
+// or an admin.
+func (s *ListingService) History(ctx context.Context, actor Actor, id uuid.UUID, limit, offset int) ([]domain.ListingStatusChange, int, error) {
+	if _, err := s.authorize(ctx, actor, id); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.StatusHistory(ctx, id, limit, offset)
+}
+
+// authorize loads the listing and checks the actor owns it or is an admin.
+func (s *ListingService) authorize(ctx context.Context, actor Actor, id uuid.UUID) (domain.Listing, error) {
+	l, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return domain.Listing{}, err
+	}
+	if !actor.IsAdmin() && l.SellerID != actor.UserID {
+		return domain.Listing{}, domain.ErrForbidden
+	}
+	return l, nil
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+)
+
+func TestListingOwnership(t *testing.T) {
+	seller := uuid.New()
+	price := 300.0
+	ops := map[string]func(s *ListingService, actor Actor, id uuid.UUID) error{
+		"update": func(s *ListingService, actor Actor, id uuid.UUID) error {
+			_, err := s.Update(context.Background(), actor, id, repository.UpdateListing{Price: &price}, nil)
+			return err
+		},
+		"delete": func(s *ListingService, actor Actor, id uuid.UUID) error {
+			return s.Delete(context.Background(), actor, id)
+		},
+		"change status": func(s *ListingService, actor Actor, id uuid.UUID) error {
+			_, err := s.ChangeStatus(context.Background(), actor, id, domain.ListingSold, "sold in person")
+			return err
+		},
+		"mark sold": func(s *ListingService, actor Actor, id uuid.UUID) error {
+			return s.MarkSold(context.Background(), actor, id, nil)
+		},
+	}
+	tests := []struct {
+		name    string
+		actor   Actor
+		missing bool
+		want    error
+	}{
+		{name: "owner", actor: Actor{UserID: seller, Role: "user"}},
+		{name: "non-owner", actor: Actor{UserID: uuid.New(), Role: "user"}, want: domain.ErrForbidden},
+		{name: "admin", actor: Actor{UserID: uuid.New(), Role: "admin"}},
+		{name: "not found", actor: Actor{UserID: seller, Role: "user"}, missing: true, want: domain.ErrNotFound},
+		{name: "not found as admin", actor: Actor{UserID: uuid.New(), Role: "admin"}, missing: true, want: domain.ErrNotFound},
+	}
+	for opName, op := range ops {
+		for _, tt := range tests {
+			t.Run(opName+"/"+tt.name, func(t *testing.T) {
+				l := domain.Listing{ID: uuid.New(), SellerID: seller, Title: "Desk", Price: 400, Status: domain.ListingActive}
+				repo := &fakeListingRepo{results: []domain.Listing{l}}
+				id := l.ID
+				if tt.missing {
+					id = uuid.New()
+				}
+
+				err := op(NewListingService(repo, nil), tt.actor, id)
+				if !errors.Is(err, tt.want) {
+					t.Fatalf("err = %v, want %v", err, tt.want)
+				}
+				if tt.want != nil && repo.writes != 0 {
+					t.Errorf("refused %s still wrote %d time(s)", opName, repo.writes)
+				}
+				if tt.want == nil && repo.writes == 0 {
+					t.Errorf("%s wrote nothing", opName)
+				}
+			})
+		}
+	}
+}
+
+func TestListingUpdateWithStatus(t *testing.T) {
+	seller := uuid.New()
+	l := domain.Listing{ID: uuid.New(), SellerID: seller, Price: 400, Status: domain.ListingActive}
+	repo := &fakeListingRepo{results: []domain.Listing{l}}
+	svc := NewListingService(repo, nil)
+
+	sold := domain.ListingSold
+	if _, err := svc.Update(context.Background(), Actor{UserID: uuid.New()}, l.ID, repository.UpdateListing{}, &sold); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("non-owner status change: %v, want ErrForbidden", err)
+	}
+	// Only offers reserve a listing.
+	reserved := domain.ListingReserved
+	if _, err := svc.Update(context.Background(), Actor{UserID: seller}, l.ID, repository.UpdateListing{}, &reserved); !errors.Is(err, ErrStatusTransition) {
+		t.Fatalf("seller reserving: %v, want ErrStatusTransition", err)
+	}
+	out, err := svc.Update(context.Background(), Actor{UserID: seller}, l.ID, repository.UpdateListing{}, &sold)
+	if err != nil {
+		t.Fatalf("owner status change: %v", err)
+	}
+	if out.Status != domain.ListingSold {
+		t.Errorf("status = %s, want sold", out.Status)
+	}
+}
+
+func TestListingUpdateWithStatusAndFields(t *testing.T) {
+	seller := Actor{UserID: uuid.New()}
+	sold := domain.ListingSold
+	price := 300.0
+	patch := repository.UpdateListing{Price: &price}
+
+	t.Run("written together", func(t *testing.T) {
+		l := domain.Listing{ID: uuid.New(), SellerID: seller.UserID, Price: 400, Status: domain.ListingActive}
+		repo := &fakeListingRepo{results: []domain.Listing{l}}
+		out, err := NewListingService(repo, nil).Update(context.Background(), seller, l.ID, patch, &sold)
+		if err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		if out.Status != domain.ListingSold || out.Price != price {
+			t.Errorf("listing = %s at %.2f, want sold at %.2f", out.Status, out.Price, price)
+		}
+		if repo.writes != 1 {
+			t.Errorf("%d writes, want the status and price in one", repo.writes)
+		}
+	})
+	t.Run("failed status change keeps the fields", func(t *testing.T) {
+		l := domain.Listing{ID: uuid.New(), SellerID: seller.UserID, Price: 400, Status: domain.ListingActive}
+		repo := &fakeListingRepo{results: []domain.Listing{l}, changeErr: domain.ErrConflict}
+		_, err := NewListingService(repo, nil).Update(context.Background(), seller, l.ID, patch, &sold)
+		if !errors.Is(err, ErrStatusTransition) {
+			t.Fatalf("Update: %v, want ErrStatusTransition", err)
+		}
+		if got, _ := repo.Get(context.Background(), l.ID); got.Price != l.Price || got.Status != l.Status {
+			t.Errorf("listing = %s at %.2f, want it untouched", got.Status, got.Price)
+		}
+	})
+}
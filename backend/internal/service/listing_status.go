@@ -0,0 +1,58 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+)
+
+var ErrStatusTransition = errors.New("listing status change not allowed")
+
+// statusChanger is who asks for a listing status change.
+type statusChanger uint8
+
+const (
+	bySeller statusChanger = 1 << iota
+	byAdmin
+	bySystem // offers and the reservation sweeper
+)
+
+// listingTransitions is the one place listing status rules live: for each
+// from -> to, who may make the change. Anything missing is not allowed.
+// Only the system reserves a listing, since a reservation needs the buyer
+// from an accepted offer.
+var listingTransitions = map[domain.ListingStatus]map[domain.ListingStatus]statusChanger{
+	domain.ListingActive: {
+		domain.ListingReserved: bySystem,
+		domain.ListingSold:     bySeller | byAdmin,
+		domain.ListingRemoved:  bySeller | byAdmin,
+	},
+	domain.ListingReserved: {
+		domain.ListingActive:  bySeller | byAdmin | bySystem, // cancelled or expired
+		domain.ListingSold:    bySeller | byAdmin,
+		domain.ListingRemoved: bySeller | byAdmin,
+	},
+	domain.ListingSold: {
+		domain.ListingActive:  byAdmin,
+		domain.ListingRemoved: bySeller | byAdmin,
+	},
+	domain.ListingRemoved: {
+		domain.ListingActive: byAdmin,
+	},
+}
+
+func changerFor(actor Actor) statusChanger {
+	if actor.IsAdmin() {
+		return byAdmin
+	}
+	return bySeller
+}
+
+// checkTransition validates a status change by who.
+func checkTransition(who statusChanger, from, to domain.ListingStatus) error {
+	if listingTransitions[from][to]&who == 0 {
+		return fmt.Errorf("%w: %s to %s", ErrStatusTransition, from, to)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	geminiBaseURL      = "https://generativelanguage.googleapis.com/v1beta/models/"
+	defaultGeminiModel = "gemini-2.5-flash"
+)
+
+// GeminiProvider calls Google's generateContent API.
+type GeminiProvider struct {
+	apiKey string
+	model  string
+}
+
+var (
+	_ LLMProvider = (*GeminiProvider)(nil)
+	_ LLMStreamer = (*GeminiProvider)(nil)
+)
+
+func NewGeminiProvider(apiKey, model string) *GeminiProvider {
+	if model == "" {
+		model = defaultGeminiModel
+	}
+	return &GeminiProvider{apiKey: apiKey, model: model}
+}
+
+// ====== Gemini types ======
+
+type GeminiRequest struct {
+	Contents         []GeminiContent  `json:"contents"`
+	GenerationConfig *GeminiGenConfig `json:"generationConfig,omitempty"`
+}
+
+type GeminiContent struct {
+	Parts []GeminiPart `json:"parts"`
+}
+
+type GeminiPart struct {
+	Text string `json:"text"`
+}
+
+type GeminiGenConfig struct {
+	ResponseMimeType string `json:"responseMimeType,omitempty"`
+}
+
+type GeminiResponse struct {
+	Candidates []struct {
+		Content struct {
+			Parts []struct {
+				Text string `json:"text"`
+			} `json:"parts"`
+		} `json:"content"`
+	} `json:"candidates"`
+}
+
+func (p *GeminiProvider) Name() string { return LLMGemini + "/" + p.model }
+
+func (p *GeminiProvider) Complete(ctx context.Context, prompt string) (string, error) {
+	return p.generate(ctx, prompt, nil)
+}
+
+func (p *GeminiProvider) ExtractJSON(ctx context.Context, prompt string, out any) error {
+	text, err := p.generate(ctx, prompt, &GeminiGenConfig{ResponseMimeType: "application/json"})
+	if err != nil {
+		return err
+	}
+	return decodeJSONObject(text, out)
+}
+
+// Stream uses streamGenerateContent; each SSE event is a partial response.
+func (p *GeminiProvider) Stream(ctx context.Context, prompt string, onChunk func(string)) (string, error) {
+	reqBody := GeminiRequest{
+		Contents: []GeminiContent{
+			{Parts: []GeminiPart{{Text: prompt}}},
+		},
+	}
+	endpoint := geminiBaseURL + url.PathEscape(p.model) + ":streamGenerateContent?alt=sse"
+
+	var sb strings.Builder
+	err := postSSE(ctx, "gemini", endpoint, p.header(), reqBody, func(data []byte) error {
+		var gr GeminiResponse
+		if err := json.Unmarshal(data, &gr); err != nil {
+			return err
+		}
+		for _, c := range gr.Candidates {
+			for _, part := range c.Content.Parts {
+				if part.Text != "" {
+					sb.WriteString(part.Text)
+					onChunk(part.Text)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if sb.Len() == 0 {
+		return "", errors.New("no response from gemini")
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+func (p *GeminiProvider) header() http.Header {
+	return http.Header{"X-Goog-Api-Key": []string{p.apiKey}}
+}
+
+func (p *GeminiProvider) generate(ctx context.Context, prompt string, gen *GeminiGenConfig) (string, error) {
+	reqBody := GeminiRequest{
+		Contents: []GeminiContent{
+			{Parts: []GeminiPart{{Text: prompt}}},
+		},
+		GenerationConfig: gen,
+	}
+	endpoint := geminiBaseURL + url.PathEscape(p.model) + ":generateContent"
+
+	var gr GeminiResponse
+	if err := postJSON(ctx, "gemini", endpoint, p.header(), reqBody, &gr); err != nil {
+		return "", err
+	}
+	if len(gr.Candidates) == 0 || len(gr.Candidates[0].Content.Parts) == 0 {
+		return "", errors.New("no response from gemini")
+	}
+	return strings.TrimSpace(gr.Candidates[0].Content.Parts[0].Text), nil
+}
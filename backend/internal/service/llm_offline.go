@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// OfflineProvider answers without any network access. Replies are scripted
+// with On; prompts that match nothing get a fixed answer (Complete) or an
+// empty object (ExtractJSON), which makes AgentService fall back to its
+// keyword heuristics. Useful for local development and tests.
+type OfflineProvider struct {
+	mu      sync.RWMutex
+	replies []offlineReply
+}
+
+type offlineReply struct {
+	match string
+	reply string
+}
+
+var (
+	_ LLMProvider = (*OfflineProvider)(nil)
+	_ LLMStreamer = (*OfflineProvider)(nil)
+)
+
+const offlineAnswer = "I'm running in offline mode, so my answers are limited. Tell me what you're looking for, like \"MacBook under $500\", and I'll search the listings."
+
+func NewOfflineProvider() *OfflineProvider {
+	return &OfflineProvider{}
+}
+
+// On makes prompts containing match (case-insensitive) get reply. Earlier
+// rules win.
+func (p *OfflineProvider) On(match, reply string) *OfflineProvider {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.replies = append(p.replies, offlineReply{match: strings.ToLower(match), reply: reply})
+	return p
+}
+
+func (p *OfflineProvider) Name() string { return LLMOffline }
+
+func (p *OfflineProvider) Complete(_ context.Context, prompt string) (string, error) {
+	if r, ok := p.lookup(prompt); ok {
+		return r, nil
+	}
+	return offlineAnswer, nil
+}
+
+// Stream hands out the Complete answer word by word.
+func (p *OfflineProvider) Stream(ctx context.Context, prompt string, onChunk func(string)) (string, error) {
+	answer, _ := p.Complete(ctx, prompt)
+	for _, w := range strings.SplitAfter(answer, " ") {
+		onChunk(w)
+	}
+	return answer, nil
+}
+
+func (p *OfflineProvider) ExtractJSON(_ context.Context, prompt string, out any) error {
+	r, ok := p.lookup(prompt)
+	if !ok {
+		r = "{}"
+	}
+	return decodeJSONObject(r, out)
+}
+
+func (p *OfflineProvider) lookup(prompt string) (string, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	l := strings.ToLower(prompt)
+	for _, r := range p.replies {
+		if strings.Contains(l, r.match) {
+			return r.reply, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultOpenAIBaseURL = "https://api.openai.com/v1"
+	defaultOpenAIModel   = "gpt-4o-mini"
+)
+
+// OpenAIProvider calls a /chat/completions endpoint. BaseURL can point at any
+// OpenAI-compatible server (Azure, vLLM, Ollama, ...).
+type OpenAIProvider struct {
+	apiKey  string
+	model   string
+	baseURL string
+}
+
+var (
+	_ LLMProvider = (*OpenAIProvider)(nil)
+	_ LLMStreamer = (*OpenAIProvider)(nil)
+)
+
+func NewOpenAIProvider(apiKey, model, baseURL string) *OpenAIProvider {
+	if model == "" {
+		model = defaultOpenAIModel
+	}
+	if baseURL == "" {
+		baseURL = defaultOpenAIBaseURL
+	}
+	return &OpenAIProvider{apiKey: apiKey, model: model, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIResponseFormat struct {
+	Type string `json:"type"`
+}
+
+type openAIRequest struct {
+	Model          string                `json:"model"`
+	Messages       []openAIMessage       `json:"messages"`
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+	Stream         bool                  `json:"stream,omitempty"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+}
+
+type openAIStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
+
+func (p *OpenAIProvider) Name() string { return LLMOpenAI + "/" + p.model }
+
+func (p *OpenAIProvider) Complete(ctx context.Context, prompt string) (string, error) {
+	return p.chat(ctx, prompt, nil)
+}
+
+func (p *OpenAIProvider) ExtractJSON(ctx context.Context, prompt string, out any) error {
+	text, err := p.chat(ctx, prompt, &openAIResponseFormat{Type: "json_object"})
+	if err != nil {
+		return err
+	}
+	return decodeJSONObject(text, out)
+}
+
+// Stream sets "stream": true; chunks arrive as SSE events ending with [DONE].
+func (p *OpenAIProvider) Stream(ctx context.Context, prompt string, onChunk func(string)) (string, error) {
+	reqBody := openAIRequest{
+		Model:    p.model,
+		Messages: []openAIMessage{{Role: "user", Content: prompt}},
+		Stream:   true,
+	}
+
+	var sb strings.Builder
+	err := postSSE(ctx, "openai", p.baseURL+"/chat/completions", p.header(), reqBody, func(data []byte) error {
+		if string(data) == "[DONE]" {
+			return nil
+		}
+		var chunk openAIStreamChunk
+		if err := json.Unmarshal(data, &chunk); err != nil {
+			return err
+		}
+		for _, c := range chunk.Choices {
+			if c.Delta.Content != "" {
+				sb.WriteString(c.Delta.Content)
+				onChunk(c.Delta.Content)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if sb.Len() == 0 {
+		return "", errors.New("no response from openai")
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+func (p *OpenAIProvider) header() http.Header {
+	return http.Header{"Authorization": []string{"Bearer " + p.apiKey}}
+}
+
+func (p *OpenAIProvider) chat(ctx context.Context, prompt string, format *openAIResponseFormat) (string, error) {
+	reqBody := openAIRequest{
+		Model:          p.model,
+		Messages:       []openAIMessage{{Role: "user", Content: prompt}},
+		ResponseFormat: format,
+	}
+
+	var or openAIResponse
+	if err := postJSON(ctx, "openai", p.baseURL+"/chat/completions", p.header(), reqBody, &or); err != nil {
+		return "", err
+	}
+	if len(or.Choices) == 0 {
+		return "", errors.New("no response from openai")
+	}
+	return strings.TrimSpace(or.Choices[0].Message.Content), nil
+}
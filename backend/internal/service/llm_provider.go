@@ -0,0 +1,149 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// LLMProvider is the language model behind AgentService. Implementations:
+// GeminiProvider, OpenAIProvider (any OpenAI-compatible endpoint) and
+// OfflineProvider (deterministic, no network).
+type LLMProvider interface {
+	// Name identifies the provider in logs.
+	Name() string
+	// Complete returns the model's free-text answer to prompt.
+	Complete(ctx context.Context, prompt string) (string, error)
+	// ExtractJSON asks for a JSON object and decodes it into out.
+	ExtractJSON(ctx context.Context, prompt string, out any) error
+}
+
+// LLMStreamer is implemented by providers that can return their answer as it
+// is generated. onChunk sees each piece in order; the full text is returned.
+type LLMStreamer interface {
+	Stream(ctx context.Context, prompt string, onChunk func(string)) (string, error)
+}
+
+const (
+	LLMGemini  = "gemini"
+	LLMOpenAI  = "openai"
+	LLMOffline = "offline"
+)
+
+// LLMConfig selects and configures a provider. Model and BaseURL are optional.
+type LLMConfig struct {
+	Provider string
+	APIKey   string
+	Model    string
+	BaseURL  string
+}
+
+var ErrNoLLMKey = errors.New("llm provider needs an api key")
+
+// NewLLMProvider builds the provider named by cfg.Provider ("" means gemini).
+func NewLLMProvider(cfg LLMConfig) (LLMProvider, error) {
+	switch cfg.Provider {
+	case "", LLMGemini:
+		if cfg.APIKey == "" {
+			return nil, ErrNoLLMKey
+		}
+		return NewGeminiProvider(cfg.APIKey, cfg.Model), nil
+	case LLMOpenAI:
+		if cfg.APIKey == "" {
+			return nil, ErrNoLLMKey
+		}
+		return NewOpenAIProvider(cfg.APIKey, cfg.Model, cfg.BaseURL), nil
+	case LLMOffline:
+		return NewOfflineProvider(), nil
+	default:
+		return nil, fmt.Errorf("unknown llm provider %q", cfg.Provider)
+	}
+}
+
+var llmHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// Streams can legitimately outlive llmHTTPClient's timeout, so they are
+// bounded by a context deadline (llmStreamTimeout) instead.
+var llmStreamClient = &http.Client{}
+
+const llmStreamTimeout = 90 * time.Second
+
+// postJSON sends body as JSON and decodes a 200 response into out.
+func postJSON(ctx context.Context, provider, url string, header http.Header, body, out any) error {
+	resp, err := doPost(ctx, llmHTTPClient, provider, url, header, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// postSSE sends body as JSON and calls onData with the payload of every
+// server-sent "data:" line until the stream ends or onData returns an error.
+func postSSE(ctx context.Context, provider, url string, header http.Header, body any, onData func([]byte) error) error {
+	resp, err := doPost(ctx, llmStreamClient, provider, url, header, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		data, ok := bytes.CutPrefix(sc.Bytes(), []byte("data:"))
+		if !ok {
+			continue // blank separators, comments, event names
+		}
+		if err := onData(bytes.TrimSpace(data)); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+func doPost(ctx context.Context, client *http.Client, provider, url string, header http.Header, body any) (*http.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%s api error: %d - %s", provider, resp.StatusCode, string(b))
+	}
+	return resp, nil
+}
+
+// decodeJSONObject decodes the outermost {...} in text, tolerating prose or
+// code fences around it.
+func decodeJSONObject(text string, out any) error {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start == -1 || end < start {
+		return errors.New("no json object in llm response")
+	}
+	if err := json.Unmarshal([]byte(text[start:end+1]), out); err != nil {
+		return fmt.Errorf("failed to parse llm json: %w", err)
+	}
+	return nil
+}
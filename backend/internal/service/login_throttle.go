@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+)
+
+// ErrTooManyAttempts is what a *ThrottledError unwraps to.
+var ErrTooManyAttempts = errors.New("too many failed sign-in attempts")
+
+// ThrottledError refuses a sign-in before the password is checked, either
+// while the account backs off after a failure or while the account or IP is
+// locked. RetryAfter is RetryAt measured from the service's clock. Reason is
+// set when something other than sign-in is throttled.
+type ThrottledError struct {
+	Locked     bool
+	RetryAt    time.Time
+	RetryAfter time.Duration
+	Reason     error
+}
+
+func (e *ThrottledError) Error() string {
+	if e.Reason != nil {
+		return fmt.Sprintf("%v, retry at %s", e.Reason, e.RetryAt.Format(time.RFC3339))
+	}
+	if e.Locked {
+		return fmt.Sprintf("sign-in locked until %s", e.RetryAt.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("too many failed sign-in attempts, retry at %s", e.RetryAt.Format(time.RFC3339))
+}
+
+func (e *ThrottledError) Unwrap() error {
+	if e.Reason != nil {
+		return e.Reason
+	}
+	return ErrTooManyAttempts
+}
+
+// LoginPolicy slows down failed sign-ins per account and per IP. After each
+// failure on an account its next attempt must wait BackoffBase, doubling per
+// failure up to BackoffMax, and MaxFailures in a row lock it for Lockout. An
+// IP may be shared by a whole campus behind NAT, so it never backs off; only
+// IPMaxFailures in a row lock it. Failures older than Lockout are forgotten.
+type LoginPolicy struct {
+	MaxFailures   int
+	IPMaxFailures int
+	Lockout       time.Duration
+	BackoffBase   time.Duration
+	BackoffMax    time.Duration
+}
+
+var DefaultLoginPolicy = LoginPolicy{
+	MaxFailures:   5,
+	IPMaxFailures: 50,
+	Lockout:       15 * time.Minute,
+	BackoffBase:   time.Second,
+	BackoffMax:    30 * time.Second,
+}
+
+// backoff is how long to wait after the n-th failure in a row.
+func (p LoginPolicy) backoff(n int) time.Duration {
+	d := p.BackoffBase
+	for i := 1; i < n && d < p.BackoffMax; i++ {
+		d *= 2
+	}
+	return min(d, p.BackoffMax)
+}
+
+type loginThrottle struct {
+	attempts repository.LoginAttemptRepo
+	audit    repository.AuditRepo
+	policy   LoginPolicy
+}
+
+// EnableLoginThrottle turns on backoff and lockout for failed sign-ins;
+// zero fields of p take DefaultLoginPolicy's values. Lockouts are written to
+// audit.
+func (s *AuthService) EnableLoginThrottle(attempts repository.LoginAttemptRepo, audit repository.AuditRepo, p LoginPolicy) {
+	d := DefaultLoginPolicy
+	if p.MaxFailures <= 0 {
+		p.MaxFailures = d.MaxFailures
+	}
+	if p.IPMaxFailures <= 0 {
+		p.IPMaxFailures = d.IPMaxFailures
+	}
+	if p.Lockout <= 0 {
+		p.Lockout = d.Lockout
+	}
+	if p.BackoffBase <= 0 {
+		p.BackoffBase = d.BackoffBase
+	}
+	if p.BackoffMax <= 0 {
+		p.BackoffMax = d.BackoffMax
+	}
+	s.throttle = &loginThrottle{attempts: attempts, audit: audit, policy: p}
+}
+
+// throttleKey is one counter a sign-in attempt goes against.
+type throttleKey struct {
+	key         string
+	maxFailures int
+	backoff     bool
+}
+
+func (t *loginThrottle) keys(cmd SignInCmd) []throttleKey {
+	keys := []throttleKey{{accountKey(cmd.Email), t.policy.MaxFailures, true}}
+	if cmd.IP != "" {
+		keys = append(keys, throttleKey{"ip:" + cmd.IP, t.policy.IPMaxFailures, false})
+	}
+	return keys
+}
+
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// checkThrottle refuses the attempt if its account or IP is locked or the
+// account is still backing off.
+func (s *AuthService) checkThrottle(ctx context.Context, cmd SignInCmd) error {
+	if s.throttle == nil {
+		return nil
+	}
+	now := s.clk.Now()
+	for _, k := range s.throttle.keys(cmd) {
+		a, err := s.throttle.attempts.Get(ctx, k.key)
+		if err != nil {
+			return err
+		}
+		if a.LockedUntil != nil && now.Before(*a.LockedUntil) {
+			return &ThrottledError{Locked: true, RetryAt: *a.LockedUntil, RetryAfter: a.LockedUntil.Sub(now)}
+		}
+		if !k.backoff || a.Failures == 0 {
+			continue
+		}
+		if retry := a.LastFailedAt.Add(s.throttle.policy.backoff(a.Failures)); now.Before(retry) {
+			return &ThrottledError{RetryAt: retry, RetryAfter: retry.Sub(now)}
+		}
+	}
+	return nil
+}
+
+// loginFailed counts a failed attempt against its account and IP, locking
+// whichever reaches its limit.
+func (s *AuthService) loginFailed(ctx context.Context, cmd SignInCmd) error {
+	if s.throttle == nil {
+		return nil
+	}
+	p := s.throttle.policy
+	now := s.clk.Now()
+	for _, k := range s.throttle.keys(cmd) {
+		n, err := s.throttle.attempts.RecordFailure(ctx, k.key, now, now.Add(-p.Lockout))
+		if err != nil {
+			return err
+		}
+		// Concurrent failures can carry the count past the limit, and a lock
+		// can run out before the count does, so any failure at or over the
+		// limit locks unless a lock is already in place.
+		if n < k.maxFailures {
+			continue
+		}
+		locked, err := s.throttle.attempts.Lock(ctx, k.key, now, now.Add(p.Lockout))
+		if err != nil {
+			return err
+		}
+		if !locked {
+			continue
+		}
+		if err := s.throttle.audit.Add(ctx, domain.AuditEntry{
+			Action:    domain.AuditLoginLocked,
+			Subject:   k.key,
+			Detail:    fmt.Sprintf("%d failed sign-ins, locked for %s", n, p.Lockout),
+			CreatedAt: now,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loginSucceeded forgets the account's failures. The IP's stay, so guessing
+// across many accounts from one address still adds up.
+func (s *AuthService) loginSucceeded(ctx context.Context, cmd SignInCmd) error {
+	if s.throttle == nil {
+		return nil
+	}
+	return s.throttle.attempts.Clear(ctx, accountKey(cmd.Email))
+}
+
+// UnlockAccount lifts a sign-in lockout on the user's account and forgets
+// its failures, recording who did it. A locked IP is not tied to any one
+// user, so it stays locked until its Lockout runs out.
+func (s *AuthService) UnlockAccount(ctx context.Context, actor Actor, userID uuid.UUID) error {
+	u, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if s.throttle == nil {
+		return nil
+	}
+	key := accountKey(u.Email)
+	if err := s.throttle.attempts.Clear(ctx, key); err != nil {
+		return err
+	}
+	return s.throttle.audit.Add(ctx, domain.AuditEntry{
+		Action:    domain.AuditLoginUnlocked,
+		ActorID:   &actor.UserID,
+		Subject:   key,
+		CreatedAt: s.clk.Now(),
+	})
+}
@@ -0,0 +1,388 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+)
+
+const testPassword = "correct horse"
+
+type fakeAuthRepo struct {
+	repository.AuthRepo
+	users  map[string]domain.User // by lowercased email
+	hashes map[uuid.UUID]string
+}
+
+func newFakeAuthRepo(t *testing.T, emails ...string) *fakeAuthRepo {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &fakeAuthRepo{users: map[string]domain.User{}, hashes: map[uuid.UUID]string{}}
+	for _, e := range emails {
+		u := domain.User{ID: uuid.New(), Email: e, Role: "user"}
+		r.users[strings.ToLower(e)] = u
+		r.hashes[u.ID] = string(hash)
+	}
+	return r
+}
+
+func (r *fakeAuthRepo) GetByEmail(_ context.Context, email string) (domain.User, string, error) {
+	u, ok := r.users[strings.ToLower(email)]
+	if !ok {
+		return domain.User{}, "", domain.ErrNotFound
+	}
+	return u, r.hashes[u.ID], nil
+}
+
+func (r *fakeAuthRepo) GetByID(_ context.Context, id uuid.UUID) (domain.User, error) {
+	for _, u := range r.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return domain.User{}, domain.ErrNotFound
+}
+
+type fakeSessionRepo struct{ repository.SessionRepo }
+
+func (fakeSessionRepo) Create(_ context.Context, in repository.CreateSession) (domain.Session, error) {
+	return domain.Session{ID: in.ID, UserID: in.UserID, ExpiresAt: in.ExpiresAt}, nil
+}
+
+type fakeSigner struct{}
+
+func (fakeSigner) SignJWT(domain.User, string, string, time.Time) (string, error) {
+	return "token", nil
+}
+
+// fakeLoginAttempts mirrors LoginAttemptRepoPG's upsert in memory.
+type fakeLoginAttempts struct {
+	mu   sync.Mutex
+	keys map[string]domain.LoginAttempts
+}
+
+func (r *fakeLoginAttempts) Get(_ context.Context, key string) (domain.LoginAttempts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.keys[key], nil
+}
+
+func (r *fakeLoginAttempts) RecordFailure(_ context.Context, key string, at, staleBefore time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a, ok := r.keys[key]
+	if ok && a.LastFailedAt.Before(staleBefore) {
+		a = domain.LoginAttempts{}
+	}
+	a.Failures++
+	a.LastFailedAt = at
+	r.keys[key] = a
+	return a.Failures, nil
+}
+
+func (r *fakeLoginAttempts) Lock(_ context.Context, key string, now, until time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a, ok := r.keys[key]
+	if !ok || (a.LockedUntil != nil && a.LockedUntil.After(now)) {
+		return false, nil
+	}
+	a.LockedUntil = &until
+	r.keys[key] = a
+	return true, nil
+}
+
+func (r *fakeLoginAttempts) Clear(_ context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.keys, key)
+	return nil
+}
+
+type fakeAudit struct {
+	mu      sync.Mutex
+	entries []domain.AuditEntry
+}
+
+func (r *fakeAudit) Add(_ context.Context, e domain.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, e)
+	return nil
+}
+
+var testLoginPolicy = LoginPolicy{
+	MaxFailures:   5,
+	IPMaxFailures: 6,
+	Lockout:       10 * time.Minute,
+	BackoffBase:   time.Second,
+	BackoffMax:    4 * time.Second,
+}
+
+type throttleFixture struct {
+	auth  *AuthService
+	users *fakeAuthRepo
+	clock *fakeClock
+	audit *fakeAudit
+}
+
+func newThrottleFixture(t *testing.T) throttleFixture {
+	users := newFakeAuthRepo(t, "alice@sjsu.edu", "bob@sjsu.edu", "carol@sjsu.edu")
+	clk := newFakeClock()
+	auth := NewAuthService(users, fakeSessionRepo{}, fakeSigner{}, clk, time.Hour, 0)
+	audit := &fakeAudit{}
+	auth.EnableLoginThrottle(&fakeLoginAttempts{keys: map[string]domain.LoginAttempts{}}, audit, testLoginPolicy)
+	return throttleFixture{auth: auth, users: users, clock: clk, audit: audit}
+}
+
+type outcome int
+
+const (
+	signedIn   outcome = iota
+	denied             // wrong password
+	backingOff         // throttled, not locked
+	locked
+)
+
+func (o outcome) String() string {
+	return [...]string{"signed in", "denied", "backing off", "locked"}[o]
+}
+
+func outcomeOf(err error) (outcome, time.Duration) {
+	var th *ThrottledError
+	switch {
+	case err == nil:
+		return signedIn, 0
+	case errors.As(err, &th) && th.Locked:
+		return locked, th.RetryAfter
+	case errors.As(err, &th):
+		return backingOff, th.RetryAfter
+	case errors.Is(err, ErrUnauthorized):
+		return denied, 0
+	}
+	return -1, 0
+}
+
+// attempt is one sign-in, after waiting.
+type attempt struct {
+	wait       time.Duration
+	email      string // alice@sjsu.edu if empty
+	ip         string // 10.0.0.1 if empty
+	right      bool   // the correct password
+	want       outcome
+	retryAfter time.Duration // when throttled
+}
+
+func TestLoginThrottle(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts []attempt
+	}{
+		{
+			name: "backoff doubles per failure",
+			attempts: []attempt{
+				{want: denied},
+				{want: backingOff, retryAfter: time.Second},
+				{wait: time.Second, want: denied},
+				{wait: time.Second, want: backingOff, retryAfter: time.Second},
+				{wait: time.Second, want: denied},
+				{want: backingOff, retryAfter: 4 * time.Second},
+				{wait: 4 * time.Second, right: true, want: signedIn},
+			},
+		},
+		{
+			name: "backoff is capped",
+			attempts: []attempt{
+				{want: denied},
+				{wait: time.Second, want: denied},
+				{wait: 2 * time.Second, want: denied},
+				{wait: 4 * time.Second, want: denied},
+				{want: backingOff, retryAfter: 4 * time.Second},
+				{wait: 4 * time.Second, right: true, want: signedIn},
+			},
+		},
+		{
+			name: "locks at max failures",
+			attempts: []attempt{
+				{want: denied},
+				{wait: time.Second, want: denied},
+				{wait: 2 * time.Second, want: denied},
+				{wait: 4 * time.Second, want: denied},
+				{wait: 4 * time.Second, want: denied},
+				{wait: time.Minute, right: true, want: locked, retryAfter: 9 * time.Minute},
+				{wait: 9 * time.Minute, right: true, want: signedIn},
+			},
+		},
+		{
+			name: "old failures are forgotten",
+			attempts: []attempt{
+				{want: denied},
+				{wait: time.Second, want: denied},
+				{wait: 11 * time.Minute, want: denied},
+				{wait: time.Second, want: denied},
+				{wait: 2 * time.Second, want: denied},
+				{wait: 4 * time.Second, right: true, want: signedIn},
+			},
+		},
+		{
+			name: "sign-in clears the account",
+			attempts: []attempt{
+				{want: denied},
+				{wait: time.Second, want: denied},
+				{wait: 2 * time.Second, right: true, want: signedIn},
+				{want: denied},
+			},
+		},
+		{
+			name: "shared IP does not back off but locks",
+			attempts: []attempt{
+				{email: "alice@sjsu.edu", want: denied},
+				{email: "bob@sjsu.edu", want: denied},
+				{email: "carol@sjsu.edu", want: denied},
+				{email: "nobody@sjsu.edu", want: denied},
+				{email: "nobody2@sjsu.edu", want: denied},
+				{email: "nobody3@sjsu.edu", want: denied},
+				{wait: time.Minute, email: "bob@sjsu.edu", right: true, want: locked, retryAfter: 9 * time.Minute},
+				{email: "bob@sjsu.edu", ip: "10.0.0.2", right: true, want: signedIn},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newThrottleFixture(t)
+			for i, a := range tt.attempts {
+				f.clock.Advance(a.wait)
+				cmd := SignInCmd{Email: a.email, Password: "wrong", IP: a.ip}
+				if cmd.Email == "" {
+					cmd.Email = "alice@sjsu.edu"
+				}
+				if cmd.IP == "" {
+					cmd.IP = "10.0.0.1"
+				}
+				if a.right {
+					cmd.Password = testPassword
+				}
+				_, err := f.auth.SignIn(context.Background(), cmd)
+				got, retryAfter := outcomeOf(err)
+				if got != a.want {
+					t.Fatalf("attempt %d: %v (%v), want %v", i, got, err, a.want)
+				}
+				if (got == backingOff || got == locked) && retryAfter != a.retryAfter {
+					t.Errorf("attempt %d: retry after %s, want %s", i, retryAfter, a.retryAfter)
+				}
+			}
+		})
+	}
+}
+
+func TestLoginLockIsAudited(t *testing.T) {
+	f := newThrottleFixture(t)
+	ctx := context.Background()
+	for i := range testLoginPolicy.MaxFailures {
+		f.clock.Advance(testLoginPolicy.BackoffMax)
+		if _, err := f.auth.SignIn(ctx, SignInCmd{Email: "alice@sjsu.edu", Password: "wrong"}); !errors.Is(err, ErrUnauthorized) {
+			t.Fatalf("failure %d: %v", i, err)
+		}
+	}
+	if len(f.audit.entries) != 1 {
+		t.Fatalf("audit has %d entries, want 1", len(f.audit.entries))
+	}
+	e := f.audit.entries[0]
+	if e.Action != domain.AuditLoginLocked || e.Subject != "account:alice@sjsu.edu" || e.ActorID != nil {
+		t.Errorf("audit entry = %+v, want a system lock of alice's account", e)
+	}
+}
+
+// A count already past the limit with no lock in place, as after concurrent
+// failures skip over it or a lock runs out first, still locks on the next
+// failure, and only once.
+func TestLoginLocksPastTheLimit(t *testing.T) {
+	users := newFakeAuthRepo(t, "alice@sjsu.edu")
+	clk := newFakeClock()
+	attempts := &fakeLoginAttempts{keys: map[string]domain.LoginAttempts{}}
+	audit := &fakeAudit{}
+	auth := NewAuthService(users, fakeSessionRepo{}, fakeSigner{}, clk, time.Hour, 0)
+	auth.EnableLoginThrottle(attempts, audit, testLoginPolicy)
+	ctx := context.Background()
+
+	expired := clk.Now().Add(-time.Second)
+	attempts.keys["ip:10.0.0.1"] = domain.LoginAttempts{
+		Failures:     testLoginPolicy.IPMaxFailures + 2,
+		LastFailedAt: clk.Now().Add(-time.Minute),
+		LockedUntil:  &expired,
+	}
+	if _, err := auth.SignIn(ctx, SignInCmd{Email: "nobody@sjsu.edu", Password: "wrong", IP: "10.0.0.1"}); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("failure past the limit: %v, want ErrUnauthorized", err)
+	}
+	if got, retryAfter := outcomeOf(func() error {
+		_, err := auth.SignIn(ctx, SignInCmd{Email: "alice@sjsu.edu", Password: testPassword, IP: "10.0.0.1"})
+		return err
+	}()); got != locked || retryAfter != testLoginPolicy.Lockout {
+		t.Fatalf("next sign-in: %v, retry after %s, want locked for %s", got, retryAfter, testLoginPolicy.Lockout)
+	}
+
+	// Another failure racing in under the new lock doesn't lock or audit
+	// again.
+	if err := auth.loginFailed(ctx, SignInCmd{Email: "nobody@sjsu.edu", IP: "10.0.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(audit.entries) != 1 || audit.entries[0].Subject != "ip:10.0.0.1" {
+		t.Errorf("audit = %+v, want one lock of the IP", audit.entries)
+	}
+}
+
+func TestUnlockAccount(t *testing.T) {
+	f := newThrottleFixture(t)
+	ctx := context.Background()
+	alice := f.users.users["alice@sjsu.edu"]
+	admin := Actor{UserID: uuid.New(), Role: "admin"}
+
+	signIn := func(email, ip, password string) error {
+		_, err := f.auth.SignIn(ctx, SignInCmd{Email: email, Password: password, IP: ip})
+		return err
+	}
+	// Lock alice's account from one IP and the other IP by guessing at
+	// unknown accounts.
+	for range testLoginPolicy.MaxFailures {
+		f.clock.Advance(testLoginPolicy.BackoffMax)
+		_ = signIn("alice@sjsu.edu", "10.0.0.1", "wrong")
+	}
+	for i := range testLoginPolicy.IPMaxFailures {
+		_ = signIn("guess"+string(rune('a'+i))+"@sjsu.edu", "10.0.0.2", "wrong")
+	}
+	if got, _ := outcomeOf(signIn("alice@sjsu.edu", "10.0.0.3", testPassword)); got != locked {
+		t.Fatalf("before unlock: %v, want locked", got)
+	}
+
+	if err := f.auth.UnlockAccount(ctx, admin, alice.ID); err != nil {
+		t.Fatalf("UnlockAccount: %v", err)
+	}
+	if err := signIn("alice@sjsu.edu", "10.0.0.3", testPassword); err != nil {
+		t.Errorf("after unlock: %v, want signed in", err)
+	}
+	// The IP lock is not the account's and stays until it runs out.
+	if got, _ := outcomeOf(signIn("alice@sjsu.edu", "10.0.0.2", testPassword)); got != locked {
+		t.Errorf("from the locked IP: %v, want locked", got)
+	}
+
+	last := f.audit.entries[len(f.audit.entries)-1]
+	if last.Action != domain.AuditLoginUnlocked || last.ActorID == nil || *last.ActorID != admin.UserID || last.Subject != "account:alice@sjsu.edu" {
+		t.Errorf("last audit entry = %+v, want alice's unlock by the admin", last)
+	}
+
+	if err := f.auth.UnlockAccount(ctx, admin, uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("unlocking an unknown user: %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/pubsub"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+)
+
+// Notifier records an in-app notification for a user. Failures are logged,
+// not returned: whatever caused the notification has already happened.
+type Notifier interface {
+	Notify(ctx context.Context, n NewNotification)
+}
+
+// NewNotification is one notification to store. Data is marshalled to JSON.
+type NewNotification struct {
+	UserID uuid.UUID
+	Kind   string
+	Title  string
+	Body   string
+	Data   any
+}
+
+var _ Notifier = (*NotificationService)(nil)
+
+// NotificationService stores notifications and publishes each new one on
+// "notification.new", which the WebSocket hub pushes to the user if they are
+// connected.
+type NotificationService struct {
+	repo   repository.NotificationRepo
+	bus    pubsub.Broker // nil: notifications are only stored
+	logger *zap.Logger
+}
+
+func NewNotificationService(r repository.NotificationRepo, bus pubsub.Broker, logger *zap.Logger) *NotificationService {
+	return &NotificationService{repo: r, bus: bus, logger: logger}
+}
+
+func (s *NotificationService) Notify(ctx context.Context, n NewNotification) {
+	var data json.RawMessage
+	if n.Data != nil {
+		raw, err := json.Marshal(n.Data)
+		if err != nil {
+			s.logger.Error("marshal notification data failed", zap.String("kind", n.Kind), zap.Error(err))
+			return
+		}
+		data = raw
+	}
+	saved, err := s.repo.Create(ctx, repository.CreateNotification{
+		UserID: n.UserID, Kind: n.Kind, Title: n.Title, Body: n.Body, Data: data,
+	})
+	if err != nil {
+		s.logger.Error("store notification failed", zap.String("userId", n.UserID.String()), zap.String("kind", n.Kind), zap.Error(err))
+		return
+	}
+	if s.bus != nil {
+		s.bus.Publish("notification.new", pubsub.NotificationEvent{
+			UserID:    saved.UserID.String(),
+			ID:        saved.ID.String(),
+			Kind:      saved.Kind,
+			Title:     saved.Title,
+			Body:      saved.Body,
+			Data:      saved.Data,
+			CreatedAt: saved.CreatedAt.Format(time.RFC3339Nano),
+		})
+	}
+}
+
+type ListNotificationsQuery struct {
+	UserID        uuid.UUID
+	UnreadOnly    bool
+	Cursor        string
+	Limit, Offset int
+}
+
+// List returns the user's notifications, newest first, plus their unread
+// count.
+func (s *NotificationService) List(ctx context.Context, q ListNotificationsQuery) ([]domain.Notification, int, int, error) {
+	return s.repo.List(ctx, repository.NotificationListParams{
+		UserID: q.UserID, UnreadOnly: q.UnreadOnly, Cursor: q.Cursor, Limit: q.Limit, Offset: q.Offset,
+	})
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, userID, id uuid.UUID) (domain.Notification, error) {
+	return s.repo.MarkRead(ctx, userID, id)
+}
+
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID uuid.UUID) (int, error) {
+	return s.repo.MarkAllRead(ctx, userID)
+}
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/clock"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+)
+
+var (
+	ErrOfferOwnListing     = errors.New("cannot make an offer on your own listing")
+	ErrOfferAmount         = errors.New("offer amount must be greater than zero")
+	ErrOfferExists         = errors.New("you already have an open offer on this listing")
+	ErrOfferTransition     = errors.New("offer cannot be changed that way in its current state")
+	ErrListingNotAvailable = errors.New("listing is not available")
+)
+
+// OfferAction is what a party does to an offer.
+type OfferAction string
+
+const (
+	OfferCounter  OfferAction = "counter"
+	OfferAccept   OfferAction = "accept"
+	OfferReject   OfferAction = "reject"
+	OfferWithdraw OfferAction = "withdraw"
+)
+
+type offerParty string
+
+const (
+	offerBuyer  offerParty = "buyer"
+	offerSeller offerParty = "seller"
+)
+
+type offerMove struct {
+	from   domain.OfferStatus
+	action OfferAction
+	by     offerParty
+}
+
+// offerTransitions is the offer state machine. Whoever the offer is waiting
+// on (the seller while pending, the buyer while countered) may accept, reject
+// or counter; the buyer may withdraw at any point before it is settled.
+// Rejected and withdrawn are final. Accepted stays until the reservation it
+// made ends: a sale keeps it, otherwise the listing repo moves it to expired
+// or cancelled.
+var offerTransitions = map[offerMove]domain.OfferStatus{
+	{domain.OfferPending, OfferCounter, offerSeller}: domain.OfferCountered,
+	{domain.OfferPending, OfferAccept, offerSeller}:  domain.OfferAccepted,
+	{domain.OfferPending, OfferReject, offerSeller}:  domain.OfferRejected,
+	{domain.OfferPending, OfferWithdraw, offerBuyer}: domain.OfferWithdrawn,
+
+	{domain.OfferCountered, OfferCounter, offerBuyer}:  domain.OfferPending,
+	{domain.OfferCountered, OfferAccept, offerBuyer}:   domain.OfferAccepted,
+	{domain.OfferCountered, OfferReject, offerBuyer}:   domain.OfferRejected,
+	{domain.OfferCountered, OfferWithdraw, offerBuyer}: domain.OfferWithdrawn,
+}
+
+// DefaultReservationTTL is how long an accepted offer holds the listing.
+const DefaultReservationTTL = 48 * time.Hour
+
+// OfferService runs price negotiation between a buyer and a listing's seller.
+type OfferService struct {
+	offers         repository.OfferRepo
+	listings       repository.ListingRepo
+	clock          clock.Clock
+	reservationTTL time.Duration
+	notifier       Notifier // nil: the other party is not notified
+}
+
+func NewOfferService(o repository.OfferRepo, l repository.ListingRepo, clk clock.Clock, reservationTTL time.Duration, n Notifier) *OfferService {
+	if reservationTTL <= 0 {
+		reservationTTL = DefaultReservationTTL
+	}
+	return &OfferService{offers: o, listings: l, clock: clk, reservationTTL: reservationTTL, notifier: n}
+}
+
+type MakeOfferCmd struct {
+	BuyerID   uuid.UUID
+	ListingID uuid.UUID
+	Amount    float64
+	Message   string
+}
+
+// Make opens an offer on an active listing.
+func (s *OfferService) Make(ctx context.Context, cmd MakeOfferCmd) (domain.Offer, error) {
+	if cmd.Amount <= 0 {
+		return domain.Offer{}, ErrOfferAmount
+	}
+	l, err := s.listings.Get(ctx, cmd.ListingID)
+	if err != nil {
+		return domain.Offer{}, err
+	}
+	if l.SellerID == cmd.BuyerID {
+		return domain.Offer{}, ErrOfferOwnListing
+	}
+	if l.Status != domain.ListingActive {
+		return domain.Offer{}, ErrListingNotAvailable
+	}
+	o, err := s.offers.Create(ctx, repository.CreateOffer{
+		ListingID: l.ID,
+		BuyerID:   cmd.BuyerID,
+		SellerID:  l.SellerID,
+		Amount:    cmd.Amount,
+		Message:   strings.TrimSpace(cmd.Message),
+	})
+	if errors.Is(err, domain.ErrConflict) {
+		return domain.Offer{}, ErrOfferExists
+	}
+	if err != nil {
+		return domain.Offer{}, err
+	}
+	s.notify(ctx, o, o.SellerID, "New offer on "+l.Title, fmt.Sprintf("$%.2f offered.", o.Amount))
+	return o, nil
+}
+
+// Get returns an offer to its buyer, its seller or an admin.
+func (s *OfferService) Get(ctx context.Context, actor Actor, id uuid.UUID) (domain.Offer, error) {
+	o, err := s.offers.Get(ctx, id)
+	if err != nil {
+		return domain.Offer{}, err
+	}
+	if !actor.IsAdmin() && actor.UserID != o.BuyerID && actor.UserID != o.SellerID {
+		return domain.Offer{}, domain.ErrForbidden
+	}
+	return o, nil
+}
+
+// ListForListing shows the seller (and admins) every offer on the listing;
+// anyone else sees only their own.
+func (s *OfferService) ListForListing(ctx context.Context, actor Actor, listingID uuid.UUID, status string, limit, offset int) ([]domain.Offer, int, error) {
+	l, err := s.listings.Get(ctx, listingID)
+	if err != nil {
+		return nil, 0, err
+	}
+	p := repository.OfferListParams{ListingID: &l.ID, Status: status, Limit: limit, Offset: offset}
+	if !actor.IsAdmin() && actor.UserID != l.SellerID {
+		p.BuyerID = &actor.UserID
+	}
+	return s.offers.List(ctx, p)
+}
+
+// ListMine returns offers the user made (role "buyer") or received ("seller").
+func (s *OfferService) ListMine(ctx context.Context, userID uuid.UUID, role, status string, limit, offset int) ([]domain.Offer, int, error) {
+	p := repository.OfferListParams{Status: status, Limit: limit, Offset: offset}
+	if offerParty(role) == offerSeller {
+		p.SellerID = &userID
+	} else {
+		p.BuyerID = &userID
+	}
+	return s.offers.List(ctx, p)
+}
+
+type CounterOfferCmd struct {
+	Amount  float64
+	Message string
+}
+
+func (s *OfferService) Counter(ctx context.Context, actor Actor, id uuid.UUID, cmd CounterOfferCmd) (domain.Offer, error) {
+	if cmd.Amount <= 0 {
+		return domain.Offer{}, ErrOfferAmount
+	}
+	o, to, err := s.transition(ctx, actor, id, OfferCounter)
+	if err != nil {
+		return domain.Offer{}, err
+	}
+	msg := strings.TrimSpace(cmd.Message)
+	countered, err := s.update(ctx, o.ID, o.Status, to, &cmd.Amount, &msg)
+	if err != nil {
+		return domain.Offer{}, err
+	}
+	s.notify(ctx, countered, otherParty(countered, actor.UserID), "Counter offer", fmt.Sprintf("$%.2f proposed.", countered.Amount))
+	return countered, nil
+}
+
+// Accept settles the offer and reserves the listing for the buyer for the
+// reservation TTL. The listing's other open offers are rejected.
+func (s *OfferService) Accept(ctx context.Context, actor Actor, id uuid.UUID) (domain.Offer, error) {
+	o, _, err := s.transition(ctx, actor, id, OfferAccept)
+	if err != nil {
+		return domain.Offer{}, err
+	}
+	l, err := s.listings.Get(ctx, o.ListingID)
+	if err != nil {
+		return domain.Offer{}, err
+	}
+	if checkTransition(bySystem, l.Status, domain.ListingReserved) != nil {
+		return domain.Offer{}, ErrListingNotAvailable
+	}
+	accepted, rejected, err := s.offers.Accept(ctx, o.ID, o.Status, actor.UserID, s.clock.Now().Add(s.reservationTTL))
+	if errors.Is(err, domain.ErrConflict) {
+		// Either the offer moved on or the listing was taken meanwhile; the
+		// offer tells which.
+		if cur, getErr := s.offers.Get(ctx, o.ID); getErr == nil && cur.Status != o.Status {
+			return domain.Offer{}, ErrOfferTransition
+		}
+		return domain.Offer{}, ErrListingNotAvailable
+	}
+	if err != nil {
+		return domain.Offer{}, err
+	}
+	s.notify(ctx, accepted, otherParty(accepted, actor.UserID), "Offer accepted: "+l.Title,
+		fmt.Sprintf("$%.2f agreed; the listing is reserved for the buyer.", accepted.Amount))
+	for _, r := range rejected {
+		s.notify(ctx, r, r.BuyerID, "Offer rejected", "The listing was reserved for another buyer.")
+	}
+	return accepted, nil
+}
+
+func (s *OfferService) Reject(ctx context.Context, actor Actor, id uuid.UUID) (domain.Offer, error) {
+	o, to, err := s.transition(ctx, actor, id, OfferReject)
+	if err != nil {
+		return domain.Offer{}, err
+	}
+	rejected, err := s.update(ctx, o.ID, o.Status, to, nil, nil)
+	if err != nil {
+		return domain.Offer{}, err
+	}
+	s.notify(ctx, rejected, otherParty(rejected, actor.UserID), "Offer rejected", fmt.Sprintf("The $%.2f offer was rejected.", rejected.Amount))
+	return rejected, nil
+}
+
+func (s *OfferService) Withdraw(ctx context.Context, actor Actor, id uuid.UUID) (domain.Offer, error) {
+	o, to, err := s.transition(ctx, actor, id, OfferWithdraw)
+	if err != nil {
+		return domain.Offer{}, err
+	}
+	withdrawn, err := s.update(ctx, o.ID, o.Status, to, nil, nil)
+	if err != nil {
+		return domain.Offer{}, err
+	}
+	s.notify(ctx, withdrawn, withdrawn.SellerID, "Offer withdrawn", "The buyer withdrew their offer.")
+	return withdrawn, nil
+}
+
+// transition loads the offer and looks up where action by actor leads.
+func (s *OfferService) transition(ctx context.Context, actor Actor, id uuid.UUID, action OfferAction) (domain.Offer, domain.OfferStatus, error) {
+	o, err := s.offers.Get(ctx, id)
+	if err != nil {
+		return domain.Offer{}, "", err
+	}
+	var by offerParty
+	switch actor.UserID {
+	case o.BuyerID:
+		by = offerBuyer
+	case o.SellerID:
+		by = offerSeller
+	default:
+		return domain.Offer{}, "", domain.ErrForbidden
+	}
+	to, ok := offerTransitions[offerMove{o.Status, action, by}]
+	if !ok {
+		return domain.Offer{}, "", ErrOfferTransition
+	}
+	return o, to, nil
+}
+
+// update applies a transition; a concurrent change to the offer means the
+// move is no longer valid.
+func (s *OfferService) update(ctx context.Context, id uuid.UUID, from, to domain.OfferStatus, amount *float64, message *string) (domain.Offer, error) {
+	o, err := s.offers.UpdateStatus(ctx, id, from, to, amount, message)
+	if errors.Is(err, domain.ErrConflict) {
+		return domain.Offer{}, ErrOfferTransition
+	}
+	return o, err
+}
+
+type offerNoticeData struct {
+	OfferID   uuid.UUID          `json:"offerId"`
+	ListingID uuid.UUID          `json:"listingId"`
+	Status    domain.OfferStatus `json:"status"`
+	Amount    float64            `json:"amount"`
+}
+
+// notify tells userID about what just happened to o.
+func (s *OfferService) notify(ctx context.Context, o domain.Offer, userID uuid.UUID, title, body string) {
+	if s.notifier == nil {
+		return
+	}
+	s.notifier.Notify(ctx, NewNotification{
+		UserID: userID, Kind: domain.NotifyOffer, Title: title, Body: body,
+		Data: offerNoticeData{OfferID: o.ID, ListingID: o.ListingID, Status: o.Status, Amount: o.Amount},
+	})
+}
+
+// otherParty is whichever of the offer's buyer and seller actor is not.
+func otherParty(o domain.Offer, actor uuid.UUID) uuid.UUID {
+	if actor == o.BuyerID {
+		return o.SellerID
+	}
+	return o.BuyerID
+}
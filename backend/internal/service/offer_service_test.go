@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+)
+
+// fakeOfferRepo keeps offers in memory and accepts them the way
+// OfferRepoPG does, against the listings in its fakeListingRepo.
+type fakeOfferRepo struct {
+	repository.OfferRepo
+	offers   map[uuid.UUID]domain.Offer
+	listings *fakeListingRepo
+
+	beforeAccept func() // runs between the service's checks and the write
+}
+
+func (r *fakeOfferRepo) Get(_ context.Context, id uuid.UUID) (domain.Offer, error) {
+	o, ok := r.offers[id]
+	if !ok {
+		return domain.Offer{}, domain.ErrNotFound
+	}
+	return o, nil
+}
+
+func (r *fakeOfferRepo) Accept(ctx context.Context, id uuid.UUID, from domain.OfferStatus, by uuid.UUID, reservedUntil time.Time) (domain.Offer, []domain.Offer, error) {
+	if r.beforeAccept != nil {
+		r.beforeAccept()
+	}
+	o := r.offers[id]
+	if o.Status != from {
+		return domain.Offer{}, nil, domain.ErrConflict
+	}
+	l, err := r.listings.Get(ctx, o.ListingID)
+	if err != nil {
+		return domain.Offer{}, nil, err
+	}
+	if l.Status != domain.ListingActive {
+		return domain.Offer{}, nil, domain.ErrConflict
+	}
+	if _, err := r.listings.ChangeStatus(ctx, repository.StatusChange{
+		ListingID: l.ID, From: l.Status, To: domain.ListingReserved, ChangedBy: &by, ReservedBy: &o.BuyerID, ReservedUntil: &reservedUntil,
+	}); err != nil {
+		return domain.Offer{}, nil, err
+	}
+	o.Status = domain.OfferAccepted
+	r.offers[id] = o
+	var rejected []domain.Offer
+	for oid, other := range r.offers {
+		if other.ListingID == o.ListingID && oid != id && other.Status.Active() {
+			other.Status = domain.OfferRejected
+			r.offers[oid] = other
+			rejected = append(rejected, other)
+		}
+	}
+	return o, rejected, nil
+}
+
+type fakeNotifier struct{ sent []NewNotification }
+
+func (n *fakeNotifier) Notify(_ context.Context, nn NewNotification) { n.sent = append(n.sent, nn) }
+
+func TestAcceptOffer(t *testing.T) {
+	seller := uuid.New()
+	tests := []struct {
+		name         string
+		listing      domain.ListingStatus
+		beforeAccept func(offers map[uuid.UUID]domain.Offer, accepted uuid.UUID, listings *fakeListingRepo)
+		want         error
+	}{
+		{name: "accepts", listing: domain.ListingActive},
+		{name: "listing not active", listing: domain.ListingReserved, want: ErrListingNotAvailable},
+		{
+			name:    "listing taken meanwhile",
+			listing: domain.ListingActive,
+			beforeAccept: func(_ map[uuid.UUID]domain.Offer, _ uuid.UUID, listings *fakeListingRepo) {
+				listings.results[0].Status = domain.ListingSold
+			},
+			want: ErrListingNotAvailable,
+		},
+		{
+			name:    "offer withdrawn meanwhile",
+			listing: domain.ListingActive,
+			beforeAccept: func(offers map[uuid.UUID]domain.Offer, accepted uuid.UUID, _ *fakeListingRepo) {
+				o := offers[accepted]
+				o.Status = domain.OfferWithdrawn
+				offers[accepted] = o
+			},
+			want: ErrOfferTransition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := domain.Listing{ID: uuid.New(), SellerID: seller, Title: "Desk", Price: 80, Status: tt.listing}
+			listings := &fakeListingRepo{results: []domain.Listing{l}}
+			offer := func(status domain.OfferStatus) domain.Offer {
+				return domain.Offer{ID: uuid.New(), ListingID: l.ID, BuyerID: uuid.New(), SellerID: seller, Amount: 60, Status: status}
+			}
+			mine, countered, withdrawn := offer(domain.OfferPending), offer(domain.OfferCountered), offer(domain.OfferWithdrawn)
+			offers := &fakeOfferRepo{
+				offers:   map[uuid.UUID]domain.Offer{mine.ID: mine, countered.ID: countered, withdrawn.ID: withdrawn},
+				listings: listings,
+			}
+			if tt.beforeAccept != nil {
+				offers.beforeAccept = func() { tt.beforeAccept(offers.offers, mine.ID, listings) }
+			}
+			notes := &fakeNotifier{}
+			svc := NewOfferService(offers, listings, newFakeClock(), time.Hour, notes)
+
+			accepted, err := svc.Accept(context.Background(), Actor{UserID: seller}, mine.ID)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if tt.want != nil {
+				if got := offers.offers[countered.ID].Status; got != domain.OfferCountered {
+					t.Errorf("failed accept left the other offer %s", got)
+				}
+				return
+			}
+
+			if accepted.Status != domain.OfferAccepted {
+				t.Errorf("offer is %s, want accepted", accepted.Status)
+			}
+			if got := offers.offers[countered.ID].Status; got != domain.OfferRejected {
+				t.Errorf("other open offer is %s, want rejected", got)
+			}
+			if got := offers.offers[withdrawn.ID].Status; got != domain.OfferWithdrawn {
+				t.Errorf("withdrawn offer is %s, want it left alone", got)
+			}
+			notified := map[uuid.UUID]bool{}
+			for _, n := range notes.sent {
+				notified[n.UserID] = true
+			}
+			if !notified[mine.BuyerID] || !notified[countered.BuyerID] || notified[withdrawn.BuyerID] {
+				t.Errorf("notified %v, want the accepted and rejected buyers only", notified)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/mail"
+)
+
+// resetAuthRepo stores reset tokens, or fails to with createErr.
+type resetAuthRepo struct {
+	*fakeAuthRepo
+
+	mu        sync.Mutex
+	created   []uuid.UUID
+	createErr error
+}
+
+func (r *resetAuthRepo) CreatePasswordReset(_ context.Context, userID uuid.UUID, _ string, _ time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.createErr != nil {
+		return r.createErr
+	}
+	r.created = append(r.created, userID)
+	return nil
+}
+
+// fakeMailer hands every message it is asked to send to sent, failing with
+// err if set.
+type fakeMailer struct {
+	sent chan mail.Message
+	err  error
+}
+
+func (m *fakeMailer) Send(_ context.Context, msg mail.Message) error {
+	m.sent <- msg
+	return m.err
+}
+
+type resetFixture struct {
+	auth   *AuthService
+	users  *resetAuthRepo
+	mailer *fakeMailer
+	clock  *fakeClock
+}
+
+func newResetFixture(t *testing.T) resetFixture {
+	users := &resetAuthRepo{fakeAuthRepo: newFakeAuthRepo(t, "alice@sjsu.edu")}
+	clk := newFakeClock()
+	mailer := &fakeMailer{sent: make(chan mail.Message, 64)}
+	auth := NewAuthService(users, fakeSessionRepo{}, fakeSigner{}, clk, time.Hour, 0)
+	auth.EnablePasswordReset(PasswordReset{
+		Mailer:   mailer,
+		LinkURL:  "https://campushub.test/reset?token=",
+		Requests: &fakeLoginAttempts{keys: map[string]domain.LoginAttempts{}},
+	})
+	return resetFixture{auth: auth, users: users, mailer: mailer, clock: clk}
+}
+
+// waitMail returns the next mail sent, failing if none comes.
+func (f resetFixture) waitMail(t *testing.T) mail.Message {
+	t.Helper()
+	select {
+	case m := <-f.mailer.sent:
+		return m
+	case <-time.After(2 * time.Second):
+		t.Fatal("no reset mail was sent")
+		return mail.Message{}
+	}
+}
+
+func (f resetFixture) noMail(t *testing.T) {
+	t.Helper()
+	select {
+	case m := <-f.mailer.sent:
+		t.Fatalf("mail sent to %s, want none", m.To)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestForgotPasswordDoesNotRevealAccounts(t *testing.T) {
+	tests := []struct {
+		name      string
+		email     string
+		createErr error
+		mailErr   error
+		wantMail  bool
+	}{
+		{name: "account", email: "alice@sjsu.edu", wantMail: true},
+		{name: "no account", email: "nobody@sjsu.edu"},
+		{name: "storing the token fails", email: "alice@sjsu.edu", createErr: errors.New("db down")},
+		{name: "mailing fails", email: "alice@sjsu.edu", mailErr: errors.New("smtp down"), wantMail: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newResetFixture(t)
+			f.users.createErr = tt.createErr
+			f.mailer.err = tt.mailErr
+
+			if err := f.auth.ForgotPassword(context.Background(), ForgotPasswordCmd{Email: tt.email, IP: "10.0.0.1"}); err != nil {
+				t.Fatalf("ForgotPassword: %v, want nil", err)
+			}
+			if !tt.wantMail {
+				f.noMail(t)
+				return
+			}
+			m := f.waitMail(t)
+			if m.To != tt.email || !strings.Contains(m.Body, "https://campushub.test/reset?token=") {
+				t.Errorf("mail to %s = %q, want a reset link to %s", m.To, m.Body, tt.email)
+			}
+		})
+	}
+}
+
+func TestForgotPasswordThrottle(t *testing.T) {
+	f := newResetFixture(t)
+	ctx := context.Background()
+	forgot := func(email, ip string) error {
+		return f.auth.ForgotPassword(ctx, ForgotPasswordCmd{Email: email, IP: ip})
+	}
+
+	// Known and unknown emails are limited alike.
+	for _, email := range []string{"alice@sjsu.edu", "nobody@sjsu.edu"} {
+		for i := range DefaultResetsPerEmail {
+			if err := forgot(email, fmt.Sprintf("10.0.1.%d", i)); err != nil {
+				t.Fatalf("%s request %d: %v", email, i+1, err)
+			}
+		}
+		var th *ThrottledError
+		err := forgot(strings.ToUpper(email), "10.0.2.1")
+		if !errors.As(err, &th) || !errors.Is(err, ErrTooManyResets) {
+			t.Fatalf("%s over the limit: err = %v, want a reset ThrottledError", email, err)
+		}
+		if th.RetryAfter != DefaultResetWindow {
+			t.Errorf("%s retry after = %s, want %s", email, th.RetryAfter, DefaultResetWindow)
+		}
+	}
+	for range DefaultResetsPerEmail {
+		f.waitMail(t)
+	}
+	f.noMail(t)
+
+	f.clock.Advance(DefaultResetWindow)
+	if err := forgot("alice@sjsu.edu", "10.0.3.1"); err != nil {
+		t.Fatalf("after the window: %v", err)
+	}
+	f.waitMail(t)
+
+	// One IP asking for many different emails.
+	for i := range DefaultResetsPerIP {
+		if err := forgot(fmt.Sprintf("user%d@sjsu.edu", i), "10.0.9.9"); err != nil {
+			t.Fatalf("ip request %d: %v", i+1, err)
+		}
+	}
+	if err := forgot("someone@sjsu.edu", "10.0.9.9"); !errors.Is(err, ErrTooManyResets) {
+		t.Fatalf("ip over the limit: err = %v, want ErrTooManyResets", err)
+	}
+}
@@ -8,9 +8,14 @@ import (
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
 )
 
-type ReportService struct{ repo repository.ReportRepo }
+type ReportService struct {
+	repo     repository.ReportRepo
+	notifier Notifier // nil: reporters are not told about outcomes
+}
 
-func NewReportService(r repository.ReportRepo) *ReportService { return &ReportService{repo: r} }
+func NewReportService(r repository.ReportRepo, n Notifier) *ReportService {
+	return &ReportService{repo: r, notifier: n}
+}
 
 type CreateReportCmd struct {
 	ListingID  uuid.UUID
@@ -19,6 +24,7 @@ type CreateReportCmd struct {
 }
 type ListReportsQuery struct {
 	Status        string
+	Cursor        string
 	Limit, Offset int
 }
 
@@ -26,8 +32,30 @@ func (s *ReportService) Create(ctx context.Context, cmd CreateReportCmd) (domain
 	return s.repo.Create(ctx, cmd.ListingID, cmd.ReporterID, cmd.Reason)
 }
 func (s *ReportService) List(ctx context.Context, q ListReportsQuery) ([]domain.Report, int, error) {
-	return s.repo.List(ctx, q.Status, q.Limit, q.Offset)
+	return s.repo.List(ctx, q.Status, q.Cursor, q.Limit, q.Offset)
 }
+
+// UpdateStatus sets the report's status and tells the reporter once it is
+// resolved or dismissed.
 func (s *ReportService) UpdateStatus(ctx context.Context, id uuid.UUID, status string) (domain.Report, error) {
-	return s.repo.UpdateStatus(ctx, id, status)
+	rp, err := s.repo.UpdateStatus(ctx, id, status)
+	if err != nil {
+		return domain.Report{}, err
+	}
+	if s.notifier != nil && (rp.Status == "resolved" || rp.Status == "dismissed") {
+		s.notifier.Notify(ctx, NewNotification{
+			UserID: rp.ReporterID,
+			Kind:   domain.NotifyReportStatus,
+			Title:  "Your report was " + rp.Status,
+			Body:   "Thanks for helping keep CampusHub safe.",
+			Data:   reportNoticeData{ReportID: rp.ID, ListingID: rp.ListingID, Status: rp.Status},
+		})
+	}
+	return rp, nil
+}
+
+type reportNoticeData struct {
+	ReportID  uuid.UUID `json:"reportId"`
+	ListingID uuid.UUID `json:"listingId"`
+	Status    string    `json:"status"`
 }
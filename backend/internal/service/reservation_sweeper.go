@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/clock"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+)
+
+const defaultSweepInterval = time.Minute
+
+// ReservationSweeper puts reserved listings whose reservation has lapsed back
+// to active. Running it in several processes is harmless: each expired
+// listing is released by exactly one UPDATE.
+type ReservationSweeper struct {
+	listings repository.ListingRepo
+	clock    clock.Clock
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+func NewReservationSweeper(l repository.ListingRepo, clk clock.Clock, interval time.Duration, logger *zap.Logger) *ReservationSweeper {
+	if interval <= 0 {
+		interval = defaultSweepInterval
+	}
+	return &ReservationSweeper{listings: l, clock: clk, interval: interval, logger: logger}
+}
+
+// Run sweeps once right away and then every interval until ctx is done.
+func (s *ReservationSweeper) Run(ctx context.Context) {
+	t := time.NewTicker(s.interval)
+	defer t.Stop()
+	for {
+		if _, err := s.Sweep(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Warn("reservation sweep failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Sweep releases every reservation that has expired and returns how many.
+func (s *ReservationSweeper) Sweep(ctx context.Context) (int, error) {
+	ids, err := s.listings.ExpireReservations(ctx, s.clock.Now())
+	if err != nil {
+		return 0, err
+	}
+	if len(ids) > 0 {
+		s.logger.Info("released expired reservations", zap.Int("count", len(ids)), zap.Any("listings", ids))
+	}
+	return len(ids), nil
+}
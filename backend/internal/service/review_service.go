@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+)
+
+var (
+	ErrReviewRating     = errors.New("rating must be between 1 and 5")
+	ErrReviewNotBuyer   = errors.New("only the buyer of a sold listing can review it")
+	ErrReviewExists     = errors.New("this listing has already been reviewed")
+	ErrReviewEmptyReply = errors.New("reply is empty")
+	ErrReviewReplied    = errors.New("this review already has a reply")
+)
+
+// ReviewService lets the buyer recorded on a sold listing rate its seller.
+type ReviewService struct {
+	reviews  repository.ReviewRepo
+	listings repository.ListingRepo
+}
+
+func NewReviewService(r repository.ReviewRepo, l repository.ListingRepo) *ReviewService {
+	return &ReviewService{reviews: r, listings: l}
+}
+
+type CreateReviewCmd struct {
+	BuyerID   uuid.UUID
+	ListingID uuid.UUID
+	Rating    int
+	Text      string
+}
+
+// Create records the review. The listing must be sold to cmd.BuyerID, which
+// happens through an accepted offer or by the seller naming the buyer when
+// marking it sold.
+func (s *ReviewService) Create(ctx context.Context, cmd CreateReviewCmd) (domain.Review, error) {
+	if cmd.Rating < 1 || cmd.Rating > 5 {
+		return domain.Review{}, ErrReviewRating
+	}
+	l, err := s.listings.Get(ctx, cmd.ListingID)
+	if err != nil {
+		return domain.Review{}, err
+	}
+	if l.Status != domain.ListingSold || l.ReservedBy == nil || *l.ReservedBy != cmd.BuyerID {
+		return domain.Review{}, ErrReviewNotBuyer
+	}
+	rv, err := s.reviews.Create(ctx, repository.CreateReview{
+		ListingID: l.ID,
+		SellerID:  l.SellerID,
+		BuyerID:   cmd.BuyerID,
+		Rating:    cmd.Rating,
+		Text:      strings.TrimSpace(cmd.Text),
+	})
+	if errors.Is(err, domain.ErrConflict) {
+		return domain.Review{}, ErrReviewExists
+	}
+	return rv, err
+}
+
+// Reply lets the reviewed seller answer once.
+func (s *ReviewService) Reply(ctx context.Context, actor Actor, id uuid.UUID, text string) (domain.Review, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return domain.Review{}, ErrReviewEmptyReply
+	}
+	rv, err := s.reviews.Get(ctx, id)
+	if err != nil {
+		return domain.Review{}, err
+	}
+	if rv.SellerID != actor.UserID {
+		return domain.Review{}, domain.ErrForbidden
+	}
+	out, err := s.reviews.Reply(ctx, id, text)
+	if errors.Is(err, domain.ErrConflict) {
+		return domain.Review{}, ErrReviewReplied
+	}
+	return out, err
+}
+
+func (s *ReviewService) ListForSeller(ctx context.Context, sellerID uuid.UUID, limit, offset int) ([]domain.Review, int, error) {
+	return s.reviews.ListBySeller(ctx, sellerID, limit, offset)
+}
+
+func (s *ReviewService) SellerRating(ctx context.Context, sellerID uuid.UUID) (domain.RatingSummary, error) {
+	return s.reviews.Summary(ctx, sellerID)
+}
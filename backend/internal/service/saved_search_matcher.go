@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/clock"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/pubsub"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+)
+
+const (
+	defaultMatchInterval = time.Minute
+	matchBatch           = 100
+	matchPage            = 50 // new listings read per query; a search pages through all of them
+	// matchOverlap re-reads a little before the last check so listings whose
+	// insert committed after that check are not missed; recorded matches
+	// are not reported twice.
+	matchOverlap = time.Minute
+)
+
+// SavedSearchMatcher periodically looks for listings created since each
+// saved search was last checked, records them as matches and publishes them
+// on "search.match" for the WebSocket hub.
+type SavedSearchMatcher struct {
+	searches repository.SavedSearchRepo
+	listings repository.ListingRepo
+	bus      pubsub.Broker // nil: matches are only recorded
+	clock    clock.Clock
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+func NewSavedSearchMatcher(s repository.SavedSearchRepo, l repository.ListingRepo, bus pubsub.Broker, clk clock.Clock, interval time.Duration, logger *zap.Logger) *SavedSearchMatcher {
+	if interval <= 0 {
+		interval = defaultMatchInterval
+	}
+	return &SavedSearchMatcher{searches: s, listings: l, bus: bus, clock: clk, interval: interval, logger: logger}
+}
+
+// Run matches once right away and then every interval until ctx is done.
+func (m *SavedSearchMatcher) Run(ctx context.Context) {
+	t := time.NewTicker(m.interval)
+	defer t.Stop()
+	for {
+		if _, err := m.Match(ctx); err != nil && ctx.Err() == nil {
+			m.logger.Warn("saved search matching failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Match checks every saved search not yet checked at this run's start and
+// returns how many new matches it found. A search that fails is logged and
+// stays due for the next run; the ones behind it are still checked.
+func (m *SavedSearchMatcher) Match(ctx context.Context) (int, error) {
+	now := m.clock.Now()
+	found := 0
+	var after repository.DueCursor
+	for {
+		due, err := m.searches.Due(ctx, now, after, matchBatch)
+		if err != nil {
+			return found, err
+		}
+		if len(due) == 0 {
+			return found, nil
+		}
+		for _, ss := range due {
+			n, err := m.matchOne(ctx, ss, now)
+			if err != nil {
+				if ctx.Err() != nil {
+					return found, ctx.Err()
+				}
+				m.logger.Warn("saved search match failed", zap.String("searchId", ss.ID.String()), zap.Error(err))
+				continue
+			}
+			found += n
+		}
+		last := due[len(due)-1]
+		after = repository.DueCursor{CheckedAt: last.LastCheckedAt, ID: last.ID}
+	}
+}
+
+func (m *SavedSearchMatcher) matchOne(ctx context.Context, ss domain.SavedSearch, now time.Time) (int, error) {
+	since := ss.LastCheckedAt.Add(-matchOverlap)
+	if since.Before(ss.CreatedAt) {
+		since = ss.CreatedAt // only listings that appeared after it was saved
+	}
+	p := repository.ListParams{
+		Q:            ss.Q,
+		Category:     ss.Category,
+		Condition:    ss.Condition,
+		PriceMin:     ss.PriceMin,
+		PriceMax:     ss.PriceMax,
+		Status:       string(domain.ListingActive),
+		Limit:        matchPage,
+		Sort:         repository.SortCreatedDesc,
+		CreatedAfter: &since,
+	}
+	// The last check moves to now below, so every listing since then has to
+	// be read in this run, however many there are.
+	var items []domain.Listing
+	for {
+		page, _, err := m.listings.List(ctx, p)
+		if err != nil {
+			return 0, err
+		}
+		items = append(items, page...)
+		if len(page) < matchPage {
+			break
+		}
+		p.Cursor = repository.ListingCursor(p.Sort, page[len(page)-1]).Encode()
+	}
+	byID := map[uuid.UUID]domain.Listing{}
+	var ids []uuid.UUID
+	for _, l := range items {
+		if l.SellerID == ss.UserID {
+			continue // your own listing is no news
+		}
+		byID[l.ID] = l
+		ids = append(ids, l.ID)
+	}
+	fresh, err := m.searches.RecordMatches(ctx, ss.ID, ids, now)
+	if err != nil {
+		return 0, err
+	}
+	if len(fresh) > 0 && m.bus != nil {
+		ev := pubsub.SavedSearchMatch{UserID: ss.UserID.String(), SearchID: ss.ID.String(), SearchName: ss.Name}
+		for _, id := range fresh {
+			ev.Results = append(ev.Results, listingInfo(byID[id]))
+		}
+		m.bus.Publish("search.match", ev)
+	}
+	return len(fresh), nil
+}
+
+// listingInfo is the pub/sub form of l, without images.
+func listingInfo(l domain.Listing) pubsub.ListingInfo {
+	return pubsub.ListingInfo{
+		ID:          l.ID.String(),
+		SellerID:    l.SellerID.String(),
+		Title:       l.Title,
+		Description: l.Description,
+		Category:    l.Category,
+		Price:       l.Price,
+		Condition:   string(l.Condition),
+		Status:      string(l.Status),
+		CreatedAt:   l.CreatedAt.Format(time.RFC3339Nano),
+		UpdatedAt:   l.UpdatedAt.Format(time.RFC3339Nano),
+	}
+}
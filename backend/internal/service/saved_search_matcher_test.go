@@ -0,0 +1,206 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+)
+
+// pagedListingRepo lists active listings newest first with Limit,
+// CreatedAfter and keyset Cursor, like ListingRepoPG. A Q of "broken" fails.
+type pagedListingRepo struct {
+	repository.ListingRepo
+	listings []domain.Listing // newest first
+	queries  int
+}
+
+func (r *pagedListingRepo) List(_ context.Context, p repository.ListParams) ([]domain.Listing, int, error) {
+	r.queries++
+	if p.Q == "broken" {
+		return nil, 0, errors.New("search index unavailable")
+	}
+	var after *repository.Cursor
+	if p.Cursor != "" {
+		c, err := repository.DecodeCursor(p.Cursor, p.Sort)
+		if err != nil {
+			return nil, 0, err
+		}
+		after = &c
+	}
+	var out []domain.Listing
+	for _, l := range r.listings {
+		if p.CreatedAfter != nil && !l.CreatedAt.After(*p.CreatedAfter) {
+			continue
+		}
+		if after != nil && !(l.CreatedAt.Before(*after.CreatedAt) ||
+			l.CreatedAt.Equal(*after.CreatedAt) && l.ID.String() < after.ID.String()) {
+			continue
+		}
+		if len(out) == p.Limit {
+			break
+		}
+		out = append(out, l)
+	}
+	return out, len(out), nil
+}
+
+// memSavedSearchRepo keeps searches and their matches in memory.
+type memSavedSearchRepo struct {
+	repository.SavedSearchRepo
+
+	mu       sync.Mutex
+	searches map[uuid.UUID]*domain.SavedSearch
+	matches  map[uuid.UUID]map[uuid.UUID]bool
+}
+
+func newMemSavedSearchRepo(searches ...domain.SavedSearch) *memSavedSearchRepo {
+	r := &memSavedSearchRepo{searches: map[uuid.UUID]*domain.SavedSearch{}, matches: map[uuid.UUID]map[uuid.UUID]bool{}}
+	for _, ss := range searches {
+		r.searches[ss.ID] = &ss
+	}
+	return r
+}
+
+func (r *memSavedSearchRepo) Create(_ context.Context, in repository.CreateSavedSearch) (domain.SavedSearch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, ss := range r.searches {
+		if ss.UserID == in.UserID {
+			n++
+		}
+	}
+	if in.Max > 0 && n >= in.Max {
+		return domain.SavedSearch{}, domain.ErrConflict
+	}
+	ss := domain.SavedSearch{ID: uuid.New(), UserID: in.UserID, Name: in.Name, Q: in.Q}
+	r.searches[ss.ID] = &ss
+	return ss, nil
+}
+
+func (r *memSavedSearchRepo) Due(_ context.Context, cutoff time.Time, after repository.DueCursor, limit int) ([]domain.SavedSearch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.SavedSearch
+	for _, ss := range r.searches {
+		if !ss.LastCheckedAt.Before(cutoff) {
+			continue
+		}
+		if c := ss.LastCheckedAt.Compare(after.CheckedAt); c < 0 || c == 0 && ss.ID.String() <= after.ID.String() {
+			continue
+		}
+		out = append(out, *ss)
+	}
+	slices.SortFunc(out, func(a, b domain.SavedSearch) int {
+		return cmp.Or(a.LastCheckedAt.Compare(b.LastCheckedAt), cmp.Compare(a.ID.String(), b.ID.String()))
+	})
+	return out[:min(limit, len(out))], nil
+}
+
+func (r *memSavedSearchRepo) RecordMatches(_ context.Context, searchID uuid.UUID, listingIDs []uuid.UUID, checkedAt time.Time) ([]uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.matches[searchID] == nil {
+		r.matches[searchID] = map[uuid.UUID]bool{}
+	}
+	var fresh []uuid.UUID
+	for _, id := range listingIDs {
+		if !r.matches[searchID][id] {
+			r.matches[searchID][id] = true
+			fresh = append(fresh, id)
+		}
+	}
+	r.searches[searchID].LastCheckedAt = checkedAt
+	return fresh, nil
+}
+
+// newListings returns n listings by someone else, one a minute before now,
+// newest first.
+func newListings(now time.Time, n int) []domain.Listing {
+	out := make([]domain.Listing, n)
+	for i := range out {
+		out[i] = domain.Listing{ID: uuid.New(), SellerID: uuid.New(), Title: "TI-84", Status: domain.ListingActive,
+			CreatedAt: now.Add(-time.Duration(i+1) * time.Minute)}
+	}
+	return out
+}
+
+func TestMatcherReadsEveryNewListing(t *testing.T) {
+	clk := newFakeClock()
+	now := clk.Now()
+	ss := domain.SavedSearch{ID: uuid.New(), UserID: uuid.New(), Q: "ti-84",
+		CreatedAt: now.Add(-24 * time.Hour), LastCheckedAt: now.Add(-24 * time.Hour)}
+	searches := newMemSavedSearchRepo(ss)
+	listings := &pagedListingRepo{listings: newListings(now, 2*matchPage+7)}
+	m := NewSavedSearchMatcher(searches, listings, nil, clk, time.Minute, zap.NewNop())
+
+	found, err := m.Match(context.Background())
+	if err != nil {
+		t.Fatalf("Match: %v", err)
+	}
+	if want := len(listings.listings); found != want || len(searches.matches[ss.ID]) != want {
+		t.Errorf("found %d, recorded %d, want all %d", found, len(searches.matches[ss.ID]), want)
+	}
+	if listings.queries != 3 {
+		t.Errorf("listed %d pages, want 3", listings.queries)
+	}
+}
+
+func TestMatcherSkipsFailingSearch(t *testing.T) {
+	clk := newFakeClock()
+	now := clk.Now()
+	lastWeek := now.Add(-7 * 24 * time.Hour)
+	search := func(q string, checked time.Duration) domain.SavedSearch {
+		return domain.SavedSearch{ID: uuid.New(), UserID: uuid.New(), Q: q, CreatedAt: lastWeek, LastCheckedAt: now.Add(-checked)}
+	}
+	// The failing search is due first.
+	broken, first, second := search("broken", 3*time.Hour), search("ti-84", 2*time.Hour), search("ti-84", time.Hour)
+	searches := newMemSavedSearchRepo(broken, first, second)
+	listings := &pagedListingRepo{listings: newListings(now, 3)}
+	m := NewSavedSearchMatcher(searches, listings, nil, clk, time.Minute, zap.NewNop())
+
+	found, err := m.Match(context.Background())
+	if err != nil {
+		t.Fatalf("Match: %v", err)
+	}
+	if found != 6 {
+		t.Errorf("found %d matches, want 3 for each working search", found)
+	}
+	for _, ss := range []domain.SavedSearch{first, second} {
+		if got := searches.searches[ss.ID].LastCheckedAt; !got.Equal(now) {
+			t.Errorf("search checked at %v, want %v", got, now)
+		}
+	}
+	if got := searches.searches[broken.ID].LastCheckedAt; !got.Equal(broken.LastCheckedAt) {
+		t.Errorf("failing search checked at %v, want it still due from %v", got, broken.LastCheckedAt)
+	}
+}
+
+func TestSavedSearchLimit(t *testing.T) {
+	repo := newMemSavedSearchRepo()
+	s := NewSavedSearchService(repo)
+	user := uuid.New()
+	ctx := context.Background()
+
+	for i := range MaxSavedSearches {
+		if _, err := s.Create(ctx, SaveSearchCmd{UserID: user, Q: "ti-84"}); err != nil {
+			t.Fatalf("search %d: %v", i+1, err)
+		}
+	}
+	if _, err := s.Create(ctx, SaveSearchCmd{UserID: user, Q: "ti-84"}); !errors.Is(err, ErrSavedSearchLimit) {
+		t.Errorf("over the limit: err = %v, want ErrSavedSearchLimit", err)
+	}
+	if _, err := s.Create(ctx, SaveSearchCmd{UserID: uuid.New(), Q: "ti-84"}); err != nil {
+		t.Errorf("another user: %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+)
+
+var (
+	ErrSavedSearchEmpty = errors.New("saved search needs at least one of q, category, condition or a price bound")
+	ErrSavedSearchPrice = errors.New("priceMin must not be greater than priceMax")
+	ErrSavedSearchLimit = errors.New("too many saved searches")
+	ErrSavedSearchName  = errors.New("name must be at most 100 characters")
+)
+
+// MaxSavedSearches is how many saved searches one user may keep.
+const MaxSavedSearches = 20
+
+// SavedSearchService stores the listing queries users want alerts for; the
+// SavedSearchMatcher does the matching.
+type SavedSearchService struct {
+	repo repository.SavedSearchRepo
+}
+
+func NewSavedSearchService(r repository.SavedSearchRepo) *SavedSearchService {
+	return &SavedSearchService{repo: r}
+}
+
+type SaveSearchCmd struct {
+	UserID    uuid.UUID
+	Name      string // defaults to a summary of the query
+	Q         string
+	Category  string
+	Condition string
+	PriceMin  *float64
+	PriceMax  *float64
+}
+
+func (s *SavedSearchService) Create(ctx context.Context, cmd SaveSearchCmd) (domain.SavedSearch, error) {
+	in := repository.CreateSavedSearch{
+		UserID:    cmd.UserID,
+		Name:      strings.TrimSpace(cmd.Name),
+		Q:         strings.TrimSpace(cmd.Q),
+		Category:  strings.TrimSpace(cmd.Category),
+		Condition: strings.TrimSpace(cmd.Condition),
+		PriceMin:  cmd.PriceMin,
+		PriceMax:  cmd.PriceMax,
+	}
+	if in.Q == "" && in.Category == "" && in.Condition == "" && in.PriceMin == nil && in.PriceMax == nil {
+		return domain.SavedSearch{}, ErrSavedSearchEmpty
+	}
+	if in.PriceMin != nil && in.PriceMax != nil && *in.PriceMin > *in.PriceMax {
+		return domain.SavedSearch{}, ErrSavedSearchPrice
+	}
+	if utf8.RuneCountInString(in.Name) > 100 {
+		return domain.SavedSearch{}, ErrSavedSearchName
+	}
+	if in.Name == "" {
+		in.Name = savedSearchName(in)
+	}
+	in.Max = MaxSavedSearches
+	ss, err := s.repo.Create(ctx, in)
+	if errors.Is(err, domain.ErrConflict) {
+		return domain.SavedSearch{}, ErrSavedSearchLimit
+	}
+	return ss, err
+}
+
+func (s *SavedSearchService) List(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.SavedSearch, int, error) {
+	return s.repo.ListByUser(ctx, userID, limit, offset)
+}
+
+// Delete removes one of the user's saved searches with its matches.
+func (s *SavedSearchService) Delete(ctx context.Context, actor Actor, id uuid.UUID) error {
+	ss, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if ss.UserID != actor.UserID && !actor.IsAdmin() {
+		return domain.ErrForbidden
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Matches is the user's in-app list of listings their saved searches found.
+func (s *SavedSearchService) Matches(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.SavedSearchMatch, int, error) {
+	return s.repo.Matches(ctx, userID, limit, offset)
+}
+
+// savedSearchName summarizes the query, e.g. `"calculus" in Textbooks`.
+func savedSearchName(in repository.CreateSavedSearch) string {
+	var parts []string
+	if in.Q != "" {
+		parts = append(parts, `"`+in.Q+`"`)
+	}
+	if in.Category != "" {
+		parts = append(parts, "in "+in.Category)
+	}
+	if in.Condition != "" {
+		parts = append(parts, "("+in.Condition+")")
+	}
+	if len(parts) == 0 {
+		return "Listings in my price range"
+	}
+	name := strings.Join(parts, " ")
+	if utf8.RuneCountInString(name) > 100 {
+		name = string([]rune(name)[:100])
+	}
+	return name
+}
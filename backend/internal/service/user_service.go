@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+)
+
+var (
+	ErrProfileName   = errors.New("name must be 1 to 100 characters")
+	ErrProfileBio    = errors.New("bio must be at most 500 characters")
+	ErrAvatarKey     = errors.New("avatar must be uploaded through the avatar presign endpoint")
+	ErrAvatarMissing = errors.New("avatar has not been uploaded")
+	ErrAvatarStorage = errors.New("avatar storage is not configured")
+)
+
+// AvatarStore tells whether an uploaded object exists.
+type AvatarStore interface {
+	HeadObjectExists(ctx context.Context, key string) (bool, error)
+}
+
+const (
+	maxNameLen = 100
+	maxBioLen  = 500
+)
+
+// UserService serves what other users may see about someone and lets users
+// edit their own profile.
+type UserService struct {
+	users    repository.AuthRepo
+	reviews  repository.ReviewRepo
+	listings repository.ListingRepo
+	avatars  AvatarStore // nil: avatars can only be removed
+}
+
+func NewUserService(u repository.AuthRepo, r repository.ReviewRepo, l repository.ListingRepo) *UserService {
+	return &UserService{users: u, reviews: r, listings: l}
+}
+
+// AvatarPrefix is where a user's avatar uploads go; PATCH /users/me only
+// accepts keys under it.
+func AvatarPrefix(userID uuid.UUID) string {
+	return "avatars/" + userID.String() + "/"
+}
+
+// EnableAvatars lets users set avatars uploaded to store.
+func (s *UserService) EnableAvatars(store AvatarStore) {
+	s.avatars = store
+}
+
+// Profile is the public view of a user with their listing counts and seller
+// rating. The email is only shown to admins and to the user themselves;
+// viewer is the zero Actor for anonymous callers.
+func (s *UserService) Profile(ctx context.Context, viewer Actor, id uuid.UUID) (domain.PublicProfile, error) {
+	u, err := s.users.GetByID(ctx, id)
+	if err != nil {
+		return domain.PublicProfile{}, err
+	}
+	return s.profile(ctx, viewer, u)
+}
+
+type UpdateProfileCmd struct {
+	Name      *string
+	Bio       *string
+	AvatarKey *string // "" removes the avatar
+}
+
+// UpdateMe edits the caller's display name, bio and avatar. A new avatar key
+// must be under the caller's AvatarPrefix, checked before storage is asked
+// about it, and already uploaded.
+func (s *UserService) UpdateMe(ctx context.Context, actor Actor, cmd UpdateProfileCmd) (domain.PublicProfile, error) {
+	var p repository.UpdateProfile
+	if cmd.Name != nil {
+		name := strings.TrimSpace(*cmd.Name)
+		if name == "" || utf8.RuneCountInString(name) > maxNameLen {
+			return domain.PublicProfile{}, ErrProfileName
+		}
+		p.Name = &name
+	}
+	if cmd.Bio != nil {
+		bio := strings.TrimSpace(*cmd.Bio)
+		if utf8.RuneCountInString(bio) > maxBioLen {
+			return domain.PublicProfile{}, ErrProfileBio
+		}
+		p.Bio = &bio
+	}
+	if cmd.AvatarKey != nil {
+		key := *cmd.AvatarKey
+		if key != "" {
+			if err := s.checkAvatar(ctx, actor, key); err != nil {
+				return domain.PublicProfile{}, err
+			}
+		}
+		p.AvatarKey = &key
+	}
+	u, err := s.users.UpdateProfile(ctx, actor.UserID, p)
+	if err != nil {
+		return domain.PublicProfile{}, err
+	}
+	return s.profile(ctx, actor, u)
+}
+
+func (s *UserService) checkAvatar(ctx context.Context, actor Actor, key string) error {
+	if !strings.HasPrefix(key, AvatarPrefix(actor.UserID)) {
+		return ErrAvatarKey
+	}
+	if s.avatars == nil {
+		return ErrAvatarStorage
+	}
+	ok, err := s.avatars.HeadObjectExists(ctx, key)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAvatarMissing
+	}
+	return nil
+}
+
+func (s *UserService) profile(ctx context.Context, viewer Actor, u domain.User) (domain.PublicProfile, error) {
+	rating, err := s.reviews.Summary(ctx, u.ID)
+	if err != nil {
+		return domain.PublicProfile{}, err
+	}
+	counts, err := s.listings.CountBySeller(ctx, u.ID)
+	if err != nil {
+		return domain.PublicProfile{}, err
+	}
+	p := domain.PublicProfile{
+		ID:             u.ID,
+		Name:           u.Name,
+		Bio:            u.Bio,
+		AvatarKey:      u.AvatarKey,
+		MemberSince:    u.CreatedAt,
+		ActiveListings: counts[domain.ListingActive],
+		SoldCount:      counts[domain.ListingSold],
+		Rating:         rating,
+	}
+	if viewer.IsAdmin() || viewer.UserID == u.ID {
+		p.Email = u.Email
+	}
+	return p, nil
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// fakeAvatarStore holds the uploaded keys and records every key asked about.
+type fakeAvatarStore struct {
+	uploaded map[string]bool
+	asked    []string
+}
+
+func (s *fakeAvatarStore) HeadObjectExists(_ context.Context, key string) (bool, error) {
+	s.asked = append(s.asked, key)
+	return s.uploaded[key], nil
+}
+
+func TestUpdateMeAvatarChecks(t *testing.T) {
+	actor := Actor{UserID: uuid.New(), Role: "user"}
+	own := AvatarPrefix(actor.UserID) + "missing.jpg"
+	foreign := AvatarPrefix(uuid.New()) + "a.jpg"
+
+	tests := []struct {
+		name      string
+		key       string
+		noStorage bool
+		wantErr   error
+		wantAsked bool
+	}{
+		{name: "another user's avatar", key: foreign, wantErr: ErrAvatarKey},
+		{name: "another user's avatar without storage", key: foreign, noStorage: true, wantErr: ErrAvatarKey},
+		{name: "outside the avatar prefix", key: "listings/a.jpg", wantErr: ErrAvatarKey},
+		{name: "prefix without a file", key: "avatars/" + actor.UserID.String(), wantErr: ErrAvatarKey},
+		{name: "not uploaded yet", key: own, wantErr: ErrAvatarMissing, wantAsked: true},
+		{name: "no storage", key: own, noStorage: true, wantErr: ErrAvatarStorage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Every case fails before any repo is used.
+			s := NewUserService(nil, nil, nil)
+			store := &fakeAvatarStore{uploaded: map[string]bool{foreign: true}}
+			if !tt.noStorage {
+				s.EnableAvatars(store)
+			}
+
+			_, err := s.UpdateMe(context.Background(), actor, UpdateProfileCmd{AvatarKey: &tt.key})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+			if asked := len(store.asked) > 0; asked != tt.wantAsked {
+				t.Errorf("storage asked about %v, want asked = %v", store.asked, tt.wantAsked)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/service"
+)
+
+var errNoActor = errors.New("missing or invalid user id in context")
+
+// actorFrom reads the caller set by middleware.JWT.
+func actorFrom(c *gin.Context) (service.Actor, error) {
+	idStr := c.GetString("userId")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return service.Actor{}, errNoActor
+	}
+	return service.Actor{UserID: id, Role: c.GetString("role")}, nil
+}
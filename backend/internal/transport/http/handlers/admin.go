@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/resp"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/service"
 )
@@ -25,12 +28,20 @@ func (h *AdminHandler) Metrics(c *gin.Context) {
 func (h *AdminHandler) Users(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	items, total, err := h.s.Users(c.Request.Context(), limit, offset)
+	items, total, err := h.s.Users(c.Request.Context(), c.Query("cursor"), limit, offset)
+	if errors.Is(err, repository.ErrBadCursor) {
+		c.JSON(400, resp.Err("BAD_REQUEST", "invalid cursor", nil))
+		return
+	}
 	if err != nil {
 		c.JSON(500, resp.Err("INTERNAL", "list users failed", err.Error()))
 		return
 	}
-	c.JSON(200, resp.Data(gin.H{"items": items, "total": total, "limit": limit, "offset": offset}))
+	var next string
+	if n := len(items); n > 0 {
+		next = repository.NextCursor(n, limit, repository.CreatedCursor(items[n-1].CreatedAt, items[n-1].ID))
+	}
+	c.JSON(200, resp.Data(gin.H{"items": items, "total": total, "limit": limit, "offset": offset, "nextCursor": next}))
 }
 func (h *AdminHandler) ForceRemoveListing(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
@@ -38,9 +49,60 @@ func (h *AdminHandler) ForceRemoveListing(c *gin.Context) {
 		c.JSON(400, resp.Err("BAD_REQUEST", "bad id", nil))
 		return
 	}
-	if err := h.s.ForceRemoveListing(c.Request.Context(), id); err != nil {
-		c.JSON(500, resp.Err("INTERNAL", "remove failed", err.Error()))
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(401, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	if err := h.s.ForceRemoveListing(c.Request.Context(), actor, id); err != nil {
+		writeListingErr(c, err, "remove failed")
 		return
 	}
 	c.JSON(200, resp.Data(gin.H{"ok": true}))
 }
+
+// UnlockUser lifts a sign-in lockout on a user's account.
+func (h *AdminHandler) UnlockUser(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(400, resp.Err("BAD_REQUEST", "bad id", nil))
+		return
+	}
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(401, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	err = h.s.UnlockUser(c.Request.Context(), actor, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		c.JSON(404, resp.Err("NOT_FOUND", "user not found", nil))
+		return
+	}
+	if err != nil {
+		c.JSON(500, resp.Err("INTERNAL", "unlock failed", err.Error()))
+		return
+	}
+	c.JSON(200, resp.Data(gin.H{"unlocked": true}))
+}
+
+// Audit lists audit log entries, newest first.
+func (h *AdminHandler) Audit(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	items, err := h.s.Audit(c.Request.Context(), c.Query("cursor"), limit)
+	if errors.Is(err, repository.ErrBadCursor) {
+		c.JSON(400, resp.Err("BAD_REQUEST", "invalid cursor", nil))
+		return
+	}
+	if err != nil {
+		c.JSON(500, resp.Err("INTERNAL", "list audit log failed", err.Error()))
+		return
+	}
+	var next string
+	if n := len(items); n > 0 {
+		next = repository.NextCursor(n, limit, repository.CreatedCursor(items[n-1].CreatedAt, items[n-1].ID))
+	}
+	c.JSON(200, resp.Data(gin.H{"items": items, "nextCursor": next}))
+}
@@ -1,9 +1,16 @@
 package handlers
 
 import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/resp"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/service"
 )
@@ -20,7 +27,7 @@ func NewAuthHandler(s *service.AuthService, v *validator.Validate) *AuthHandler
 type signUpReq struct {
 	Name     string `json:"name" validate:"required,min=2,max=80"`
 	Email    string `json:"email" validate:"required,email"`
-	Role     string `json:"role" validate:"required,oneof=buyer seller admin"`
+	Role     string `json:"role" validate:"required,oneof=buyer seller"`
 	Password string `json:"password" validate:"required,min=8,max=72"`
 }
 
@@ -34,14 +41,172 @@ func (h *AuthHandler) SignUp(c *gin.Context) {
 		c.JSON(400, resp.Err("VALIDATION_ERROR", "invalid fields", err.Error()))
 		return
 	}
-	u, err := h.s.SignUp(c.Request.Context(), service.SignUpCmd{
+	out, err := h.s.SignUp(c.Request.Context(), service.SignUpCmd{
 		Name: req.Name, Email: req.Email, Role: req.Role, Password: req.Password,
 	})
+	if errors.Is(err, service.ErrEmailDomain) || errors.Is(err, service.ErrRoleNotAllowed) {
+		c.JSON(400, resp.Err("VALIDATION_ERROR", err.Error(), nil))
+		return
+	}
 	if err != nil {
 		c.JSON(409, resp.Err("CONFLICT", "email exists?", err.Error()))
 		return
 	}
-	c.JSON(201, resp.Data(u))
+	// The user's fields stay at the top level, as before verification existed.
+	c.JSON(201, resp.Data(struct {
+		domain.User
+		VerificationSent bool `json:"verificationSent"`
+	}{out.User, out.VerificationSent}))
+}
+
+type verifyReq struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// Verify confirms the email address from the link mailed at sign-up.
+func (h *AuthHandler) Verify(c *gin.Context) {
+	var req verifyReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, resp.Err("VALIDATION_ERROR", "invalid json", err.Error()))
+		return
+	}
+	if err := h.v.Struct(req); err != nil {
+		c.JSON(400, resp.Err("VALIDATION_ERROR", "invalid fields", err.Error()))
+		return
+	}
+	u, err := h.s.Verify(c.Request.Context(), req.Token)
+	if errors.Is(err, service.ErrBadVerification) {
+		c.JSON(400, resp.Err("BAD_REQUEST", err.Error(), nil))
+		return
+	}
+	if err != nil {
+		c.JSON(500, resp.Err("INTERNAL", "verify failed", err.Error()))
+		return
+	}
+	c.JSON(200, resp.Data(u))
+}
+
+// ResendVerification mails the caller a new verification link.
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(401, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	err = h.s.ResendVerification(c.Request.Context(), actor.UserID)
+	if errors.Is(err, service.ErrAlreadyVerified) {
+		c.JSON(409, resp.Err("CONFLICT", err.Error(), nil))
+		return
+	}
+	if err != nil {
+		c.JSON(500, resp.Err("INTERNAL", "resend failed", err.Error()))
+		return
+	}
+	c.JSON(200, resp.Data(gin.H{"sent": true}))
+}
+
+type forgotPasswordReq struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ForgotPassword mails a reset link. It answers the same whether or not the
+// email has an account, and 429 once the email or IP asked too often.
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req forgotPasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, resp.Err("VALIDATION_ERROR", "invalid json", err.Error()))
+		return
+	}
+	if err := h.v.Struct(req); err != nil {
+		c.JSON(400, resp.Err("VALIDATION_ERROR", "invalid fields", err.Error()))
+		return
+	}
+	err := h.s.ForgotPassword(c.Request.Context(), service.ForgotPasswordCmd{Email: req.Email, IP: c.ClientIP()})
+	var throttled *service.ThrottledError
+	if errors.As(err, &throttled) {
+		retry := int(math.Ceil(throttled.RetryAfter.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(max(retry, 1)))
+		c.JSON(429, resp.Err("TOO_MANY_REQUESTS", throttled.Error(), gin.H{"retryAt": throttled.RetryAt}))
+		return
+	}
+	if errors.Is(err, service.ErrResetDisabled) {
+		c.JSON(503, resp.Err("UNAVAILABLE", err.Error(), nil))
+		return
+	}
+	if err != nil {
+		c.JSON(500, resp.Err("INTERNAL", "forgot password failed", err.Error()))
+		return
+	}
+	c.JSON(200, resp.Data(gin.H{"requested": true}))
+}
+
+type resetPasswordReq struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+// ResetPassword sets a new password from a mailed reset token.
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req resetPasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, resp.Err("VALIDATION_ERROR", "invalid json", err.Error()))
+		return
+	}
+	if err := h.v.Struct(req); err != nil {
+		c.JSON(400, resp.Err("VALIDATION_ERROR", "invalid fields", err.Error()))
+		return
+	}
+	err := h.s.ResetPassword(c.Request.Context(), req.Token, req.Password)
+	if errors.Is(err, service.ErrBadResetToken) {
+		c.JSON(400, resp.Err("BAD_REQUEST", err.Error(), nil))
+		return
+	}
+	if err != nil {
+		c.JSON(500, resp.Err("INTERNAL", "reset password failed", err.Error()))
+		return
+	}
+	c.JSON(200, resp.Data(gin.H{"reset": true}))
+}
+
+type changePasswordReq struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	NewPassword     string `json:"newPassword" validate:"required,min=8,max=72"`
+}
+
+// ChangePassword replaces the caller's password; their other sessions are
+// signed out.
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(401, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	var req changePasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, resp.Err("VALIDATION_ERROR", "invalid json", err.Error()))
+		return
+	}
+	if err := h.v.Struct(req); err != nil {
+		c.JSON(400, resp.Err("VALIDATION_ERROR", "invalid fields", err.Error()))
+		return
+	}
+	sid, _ := uuid.Parse(c.GetString("sessionId"))
+	err = h.s.ChangePassword(c.Request.Context(), service.ChangePasswordCmd{
+		UserID: actor.UserID, SessionID: sid, Current: req.CurrentPassword, New: req.NewPassword,
+	})
+	if errors.Is(err, service.ErrWrongPassword) {
+		c.JSON(403, resp.Err("FORBIDDEN", err.Error(), nil))
+		return
+	}
+	if errors.Is(err, domain.ErrNotFound) {
+		c.JSON(404, resp.Err("NOT_FOUND", "user not found", nil))
+		return
+	}
+	if err != nil {
+		c.JSON(500, resp.Err("INTERNAL", "change password failed", err.Error()))
+		return
+	}
+	c.JSON(200, resp.Data(gin.H{"changed": true}))
 }
 
 type signInReq struct {
@@ -59,10 +224,119 @@ func (h *AuthHandler) SignIn(c *gin.Context) {
 		c.JSON(400, resp.Err("VALIDATION_ERROR", "invalid fields", err.Error()))
 		return
 	}
-	out, err := h.s.SignIn(c.Request.Context(), service.SignInCmd{Email: req.Email, Password: req.Password})
-	if err != nil {
+	out, err := h.s.SignIn(c.Request.Context(), service.SignInCmd{
+		Email: req.Email, Password: req.Password, UserAgent: c.Request.UserAgent(), IP: c.ClientIP(),
+	})
+	var throttled *service.ThrottledError
+	if errors.As(err, &throttled) {
+		code := "TOO_MANY_ATTEMPTS"
+		if throttled.Locked {
+			code = "ACCOUNT_LOCKED"
+		}
+		retry := int(math.Ceil(throttled.RetryAfter.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(max(retry, 1)))
+		c.JSON(429, resp.Err(code, throttled.Error(), gin.H{"retryAt": throttled.RetryAt}))
+		return
+	}
+	if errors.Is(err, service.ErrUnauthorized) {
 		c.JSON(401, resp.Err("UNAUTHORIZED", "bad credentials", nil))
 		return
 	}
-	c.JSON(200, resp.Data(gin.H{"token": out.Token, "user": out.User}))
+	if err != nil {
+		c.JSON(500, resp.Err("INTERNAL", "sign in failed", err.Error()))
+		return
+	}
+	c.JSON(200, resp.Data(tokenPair(out)))
+}
+
+type refreshReq struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+// Refresh trades a refresh token for a new access and refresh token.
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req refreshReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, resp.Err("VALIDATION_ERROR", "invalid json", err.Error()))
+		return
+	}
+	if err := h.v.Struct(req); err != nil {
+		c.JSON(400, resp.Err("VALIDATION_ERROR", "invalid fields", err.Error()))
+		return
+	}
+	out, err := h.s.Refresh(c.Request.Context(), service.RefreshCmd{
+		RefreshToken: req.RefreshToken, UserAgent: c.Request.UserAgent(), IP: c.ClientIP(),
+	})
+	if errors.Is(err, service.ErrUnauthorized) {
+		c.JSON(401, resp.Err("UNAUTHORIZED", "invalid or expired refresh token", nil))
+		return
+	}
+	if err != nil {
+		c.JSON(500, resp.Err("INTERNAL", "refresh failed", err.Error()))
+		return
+	}
+	c.JSON(200, resp.Data(tokenPair(out)))
+}
+
+func tokenPair(out service.SignInResult) gin.H {
+	return gin.H{"token": out.Token, "expiresAt": out.ExpiresAt, "refreshToken": out.RefreshToken, "user": out.User}
+}
+
+// Logout revokes the bearer token and ends its session.
+func (h *AuthHandler) Logout(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(401, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	sid, _ := uuid.Parse(c.GetString("sessionId"))
+	exp, _ := c.Get("tokenExp")
+	expAt, _ := exp.(time.Time)
+	if err := h.s.Logout(c.Request.Context(), service.TokenRef{
+		UserID: actor.UserID, SessionID: sid, JTI: c.GetString("jti"), ExpiresAt: expAt,
+	}); err != nil {
+		c.JSON(500, resp.Err("INTERNAL", "logout failed", err.Error()))
+		return
+	}
+	c.JSON(200, resp.Data(gin.H{"loggedOut": true}))
+}
+
+// Sessions lists the caller's signed-in devices.
+func (h *AuthHandler) Sessions(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(401, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	current, _ := uuid.Parse(c.GetString("sessionId"))
+	items, err := h.s.Sessions(c.Request.Context(), actor.UserID, current)
+	if err != nil {
+		c.JSON(500, resp.Err("INTERNAL", "list sessions failed", err.Error()))
+		return
+	}
+	c.JSON(200, resp.Data(gin.H{"items": items}))
+}
+
+// RevokeSession signs one of the caller's devices out.
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(401, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(400, resp.Err("BAD_REQUEST", "bad id", nil))
+		return
+	}
+	err = h.s.RevokeSession(c.Request.Context(), actor.UserID, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		c.JSON(404, resp.Err("NOT_FOUND", "session not found", nil))
+		return
+	}
+	if err != nil {
+		c.JSON(500, resp.Err("INTERNAL", "revoke session failed", err.Error()))
+		return
+	}
+	c.JSON(200, resp.Data(gin.H{"revoked": true}))
 }
@@ -1 +1,172 @@
 package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/resp"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/service"
+)
+
+type ChatHandler struct {
+	s *service.ChatService
+	v *validator.Validate
+}
+
+func NewChatHandler(s *service.ChatService, v *validator.Validate) *ChatHandler {
+	return &ChatHandler{s: s, v: v}
+}
+
+type startConversationReq struct {
+	ListingID uuid.UUID `json:"listingId" validate:"required"`
+}
+
+func (h *ChatHandler) Start(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	var req startConversationReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("VALIDATION_ERROR", "invalid json", err.Error()))
+		return
+	}
+	if err := h.v.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("VALIDATION_ERROR", "invalid fields", err.Error()))
+		return
+	}
+	conv, err := h.s.StartConversation(c.Request.Context(), actor.UserID, req.ListingID)
+	if err != nil {
+		writeChatErr(c, err, "start conversation failed")
+		return
+	}
+	c.JSON(http.StatusOK, resp.Data(conv))
+}
+
+func (h *ChatHandler) List(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	limit, offset := pageParams(c)
+	items, total, err := h.s.ListConversations(c.Request.Context(), actor.UserID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, resp.Err("INTERNAL", "list conversations failed", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, resp.Data(gin.H{"items": items, "total": total, "limit": limit, "offset": offset}))
+}
+
+// Messages pages history newest first; pass the oldest createdAt seen as
+// ?before= to fetch the previous page.
+func (h *ChatHandler) Messages(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", "bad id", nil))
+		return
+	}
+	var before time.Time
+	if s := c.Query("before"); s != "" {
+		if before, err = time.Parse(time.RFC3339Nano, s); err != nil {
+			c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", "before must be RFC3339", nil))
+			return
+		}
+	}
+	limit := 50
+	if s := c.Query("limit"); s != "" {
+		if v, err := strconv.Atoi(s); err == nil && v > 0 && v <= 200 {
+			limit = v
+		}
+	}
+
+	items, err := h.s.ListMessages(c.Request.Context(), actor.UserID, id, before, limit)
+	if err != nil {
+		writeChatErr(c, err, "list messages failed")
+		return
+	}
+	out := gin.H{"items": items, "limit": limit}
+	if len(items) == limit {
+		out["nextBefore"] = items[len(items)-1].CreatedAt
+	}
+	c.JSON(http.StatusOK, resp.Data(out))
+}
+
+// Unread returns per-conversation unread counts plus their total, for the
+// inbox badge.
+func (h *ChatHandler) Unread(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	items, err := h.s.UnreadCounts(c.Request.Context(), actor.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, resp.Err("INTERNAL", "unread counts failed", err.Error()))
+		return
+	}
+	total := 0
+	for _, u := range items {
+		total += u.Unread
+	}
+	c.JSON(http.StatusOK, resp.Data(gin.H{"items": items, "total": total}))
+}
+
+type markReadReq struct {
+	ReadAt *time.Time `json:"readAt"`
+}
+
+func (h *ChatHandler) MarkRead(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", "bad id", nil))
+		return
+	}
+	var req markReadReq
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, resp.Err("VALIDATION_ERROR", "invalid json", err.Error()))
+			return
+		}
+	}
+	var at time.Time
+	if req.ReadAt != nil {
+		at = *req.ReadAt
+	}
+	if err := h.s.MarkRead(c.Request.Context(), actor.UserID, id, at); err != nil {
+		writeChatErr(c, err, "mark read failed")
+		return
+	}
+	c.JSON(http.StatusOK, resp.Data(gin.H{"ok": true}))
+}
+
+func writeChatErr(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusNotFound, resp.Err("NOT_FOUND", "not found", nil))
+	case errors.Is(err, domain.ErrForbidden):
+		c.JSON(http.StatusForbidden, resp.Err("FORBIDDEN", "not a participant", nil))
+	case errors.Is(err, service.ErrChatWithSelf):
+		c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", err.Error(), nil))
+	default:
+		c.JSON(http.StatusInternalServerError, resp.Err("INTERNAL", msg, err.Error()))
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/resp"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/service"
+)
+
+type FavoritesHandler struct{ s *service.FavoriteService }
+
+func NewFavoritesHandler(s *service.FavoriteService) *FavoritesHandler {
+	return &FavoritesHandler{s: s}
+}
+
+// Add favorites the listing in the path; favoriting twice is fine.
+func (h *FavoritesHandler) Add(c *gin.Context) {
+	actor, id, ok := h.target(c)
+	if !ok {
+		return
+	}
+	if err := h.s.Add(c.Request.Context(), actor.UserID, id); err != nil {
+		writeFavoriteErr(c, err, "favorite failed")
+		return
+	}
+	c.JSON(http.StatusOK, resp.Data(gin.H{"listingId": id, "favorited": true}))
+}
+
+func (h *FavoritesHandler) Remove(c *gin.Context) {
+	actor, id, ok := h.target(c)
+	if !ok {
+		return
+	}
+	if err := h.s.Remove(c.Request.Context(), actor.UserID, id); err != nil {
+		writeFavoriteErr(c, err, "unfavorite failed")
+		return
+	}
+	c.JSON(http.StatusOK, resp.Data(gin.H{"listingId": id, "favorited": false}))
+}
+
+// List returns the caller's favorited listings, most recently saved first.
+func (h *FavoritesHandler) List(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	limit, offset := pageParams(c)
+	items, total, err := h.s.List(c.Request.Context(), actor.UserID, limit, offset)
+	if err != nil {
+		writeFavoriteErr(c, err, "list favorites failed")
+		return
+	}
+	c.JSON(http.StatusOK, resp.Data(gin.H{"items": items, "total": total, "limit": limit, "offset": offset}))
+}
+
+func (h *FavoritesHandler) target(c *gin.Context) (service.Actor, uuid.UUID, bool) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return service.Actor{}, uuid.Nil, false
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", "bad id", nil))
+		return service.Actor{}, uuid.Nil, false
+	}
+	return actor, id, true
+}
+
+func writeFavoriteErr(c *gin.Context, err error, msg string) {
+	if errors.Is(err, domain.ErrNotFound) {
+		c.JSON(http.StatusNotFound, resp.Err("NOT_FOUND", "listing not found", nil))
+		return
+	}
+	c.JSON(http.StatusInternalServerError, resp.Err("INTERNAL", msg, err.Error()))
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -14,14 +15,18 @@ import (
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/s3client"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
 	resp "github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/resp"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/service"
 )
 
 type ListingsHandler struct {
-	repo   repository.ListingRepo
-	images repository.ImageRepo
-	s3     *s3client.Client
-	v      *validator.Validate
-	expiry time.Duration
+	repo      repository.ListingRepo
+	svc       *service.ListingService
+	reviews   *service.ReviewService
+	favorites *service.FavoriteService
+	images    repository.ImageRepo
+	s3        *s3client.Client
+	v         *validator.Validate
+	expiry    time.Duration
 }
 
 type listingWithImage struct {
@@ -30,10 +35,20 @@ type listingWithImage struct {
 		Key string `json:"key"`
 		URL string `json:"url"`
 	} `json:"images"`
+
+	// Only on GET /listings/:id.
+	SellerRating *domain.RatingSummary `json:"sellerRating,omitempty"`
+
+	// On GET /listings and /listings/:id; favorited only for signed-in callers.
+	FavoriteCount int   `json:"favoriteCount"`
+	Favorited     *bool `json:"favorited,omitempty"`
 }
 
 func NewListingsHandler(
 	repo repository.ListingRepo,
+	svc *service.ListingService,
+	reviews *service.ReviewService,
+	favorites *service.FavoriteService,
 	images repository.ImageRepo,
 	s3 *s3client.Client,
 	v *validator.Validate,
@@ -43,16 +58,19 @@ func NewListingsHandler(
 		expiryMinutes = 15
 	}
 	return &ListingsHandler{
-		repo:   repo,
-		images: images,
-		s3:     s3,
-		v:      v,
-		expiry: time.Duration(expiryMinutes) * time.Minute,
+		repo:      repo,
+		svc:       svc,
+		reviews:   reviews,
+		favorites: favorites,
+		images:    images,
+		s3:        s3,
+		v:         v,
+		expiry:    time.Duration(expiryMinutes) * time.Minute,
 	}
 }
 
 type createListingReq struct {
-	SellerID    uuid.UUID        `json:"sellerId" validate:"required"`
+	SellerID    uuid.UUID        `json:"sellerId"` // optional; must be the caller when sent
 	Title       string           `json:"title" validate:"required,min=3,max=120"`
 	Description string           `json:"description" validate:"required,min=5"`
 	Category    string           `json:"category" validate:"required,min=2,max=60"`
@@ -70,8 +88,18 @@ func (h *ListingsHandler) Create(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, resp.Err("VALIDATION_ERROR", "invalid fields", err.Error()))
 		return
 	}
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	// Listings always belong to the caller, whose email VerifiedEmail checked.
+	if req.SellerID != uuid.Nil && req.SellerID != actor.UserID {
+		c.JSON(http.StatusForbidden, resp.Err("FORBIDDEN", "sellerId must be your own user id", nil))
+		return
+	}
 	l, err := h.repo.Create(c.Request.Context(), repository.CreateListing{
-		SellerID:    req.SellerID,
+		SellerID:    actor.UserID,
 		Title:       req.Title,
 		Description: req.Description,
 		Category:    req.Category,
@@ -140,7 +168,12 @@ func (h *ListingsHandler) ListMine(c *gin.Context) {
 		Offset:   offset,
 		Sort:     sort,
 		SellerID: &sellerID,
+		Cursor:   c.Query("cursor"),
 	})
+	if errors.Is(err, repository.ErrBadCursor) {
+		c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", "invalid cursor", nil))
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, resp.Err("INTERNAL", "list failed", err.Error()))
 		return
@@ -171,10 +204,11 @@ func (h *ListingsHandler) ListMine(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, resp.Data(gin.H{
-		"items":  out,
-		"total":  total,
-		"limit":  limit,
-		"offset": offset,
+		"items":      out,
+		"total":      total,
+		"limit":      limit,
+		"offset":     offset,
+		"nextCursor": nextListingCursor(sort, items, limit),
 	}))
 }
 
@@ -191,9 +225,15 @@ func (h *ListingsHandler) Get(c *gin.Context) {
 	}
 
 	out := listingWithImage{Listing: l}
+	ctx := c.Request.Context()
+
+	if h.reviews != nil {
+		if rating, err := h.reviews.SellerRating(ctx, l.SellerID); err == nil {
+			out.SellerRating = &rating
+		}
+	}
 
 	if h.images != nil && h.s3 != nil {
-		ctx := c.Request.Context()
 		if imgs, err := h.images.ListByListing(ctx, id); err == nil && len(imgs) > 0 {
 			for _, img := range imgs {
 				if url, err := h.s3.PresignGet(ctx, img.S3Key, h.expiry); err == nil {
@@ -209,12 +249,16 @@ func (h *ListingsHandler) Get(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, resp.Data(out))
+	items := []listingWithImage{out}
+	h.withFavorites(c, items)
+
+	c.JSON(http.StatusOK, resp.Data(items[0]))
 }
 
 func (h *ListingsHandler) List(c *gin.Context) {
 	q := c.Query("q")
 	category := c.Query("category")
+	condition := c.Query("condition")
 	status := c.DefaultQuery("status", "active")
 	sort := c.DefaultQuery("sort", "created_desc")
 
@@ -242,20 +286,32 @@ func (h *ListingsHandler) List(c *gin.Context) {
 		}
 	}
 
-	items, total, err := h.repo.List(c.Request.Context(), repository.ListParams{
-		Q:        q,
-		Category: category,
-		PriceMin: pminPtr,
-		PriceMax: pmaxPtr,
-		Status:   status,
-		Limit:    limit,
-		Offset:   offset,
-		Sort:     sort,
-	})
+	params := repository.ListParams{
+		Q:         q,
+		Category:  category,
+		Condition: condition,
+		PriceMin:  pminPtr,
+		PriceMax:  pmaxPtr,
+		Status:    status,
+		Limit:     limit,
+		Offset:    offset,
+		Sort:      sort,
+		Cursor:    c.Query("cursor"),
+	}
+	items, total, err := h.repo.List(c.Request.Context(), params)
+	if errors.Is(err, repository.ErrBadCursor) {
+		c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", "invalid cursor", nil))
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, resp.Err("INTERNAL", "list failed", err.Error()))
 		return
 	}
+	facets, err := h.repo.Facets(c.Request.Context(), params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, resp.Err("INTERNAL", "facets failed", err.Error()))
+		return
+	}
 
 	ctx := c.Request.Context()
 	out := make([]listingWithImage, 0, len(items))
@@ -280,15 +336,55 @@ func (h *ListingsHandler) List(c *gin.Context) {
 
 		out = append(out, lw)
 	}
+	h.withFavorites(c, out)
 
 	c.JSON(http.StatusOK, resp.Data(gin.H{
-		"items":  out,
-		"total":  total,
-		"limit":  limit,
-		"offset": offset,
+		"items":      out,
+		"total":      total,
+		"limit":      limit,
+		"offset":     offset,
+		"nextCursor": nextListingCursor(sort, items, limit),
+		"facets":     facets,
 	}))
 }
 
+// withFavorites fills in favorite counts and, when the caller is signed in,
+// whether they favorited each listing. Lookup failures leave the defaults.
+func (h *ListingsHandler) withFavorites(c *gin.Context, items []listingWithImage) {
+	if h.favorites == nil || len(items) == 0 {
+		return
+	}
+	ctx := c.Request.Context()
+	ids := make([]uuid.UUID, len(items))
+	for i, it := range items {
+		ids[i] = it.ID
+	}
+	if counts, err := h.favorites.Counts(ctx, ids); err == nil {
+		for i := range items {
+			items[i].FavoriteCount = counts[items[i].ID]
+		}
+	}
+	actor, err := actorFrom(c)
+	if err != nil {
+		return
+	}
+	if mine, err := h.favorites.FavoritedBy(ctx, actor.UserID, ids); err == nil {
+		for i := range items {
+			fav := mine[items[i].ID]
+			items[i].Favorited = &fav
+		}
+	}
+}
+
+// nextListingCursor is "" for the last page and for sorts that only page by
+// offset (relevance).
+func nextListingCursor(sort string, items []domain.Listing, limit int) string {
+	if _, ok := repository.CursorSort(sort); !ok || len(items) == 0 {
+		return ""
+	}
+	return repository.NextCursor(len(items), limit, repository.ListingCursor(sort, items[len(items)-1]))
+}
+
 // ------------------------ Update / MarkSold / Delete ------------------------
 
 type updateListingReq struct {
@@ -315,25 +411,47 @@ func (h *ListingsHandler) Update(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, resp.Err("VALIDATION_ERROR", "invalid fields", err.Error()))
 		return
 	}
-	l, err := h.repo.UpdatePartial(c.Request.Context(), id, repository.UpdateListing{
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	l, err := h.svc.Update(c.Request.Context(), actor, id, repository.UpdateListing{
 		Title: req.Title, Description: req.Description, Category: req.Category,
-		Price: req.Price, Condition: req.Condition, Status: req.Status,
-	})
+		Price: req.Price, Condition: req.Condition,
+	}, req.Status)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, resp.Err("INTERNAL", "update failed", err.Error()))
+		writeListingErr(c, err, "update failed")
 		return
 	}
 	c.JSON(http.StatusOK, resp.Data(l))
 }
 
+type markSoldReq struct {
+	BuyerID *uuid.UUID `json:"buyerId"`
+}
+
 func (h *ListingsHandler) MarkSold(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", "bad id", nil))
 		return
 	}
-	if err := h.repo.MarkSold(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, resp.Err("INTERNAL", "mark sold failed", err.Error()))
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	// The body is optional; it only names the buyer.
+	var req markSoldReq
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, resp.Err("VALIDATION_ERROR", "invalid json", err.Error()))
+			return
+		}
+	}
+	if err := h.svc.MarkSold(c.Request.Context(), actor, id, req.BuyerID); err != nil {
+		writeListingErr(c, err, "mark sold failed")
 		return
 	}
 	c.JSON(http.StatusOK, resp.Data(gin.H{"ok": true}))
@@ -345,9 +463,51 @@ func (h *ListingsHandler) Delete(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", "bad id", nil))
 		return
 	}
-	if err := h.repo.SoftDelete(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, resp.Err("INTERNAL", "delete failed", err.Error()))
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	if err := h.svc.Delete(c.Request.Context(), actor, id); err != nil {
+		writeListingErr(c, err, "delete failed")
 		return
 	}
 	c.JSON(http.StatusOK, resp.Data(gin.H{"ok": true}))
 }
+
+// History lists the listing's status changes for its seller or an admin.
+func (h *ListingsHandler) History(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", "bad id", nil))
+		return
+	}
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	limit, offset := pageParams(c)
+	items, total, err := h.svc.History(c.Request.Context(), actor, id, limit, offset)
+	if err != nil {
+		writeListingErr(c, err, "history failed")
+		return
+	}
+	c.JSON(http.StatusOK, resp.Data(gin.H{"items": items, "total": total, "limit": limit, "offset": offset}))
+}
+
+// writeListingErr maps ListingService errors onto HTTP responses.
+func writeListingErr(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusNotFound, resp.Err("NOT_FOUND", "listing not found", nil))
+	case errors.Is(err, domain.ErrForbidden):
+		c.JSON(http.StatusForbidden, resp.Err("FORBIDDEN", "not the owner of this listing", nil))
+	case errors.Is(err, service.ErrStatusTransition):
+		c.JSON(http.StatusConflict, resp.Err("CONFLICT", err.Error(), nil))
+	case errors.Is(err, service.ErrBuyerMismatch):
+		c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", err.Error(), nil))
+	default:
+		c.JSON(http.StatusInternalServerError, resp.Err("INTERNAL", msg, err.Error()))
+	}
+}
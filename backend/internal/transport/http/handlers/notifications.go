@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/resp"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/service"
+)
+
+type NotificationsHandler struct{ s *service.NotificationService }
+
+func NewNotificationsHandler(s *service.NotificationService) *NotificationsHandler {
+	return &NotificationsHandler{s: s}
+}
+
+// List returns the caller's notifications, newest first; ?unread=true keeps
+// only unread ones.
+func (h *NotificationsHandler) List(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	limit, offset := pageParams(c)
+	unreadOnly, _ := strconv.ParseBool(c.DefaultQuery("unread", "false"))
+	items, total, unread, err := h.s.List(c.Request.Context(), service.ListNotificationsQuery{
+		UserID: actor.UserID, UnreadOnly: unreadOnly, Cursor: c.Query("cursor"), Limit: limit, Offset: offset,
+	})
+	if errors.Is(err, repository.ErrBadCursor) {
+		c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", "invalid cursor", nil))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, resp.Err("INTERNAL", "list notifications failed", err.Error()))
+		return
+	}
+	var next string
+	if n := len(items); n > 0 {
+		next = repository.NextCursor(n, limit, repository.CreatedCursor(items[n-1].CreatedAt, items[n-1].ID))
+	}
+	c.JSON(http.StatusOK, resp.Data(gin.H{
+		"items": items, "total": total, "unread": unread, "limit": limit, "offset": offset, "nextCursor": next,
+	}))
+}
+
+func (h *NotificationsHandler) MarkRead(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", "bad id", nil))
+		return
+	}
+	n, err := h.s.MarkRead(c.Request.Context(), actor.UserID, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		c.JSON(http.StatusNotFound, resp.Err("NOT_FOUND", "notification not found", nil))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, resp.Err("INTERNAL", "mark read failed", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, resp.Data(n))
+}
+
+func (h *NotificationsHandler) MarkAllRead(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	marked, err := h.s.MarkAllRead(c.Request.Context(), actor.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, resp.Err("INTERNAL", "mark all read failed", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, resp.Data(gin.H{"marked": marked}))
+}
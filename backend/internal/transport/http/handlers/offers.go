@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/resp"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/service"
+)
+
+type OffersHandler struct {
+	s *service.OfferService
+	v *validator.Validate
+}
+
+func NewOffersHandler(s *service.OfferService, v *validator.Validate) *OffersHandler {
+	return &OffersHandler{s: s, v: v}
+}
+
+type offerAmountReq struct {
+	Amount  float64 `json:"amount" validate:"required,gt=0"`
+	Message string  `json:"message" validate:"max=500"`
+}
+
+// Create makes an offer on the listing in the path.
+func (h *OffersHandler) Create(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	listingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", "bad id", nil))
+		return
+	}
+	req, ok := h.bindAmount(c)
+	if !ok {
+		return
+	}
+	o, err := h.s.Make(c.Request.Context(), service.MakeOfferCmd{
+		BuyerID: actor.UserID, ListingID: listingID, Amount: req.Amount, Message: req.Message,
+	})
+	if err != nil {
+		writeOfferErr(c, err, "create offer failed")
+		return
+	}
+	c.JSON(http.StatusCreated, resp.Data(o))
+}
+
+// ListForListing: the seller sees every offer, a buyer only their own.
+func (h *OffersHandler) ListForListing(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	listingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", "bad id", nil))
+		return
+	}
+	limit, offset := pageParams(c)
+	items, total, err := h.s.ListForListing(c.Request.Context(), actor, listingID, c.Query("status"), limit, offset)
+	if err != nil {
+		writeOfferErr(c, err, "list offers failed")
+		return
+	}
+	c.JSON(http.StatusOK, resp.Data(gin.H{"items": items, "total": total, "limit": limit, "offset": offset}))
+}
+
+// ListMine returns offers the caller made (?role=buyer, default) or
+// received (?role=seller).
+func (h *OffersHandler) ListMine(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	role := c.DefaultQuery("role", "buyer")
+	if role != "buyer" && role != "seller" {
+		c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", "role must be buyer or seller", nil))
+		return
+	}
+	limit, offset := pageParams(c)
+	items, total, err := h.s.ListMine(c.Request.Context(), actor.UserID, role, c.Query("status"), limit, offset)
+	if err != nil {
+		writeOfferErr(c, err, "list offers failed")
+		return
+	}
+	c.JSON(http.StatusOK, resp.Data(gin.H{"items": items, "total": total, "limit": limit, "offset": offset}))
+}
+
+func (h *OffersHandler) Get(c *gin.Context) {
+	h.act(c, "get offer failed", h.s.Get)
+}
+
+func (h *OffersHandler) Counter(c *gin.Context) {
+	req, ok := h.bindAmount(c)
+	if !ok {
+		return
+	}
+	h.act(c, "counter failed", func(ctx context.Context, actor service.Actor, id uuid.UUID) (domain.Offer, error) {
+		return h.s.Counter(ctx, actor, id, service.CounterOfferCmd{Amount: req.Amount, Message: req.Message})
+	})
+}
+
+func (h *OffersHandler) Accept(c *gin.Context)   { h.act(c, "accept failed", h.s.Accept) }
+func (h *OffersHandler) Reject(c *gin.Context)   { h.act(c, "reject failed", h.s.Reject) }
+func (h *OffersHandler) Withdraw(c *gin.Context) { h.act(c, "withdraw failed", h.s.Withdraw) }
+
+// act runs fn on the offer in the path as the caller and writes the result.
+func (h *OffersHandler) act(c *gin.Context, msg string, fn func(context.Context, service.Actor, uuid.UUID) (domain.Offer, error)) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", "bad id", nil))
+		return
+	}
+	o, err := fn(c.Request.Context(), actor, id)
+	if err != nil {
+		writeOfferErr(c, err, msg)
+		return
+	}
+	c.JSON(http.StatusOK, resp.Data(o))
+}
+
+func (h *OffersHandler) bindAmount(c *gin.Context) (offerAmountReq, bool) {
+	var req offerAmountReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("VALIDATION_ERROR", "invalid json", err.Error()))
+		return req, false
+	}
+	if err := h.v.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("VALIDATION_ERROR", "invalid fields", err.Error()))
+		return req, false
+	}
+	return req, true
+}
+
+func writeOfferErr(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusNotFound, resp.Err("NOT_FOUND", "not found", nil))
+	case errors.Is(err, domain.ErrForbidden):
+		c.JSON(http.StatusForbidden, resp.Err("FORBIDDEN", "not a party to this offer", nil))
+	case errors.Is(err, service.ErrOfferOwnListing), errors.Is(err, service.ErrOfferAmount):
+		c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", err.Error(), nil))
+	case errors.Is(err, service.ErrOfferExists), errors.Is(err, service.ErrOfferTransition),
+		errors.Is(err, service.ErrListingNotAvailable):
+		c.JSON(http.StatusConflict, resp.Err("CONFLICT", err.Error(), nil))
+	default:
+		c.JSON(http.StatusInternalServerError, resp.Err("INTERNAL", msg, err.Error()))
+	}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPageParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		query      string
+		wantLimit  int
+		wantOffset int
+	}{
+		{"", 20, 0},
+		{"?limit=50&offset=40", 50, 40},
+		{"?limit=100", 100, 0},
+		{"?limit=-1&offset=-5", 20, 0},
+		{"?limit=0", 20, 0},
+		{"?limit=1000000", 20, 0},
+		{"?limit=ten&offset=x", 20, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/"+tt.query, nil)
+
+			limit, offset := pageParams(c)
+			if limit != tt.wantLimit || offset != tt.wantOffset {
+				t.Errorf("pageParams = %d, %d, want %d, %d", limit, offset, tt.wantLimit, tt.wantOffset)
+			}
+		})
+	}
+}
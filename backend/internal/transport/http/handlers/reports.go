@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/resp"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/service"
 )
@@ -51,13 +53,21 @@ func (h *ReportsHandler) List(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
 	items, total, err := h.s.List(c.Request.Context(), service.ListReportsQuery{
-		Status: status, Limit: limit, Offset: offset,
+		Status: status, Cursor: c.Query("cursor"), Limit: limit, Offset: offset,
 	})
+	if errors.Is(err, repository.ErrBadCursor) {
+		c.JSON(400, resp.Err("BAD_REQUEST", "invalid cursor", nil))
+		return
+	}
 	if err != nil {
 		c.JSON(500, resp.Err("INTERNAL", "list failed", err.Error()))
 		return
 	}
-	c.JSON(200, resp.Data(gin.H{"items": items, "total": total, "limit": limit, "offset": offset}))
+	var next string
+	if n := len(items); n > 0 {
+		next = repository.NextCursor(n, limit, repository.CreatedCursor(items[n-1].CreatedAt, items[n-1].ID))
+	}
+	c.JSON(200, resp.Data(gin.H{"items": items, "total": total, "limit": limit, "offset": offset, "nextCursor": next}))
 }
 
 type updateStatusReq struct {
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/resp"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/service"
+)
+
+type ReviewsHandler struct {
+	s *service.ReviewService
+	v *validator.Validate
+}
+
+func NewReviewsHandler(s *service.ReviewService, v *validator.Validate) *ReviewsHandler {
+	return &ReviewsHandler{s: s, v: v}
+}
+
+type createReviewReq struct {
+	Rating int    `json:"rating" validate:"required,min=1,max=5"`
+	Text   string `json:"text" validate:"max=2000"`
+}
+
+// Create reviews the seller of the sold listing in the path.
+func (h *ReviewsHandler) Create(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	listingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", "bad id", nil))
+		return
+	}
+	var req createReviewReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("VALIDATION_ERROR", "invalid json", err.Error()))
+		return
+	}
+	if err := h.v.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("VALIDATION_ERROR", "invalid fields", err.Error()))
+		return
+	}
+	rv, err := h.s.Create(c.Request.Context(), service.CreateReviewCmd{
+		BuyerID: actor.UserID, ListingID: listingID, Rating: req.Rating, Text: req.Text,
+	})
+	if err != nil {
+		writeReviewErr(c, err, "create review failed")
+		return
+	}
+	c.JSON(http.StatusCreated, resp.Data(rv))
+}
+
+type replyReviewReq struct {
+	Text string `json:"text" validate:"required,max=2000"`
+}
+
+func (h *ReviewsHandler) Reply(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", "bad id", nil))
+		return
+	}
+	var req replyReviewReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("VALIDATION_ERROR", "invalid json", err.Error()))
+		return
+	}
+	if err := h.v.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("VALIDATION_ERROR", "invalid fields", err.Error()))
+		return
+	}
+	rv, err := h.s.Reply(c.Request.Context(), actor, id, req.Text)
+	if err != nil {
+		writeReviewErr(c, err, "reply failed")
+		return
+	}
+	c.JSON(http.StatusOK, resp.Data(rv))
+}
+
+// ListForUser is public: the reviews a user received as a seller, with
+// their rating summary.
+func (h *ReviewsHandler) ListForUser(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", "bad id", nil))
+		return
+	}
+	limit, offset := pageParams(c)
+	ctx := c.Request.Context()
+	items, total, err := h.s.ListForSeller(ctx, id, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, resp.Err("INTERNAL", "list reviews failed", err.Error()))
+		return
+	}
+	rating, err := h.s.SellerRating(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, resp.Err("INTERNAL", "rating failed", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, resp.Data(gin.H{"items": items, "total": total, "limit": limit, "offset": offset, "rating": rating}))
+}
+
+func writeReviewErr(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusNotFound, resp.Err("NOT_FOUND", "not found", nil))
+	case errors.Is(err, domain.ErrForbidden):
+		c.JSON(http.StatusForbidden, resp.Err("FORBIDDEN", "not the reviewed seller", nil))
+	case errors.Is(err, service.ErrReviewNotBuyer):
+		c.JSON(http.StatusForbidden, resp.Err("FORBIDDEN", err.Error(), nil))
+	case errors.Is(err, service.ErrReviewRating), errors.Is(err, service.ErrReviewEmptyReply):
+		c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", err.Error(), nil))
+	case errors.Is(err, service.ErrReviewExists), errors.Is(err, service.ErrReviewReplied):
+		c.JSON(http.StatusConflict, resp.Err("CONFLICT", err.Error(), nil))
+	default:
+		c.JSON(http.StatusInternalServerError, resp.Err("INTERNAL", msg, err.Error()))
+	}
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/resp"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/service"
+)
+
+type SavedSearchesHandler struct {
+	s *service.SavedSearchService
+	v *validator.Validate
+}
+
+func NewSavedSearchesHandler(s *service.SavedSearchService, v *validator.Validate) *SavedSearchesHandler {
+	return &SavedSearchesHandler{s: s, v: v}
+}
+
+type saveSearchReq struct {
+	Name      string   `json:"name" validate:"max=100"`
+	Q         string   `json:"q" validate:"max=200"`
+	Category  string   `json:"category" validate:"max=60"`
+	Condition string   `json:"condition" validate:"omitempty,oneof=New LikeNew Good Fair"`
+	PriceMin  *float64 `json:"priceMin" validate:"omitempty,gte=0"`
+	PriceMax  *float64 `json:"priceMax" validate:"omitempty,gte=0"`
+}
+
+func (h *SavedSearchesHandler) Create(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	var req saveSearchReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("VALIDATION_ERROR", "invalid json", err.Error()))
+		return
+	}
+	if err := h.v.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("VALIDATION_ERROR", "invalid fields", err.Error()))
+		return
+	}
+	ss, err := h.s.Create(c.Request.Context(), service.SaveSearchCmd{
+		UserID:    actor.UserID,
+		Name:      req.Name,
+		Q:         req.Q,
+		Category:  req.Category,
+		Condition: req.Condition,
+		PriceMin:  req.PriceMin,
+		PriceMax:  req.PriceMax,
+	})
+	if err != nil {
+		writeSavedSearchErr(c, err, "save search failed")
+		return
+	}
+	c.JSON(http.StatusCreated, resp.Data(ss))
+}
+
+func (h *SavedSearchesHandler) List(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	limit, offset := pageParams(c)
+	items, total, err := h.s.List(c.Request.Context(), actor.UserID, limit, offset)
+	if err != nil {
+		writeSavedSearchErr(c, err, "list saved searches failed")
+		return
+	}
+	c.JSON(http.StatusOK, resp.Data(gin.H{"items": items, "total": total, "limit": limit, "offset": offset}))
+}
+
+func (h *SavedSearchesHandler) Delete(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", "bad id", nil))
+		return
+	}
+	if err := h.s.Delete(c.Request.Context(), actor, id); err != nil {
+		writeSavedSearchErr(c, err, "delete saved search failed")
+		return
+	}
+	c.JSON(http.StatusOK, resp.Data(gin.H{"deleted": true}))
+}
+
+// Matches lists new listings found by the caller's saved searches, newest
+// first.
+func (h *SavedSearchesHandler) Matches(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	limit, offset := pageParams(c)
+	items, total, err := h.s.Matches(c.Request.Context(), actor.UserID, limit, offset)
+	if err != nil {
+		writeSavedSearchErr(c, err, "list matches failed")
+		return
+	}
+	c.JSON(http.StatusOK, resp.Data(gin.H{"items": items, "total": total, "limit": limit, "offset": offset}))
+}
+
+// pageParams reads limit (1..100, default 20) and offset (default 0).
+func pageParams(c *gin.Context) (limit, offset int) {
+	limit, _ = strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	offset, _ = strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+func writeSavedSearchErr(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusNotFound, resp.Err("NOT_FOUND", "saved search not found", nil))
+	case errors.Is(err, domain.ErrForbidden):
+		c.JSON(http.StatusForbidden, resp.Err("FORBIDDEN", "not your saved search", nil))
+	case errors.Is(err, service.ErrSavedSearchEmpty), errors.Is(err, service.ErrSavedSearchPrice),
+		errors.Is(err, service.ErrSavedSearchName):
+		c.JSON(http.StatusBadRequest, resp.Err("VALIDATION_ERROR", err.Error(), nil))
+	case errors.Is(err, service.ErrSavedSearchLimit):
+		c.JSON(http.StatusConflict, resp.Err("CONFLICT", err.Error(), nil))
+	default:
+		c.JSON(http.StatusInternalServerError, resp.Err("INTERNAL", msg, err.Error()))
+	}
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/s3client"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/resp"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/service"
+)
+
+type UsersHandler struct {
+	s      *service.UserService
+	v      *validator.Validate
+	s3     *s3client.Client
+	expiry time.Duration
+}
+
+func NewUsersHandler(s *service.UserService, v *validator.Validate, s3c *s3client.Client, expiryMin int) *UsersHandler {
+	return &UsersHandler{s: s, v: v, s3: s3c, expiry: time.Duration(expiryMin) * time.Minute}
+}
+
+// Profile is public; admins (and the user themselves) also get the email.
+func (h *UsersHandler) Profile(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("BAD_REQUEST", "bad id", nil))
+		return
+	}
+	viewer, _ := actorFrom(c) // anonymous callers get the zero Actor
+	p, err := h.s.Profile(c.Request.Context(), viewer, id)
+	if err != nil {
+		writeUserErr(c, err, "profile failed")
+		return
+	}
+	c.JSON(http.StatusOK, resp.Data(h.withAvatar(c, p)))
+}
+
+// Me returns the caller's own profile.
+func (h *UsersHandler) Me(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	p, err := h.s.Profile(c.Request.Context(), actor, actor.UserID)
+	if err != nil {
+		writeUserErr(c, err, "profile failed")
+		return
+	}
+	c.JSON(http.StatusOK, resp.Data(h.withAvatar(c, p)))
+}
+
+type updateMeReq struct {
+	Name      *string `json:"name" validate:"omitempty,max=100"`
+	Bio       *string `json:"bio" validate:"omitempty,max=500"`
+	AvatarKey *string `json:"avatarKey"`
+}
+
+// UpdateMe edits the caller's name, bio and avatar. The avatar key comes from
+// AvatarPresign and must already be uploaded; "" removes the avatar.
+func (h *UsersHandler) UpdateMe(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	var req updateMeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("VALIDATION_ERROR", "invalid json", err.Error()))
+		return
+	}
+	if err := h.v.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("VALIDATION_ERROR", "invalid fields", err.Error()))
+		return
+	}
+	p, err := h.s.UpdateMe(c.Request.Context(), actor, service.UpdateProfileCmd{
+		Name: req.Name, Bio: req.Bio, AvatarKey: req.AvatarKey,
+	})
+	if err != nil {
+		writeUserErr(c, err, "update profile failed")
+		return
+	}
+	c.JSON(http.StatusOK, resp.Data(h.withAvatar(c, p)))
+}
+
+type avatarPresignReq struct {
+	FileName    string `json:"fileName" validate:"required"`
+	ContentType string `json:"contentType" validate:"required,startswith=image/"`
+}
+
+// AvatarPresign returns a PUT URL for a new avatar under the caller's avatar
+// prefix; send the key to PATCH /users/me once the upload is done.
+func (h *UsersHandler) AvatarPresign(c *gin.Context) {
+	actor, err := actorFrom(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, resp.Err("UNAUTHORIZED", err.Error(), nil))
+		return
+	}
+	var req avatarPresignReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("VALIDATION_ERROR", "invalid json", err.Error()))
+		return
+	}
+	if err := h.v.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, resp.Err("VALIDATION_ERROR", "invalid fields", err.Error()))
+		return
+	}
+
+	if h.s3 == nil {
+		c.JSON(http.StatusServiceUnavailable, resp.Err("UNAVAILABLE", "avatar storage is not configured", nil))
+		return
+	}
+
+	ext := strings.ToLower(filepath.Ext(req.FileName))
+	key := fmt.Sprintf("%s%s%s", service.AvatarPrefix(actor.UserID), uuid.New().String(), ext)
+
+	ps, err := h.s3.PresignPut(c.Request.Context(), key, req.ContentType, h.expiry)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, resp.Err("INTERNAL", "presign failed", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, resp.Data(ps))
+}
+
+// withAvatar signs a GET URL for the avatar, if there is one.
+func (h *UsersHandler) withAvatar(c *gin.Context, p domain.PublicProfile) domain.PublicProfile {
+	if p.AvatarKey != "" && h.s3 != nil {
+		if url, err := h.s3.PresignGet(c.Request.Context(), p.AvatarKey, h.expiry); err == nil {
+			p.AvatarURL = url
+		}
+	}
+	return p
+}
+
+func writeUserErr(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		c.JSON(http.StatusNotFound, resp.Err("NOT_FOUND", "user not found", nil))
+	case errors.Is(err, service.ErrProfileName), errors.Is(err, service.ErrProfileBio),
+		errors.Is(err, service.ErrAvatarKey), errors.Is(err, service.ErrAvatarMissing):
+		c.JSON(http.StatusBadRequest, resp.Err("VALIDATION_ERROR", err.Error(), nil))
+	case errors.Is(err, service.ErrAvatarStorage):
+		c.JSON(http.StatusServiceUnavailable, resp.Err("UNAVAILABLE", err.Error(), nil))
+	default:
+		c.JSON(http.StatusInternalServerError, resp.Err("INTERNAL", msg, err.Error()))
+	}
+}
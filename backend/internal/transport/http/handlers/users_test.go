@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/service"
+)
+
+// Without object storage an avatar can't be set or presigned, but a key
+// outside the caller's prefix is refused as invalid before storage matters.
+func TestAvatarWithoutStorage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewUsersHandler(service.NewUserService(nil, nil, nil), validator.New(), nil, 15)
+	userID := uuid.New()
+
+	tests := []struct {
+		name     string
+		handler  gin.HandlerFunc
+		body     string
+		wantCode int
+	}{
+		{"update avatar", h.UpdateMe, `{"avatarKey":"avatars/` + userID.String() + `/a.jpg"}`, http.StatusServiceUnavailable},
+		{"update foreign avatar", h.UpdateMe, `{"avatarKey":"avatars/` + uuid.NewString() + `/a.jpg"}`, http.StatusBadRequest},
+		{"presign avatar", h.AvatarPresign, `{"fileName":"me.jpg","contentType":"image/jpeg"}`, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set("userId", userID.String())
+
+			tt.handler(c)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantCode, w.Body)
+			}
+		})
+	}
+}
@@ -1,14 +1,25 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/jwt"
 )
 
-func JWT(secret []byte, roles ...string) gin.HandlerFunc {
+// RevocationList says whether an access token was revoked before it
+// expired, by its jti or its session (sid).
+type RevocationList interface {
+	IsRevoked(ctx context.Context, jti, sid string) (bool, error)
+}
+
+// JWT requires a bearer token that tokens verifies, with one of roles (any
+// role if none are given). A nil revocation list skips the revocation check.
+func JWT(tokens *jwt.Signer, revoked RevocationList, roles ...string) gin.HandlerFunc {
 	allowed := map[string]bool{}
 	for _, r := range roles {
 		allowed[r] = true
@@ -20,29 +31,108 @@ func JWT(secret []byte, roles ...string) gin.HandlerFunc {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing bearer"})
 			return
 		}
-		raw := strings.TrimPrefix(h, "Bearer ")
 
-		tok, err := jwt.Parse(raw, func(t *jwt.Token) (interface{}, error) {
-			return secret, nil
-		})
-		if err != nil || !tok.Valid {
+		tok, ok := parseToken(tokens, strings.TrimPrefix(h, "Bearer "))
+		if !ok {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
 			return
 		}
+		if revoked != nil {
+			isRevoked, err := revoked.IsRevoked(c.Request.Context(), tok.jti, tok.sid)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "cannot check token"})
+				return
+			}
+			if isRevoked {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token revoked"})
+				return
+			}
+		}
 
-		claims, _ := tok.Claims.(jwt.MapClaims)
-
-		idStr, _ := claims["sub"].(string)
-		role, _ := claims["role"].(string)
-
-		if len(allowed) > 0 && !allowed[role] {
+		if len(allowed) > 0 && !allowed[tok.role] {
 			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 			return
 		}
 
-		c.Set("userId", idStr)
-		c.Set("role", role)
+		tok.set(c)
+		c.Next()
+	}
+}
+
+// OptionalJWT is for public routes that show more to signed-in callers: a
+// valid bearer token sets the caller like JWT does, anything else (including
+// a revoked token) carries on as anonymous.
+func OptionalJWT(tokens *jwt.Signer, revoked RevocationList) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h := c.GetHeader("Authorization")
+		if strings.HasPrefix(h, "Bearer ") {
+			if tok, ok := parseToken(tokens, strings.TrimPrefix(h, "Bearer ")); ok && !isRevoked(c, revoked, tok) {
+				tok.set(c)
+			}
+		}
+		c.Next()
+	}
+}
+
+func isRevoked(c *gin.Context, revoked RevocationList, tok token) bool {
+	if revoked == nil {
+		return false
+	}
+	r, err := revoked.IsRevoked(c.Request.Context(), tok.jti, tok.sid)
+	return err != nil || r
+}
+
+// EmailVerifier says whether a user confirmed their email.
+type EmailVerifier interface {
+	IsEmailVerified(ctx context.Context, userID string) (bool, error)
+}
 
+// VerifiedEmail goes after JWT and lets only users with a verified email
+// through. A nil verifier lets everyone through.
+func VerifiedEmail(v EmailVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if v == nil {
+			c.Next()
+			return
+		}
+		ok, err := v.IsEmailVerified(c.Request.Context(), c.GetString("userId"))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "cannot check email verification"})
+			return
+		}
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "email not verified", "code": "EMAIL_UNVERIFIED"})
+			return
+		}
 		c.Next()
 	}
 }
+
+// token holds the claims handlers read back from the context.
+type token struct {
+	sub, role string
+	jti, sid  string
+	exp       time.Time
+}
+
+// set stores the caller for handlers: userId and role, plus jti, sessionId
+// and tokenExp for logout.
+func (t token) set(c *gin.Context) {
+	c.Set("userId", t.sub)
+	c.Set("role", t.role)
+	c.Set("jti", t.jti)
+	c.Set("sessionId", t.sid)
+	c.Set("tokenExp", t.exp)
+}
+
+func parseToken(tokens *jwt.Signer, raw string) (token, bool) {
+	claims, err := tokens.Verify(raw)
+	if err != nil {
+		return token{}, false
+	}
+	t := token{sub: claims.Sub, role: claims.Role, jti: claims.ID, sid: claims.Sid}
+	if claims.ExpiresAt != nil {
+		t.exp = claims.ExpiresAt.Time
+	}
+	return t, true
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/jwt"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/s3client"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/service"
@@ -19,13 +20,22 @@ type Deps struct {
 	Reports  repository.ReportRepo
 	Admin    service.AdminRepo // admin uses its own interface
 	AuthRepo repository.AuthRepo
-	// Chat     repository.ChatRepo
+	Chat     repository.ChatRepo
+	Offers   repository.OfferRepo
+	Reviews  repository.ReviewRepo
 
 	// services
-	AuthSvc   *service.AuthService
-	ReportSvc *service.ReportService
-	AdminSvc  *service.AdminService
-	// ChatSvc   *service.ChatService
+	AuthSvc         *service.AuthService
+	ListingSvc      *service.ListingService
+	ReportSvc       *service.ReportService
+	AdminSvc        *service.AdminService
+	ChatSvc         *service.ChatService
+	OfferSvc        *service.OfferService
+	ReviewSvc       *service.ReviewService
+	UserSvc         *service.UserService
+	FavoriteSvc     *service.FavoriteService
+	SavedSearchSvc  *service.SavedSearchService
+	NotificationSvc *service.NotificationService
 
 	// infra
 	Validate  *validator.Validate
@@ -33,8 +43,10 @@ type Deps struct {
 	ExpiryMin int
 
 	// auth/mode
-	JWTSecret []byte
-	Env       string // "dev"/"prod"
+	JWT         *jwt.Signer
+	Revocations middleware.RevocationList // nil: revoked tokens are not checked
+	Verified    middleware.EmailVerifier  // nil: unverified users may create listings
+	Env         string                    // "dev"/"prod"
 }
 
 func NewRouter(d Deps) *gin.Engine {
@@ -56,7 +68,7 @@ func NewRouter(d Deps) *gin.Engine {
 	r.GET("/healthz", func(c *gin.Context) { c.String(200, "ok") })
 
 	// Handlers
-	lh := handlers.NewListingsHandler(d.Listings, d.Images, d.S3, d.Validate, d.ExpiryMin)
+	lh := handlers.NewListingsHandler(d.Listings, d.ListingSvc, d.ReviewSvc, d.FavoriteSvc, d.Images, d.S3, d.Validate, d.ExpiryMin)
 	uh := handlers.NewUploadsHandler(d.Validate, d.S3, d.Images, d.ExpiryMin)
 
 	var ah *handlers.AuthHandler
@@ -71,6 +83,34 @@ func NewRouter(d Deps) *gin.Engine {
 	if d.AdminSvc != nil {
 		adm = handlers.NewAdminHandler(d.AdminSvc)
 	}
+	var ch *handlers.ChatHandler
+	if d.ChatSvc != nil {
+		ch = handlers.NewChatHandler(d.ChatSvc, d.Validate)
+	}
+	var rvh *handlers.ReviewsHandler
+	if d.ReviewSvc != nil {
+		rvh = handlers.NewReviewsHandler(d.ReviewSvc, d.Validate)
+	}
+	var ush *handlers.UsersHandler
+	if d.UserSvc != nil {
+		ush = handlers.NewUsersHandler(d.UserSvc, d.Validate, d.S3, d.ExpiryMin)
+	}
+	var fh *handlers.FavoritesHandler
+	if d.FavoriteSvc != nil {
+		fh = handlers.NewFavoritesHandler(d.FavoriteSvc)
+	}
+	var ssh *handlers.SavedSearchesHandler
+	if d.SavedSearchSvc != nil {
+		ssh = handlers.NewSavedSearchesHandler(d.SavedSearchSvc, d.Validate)
+	}
+	var nh *handlers.NotificationsHandler
+	if d.NotificationSvc != nil {
+		nh = handlers.NewNotificationsHandler(d.NotificationSvc)
+	}
+	var oh *handlers.OffersHandler
+	if d.OfferSvc != nil {
+		oh = handlers.NewOffersHandler(d.OfferSvc, d.Validate)
+	}
 
 	// Routes
 	v1 := r.Group("/v1")
@@ -78,29 +118,92 @@ func NewRouter(d Deps) *gin.Engine {
 		if ah != nil {
 			v1.POST("/auth/sign-up", ah.SignUp)
 			v1.POST("/auth/sign-in", ah.SignIn)
+			v1.POST("/auth/refresh", ah.Refresh)
+			v1.POST("/auth/verify", ah.Verify)
+			v1.POST("/auth/verify/resend", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), ah.ResendVerification)
+			v1.POST("/auth/password/forgot", ah.ForgotPassword)
+			v1.POST("/auth/password/reset", ah.ResetPassword)
+			v1.POST("/auth/password/change", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), ah.ChangePassword)
+			v1.POST("/auth/logout", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), ah.Logout)
+			v1.GET("/auth/sessions", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), ah.Sessions)
+			v1.DELETE("/auth/sessions/:id", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), ah.RevokeSession)
 		}
 
-		v1.GET("/listings", lh.List) // Public - anyone can browse listings
-		v1.GET("/listings/:id", lh.Get) // Public - anyone can view listing details
-		v1.POST("/listings", middleware.JWT(d.JWTSecret, "buyer", "seller", "admin"), lh.Create)
-		v1.PATCH("/listings/:id", middleware.JWT(d.JWTSecret, "buyer", "seller", "admin"), lh.Update)
-		v1.POST("/listings/:id/mark-sold", middleware.JWT(d.JWTSecret, "buyer", "seller", "admin"), lh.MarkSold)
-		v1.DELETE("/listings/:id", middleware.JWT(d.JWTSecret, "buyer", "seller", "admin"), lh.Delete)
-		v1.GET("/listings/mine", middleware.JWT(d.JWTSecret, "buyer", "seller", "admin"), lh.ListMine)
+		v1.GET("/listings", middleware.OptionalJWT(d.JWT, d.Revocations), lh.List)    // Public - anyone can browse listings
+		v1.GET("/listings/:id", middleware.OptionalJWT(d.JWT, d.Revocations), lh.Get) // Public - anyone can view listing details
+		v1.POST("/listings", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), middleware.VerifiedEmail(d.Verified), lh.Create)
+		v1.PATCH("/listings/:id", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), lh.Update)
+		v1.POST("/listings/:id/mark-sold", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), lh.MarkSold)
+		v1.DELETE("/listings/:id", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), lh.Delete)
+		v1.GET("/listings/mine", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), lh.ListMine)
+		v1.GET("/listings/:id/history", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), lh.History)
 
-		v1.POST("/uploads/presign", middleware.JWT(d.JWTSecret, "buyer", "seller", "admin"), uh.Presign)
-		v1.POST("/uploads/complete", middleware.JWT(d.JWTSecret, "buyer", "seller", "admin"), uh.Complete)
+		v1.POST("/uploads/presign", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), uh.Presign)
+		v1.POST("/uploads/complete", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), uh.Complete)
 
 		if rh != nil {
-			v1.POST("/reports", middleware.JWT(d.JWTSecret, "buyer", "seller", "admin"), rh.Create) // Allow all authenticated users to report
-			v1.GET("/reports", middleware.JWT(d.JWTSecret, "admin"), rh.List)
-			v1.PATCH("/reports/:id/status", middleware.JWT(d.JWTSecret, "admin"), rh.UpdateStatus)
+			v1.POST("/reports", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), rh.Create) // Allow all authenticated users to report
+			v1.GET("/reports", middleware.JWT(d.JWT, d.Revocations, "admin"), rh.List)
+			v1.PATCH("/reports/:id/status", middleware.JWT(d.JWT, d.Revocations, "admin"), rh.UpdateStatus)
 		}
 
 		if adm != nil {
-			v1.GET("/admin/metrics", middleware.JWT(d.JWTSecret, "admin"), adm.Metrics)
-			v1.GET("/admin/users", middleware.JWT(d.JWTSecret, "admin"), adm.Users)
-			v1.POST("/admin/listings/:id/remove", middleware.JWT(d.JWTSecret, "admin"), adm.ForceRemoveListing)
+			v1.GET("/admin/metrics", middleware.JWT(d.JWT, d.Revocations, "admin"), adm.Metrics)
+			v1.GET("/admin/users", middleware.JWT(d.JWT, d.Revocations, "admin"), adm.Users)
+			v1.POST("/admin/listings/:id/remove", middleware.JWT(d.JWT, d.Revocations, "admin"), adm.ForceRemoveListing)
+			v1.POST("/admin/users/:id/unlock", middleware.JWT(d.JWT, d.Revocations, "admin"), adm.UnlockUser)
+			v1.GET("/admin/audit", middleware.JWT(d.JWT, d.Revocations, "admin"), adm.Audit)
+		}
+
+		if ch != nil {
+			v1.POST("/conversations", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), ch.Start)
+			v1.GET("/conversations", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), ch.List)
+			v1.GET("/conversations/unread", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), ch.Unread)
+			v1.GET("/conversations/:id/messages", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), ch.Messages)
+			v1.POST("/conversations/:id/read", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), ch.MarkRead)
+		}
+
+		if oh != nil {
+			v1.POST("/listings/:id/offers", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), oh.Create)
+			v1.GET("/listings/:id/offers", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), oh.ListForListing)
+			v1.GET("/offers", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), oh.ListMine)
+			v1.GET("/offers/:id", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), oh.Get)
+			v1.POST("/offers/:id/counter", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), oh.Counter)
+			v1.POST("/offers/:id/accept", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), oh.Accept)
+			v1.POST("/offers/:id/reject", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), oh.Reject)
+			v1.POST("/offers/:id/withdraw", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), oh.Withdraw)
+		}
+
+		if rvh != nil {
+			v1.POST("/listings/:id/reviews", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), rvh.Create)
+			v1.POST("/reviews/:id/reply", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), rvh.Reply)
+			v1.GET("/users/:id/reviews", rvh.ListForUser) // Public
+		}
+
+		if fh != nil {
+			v1.POST("/listings/:id/favorite", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), fh.Add)
+			v1.DELETE("/listings/:id/favorite", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), fh.Remove)
+			v1.GET("/favorites", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), fh.List)
+		}
+
+		if ssh != nil {
+			v1.POST("/saved-searches", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), ssh.Create)
+			v1.GET("/saved-searches", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), ssh.List)
+			v1.GET("/saved-searches/matches", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), ssh.Matches)
+			v1.DELETE("/saved-searches/:id", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), ssh.Delete)
+		}
+
+		if nh != nil {
+			v1.GET("/notifications", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), nh.List)
+			v1.POST("/notifications/read-all", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), nh.MarkAllRead)
+			v1.POST("/notifications/:id/read", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), nh.MarkRead)
+		}
+
+		if ush != nil {
+			v1.GET("/users/me", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), ush.Me)
+			v1.PATCH("/users/me", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), ush.UpdateMe)
+			v1.POST("/users/me/avatar/presign", middleware.JWT(d.JWT, d.Revocations, "buyer", "seller", "admin"), ush.AvatarPresign)
+			v1.GET("/users/:id", middleware.OptionalJWT(d.JWT, d.Revocations), ush.Profile) // Public; email for admins
 		}
 
 	}
@@ -1,41 +1,39 @@
 package ws
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/jwt"
 )
 
-// Claims represents the JWT claims we expect
-type Claims struct {
-	UserID string `json:"sub"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
-	jwt.RegisteredClaims
+// RevocationList says whether a token was revoked before it expired, by its
+// jti or its session.
+type RevocationList interface {
+	IsRevoked(ctx context.Context, jti, sid string) (bool, error)
 }
 
-// ValidateToken validates a JWT token and extracts claims
-func ValidateToken(tokenString string, secret []byte) (*Claims, error) {
+// ValidateToken verifies a JWT with the same rules as the HTTP API and
+// extracts its claims. A nil revocation list skips the revocation check.
+func ValidateToken(ctx context.Context, tokenString string, tokens *jwt.Signer, revoked RevocationList) (*jwt.Claims, error) {
 	if tokenString == "" {
 		return nil, errors.New("token is required")
 	}
 
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		// Verify signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return secret, nil
-	})
-
+	claims, err := tokens.Verify(tokenString)
 	if err != nil {
 		return nil, fmt.Errorf("invalid token: %w", err)
 	}
 
-	claims, ok := token.Claims.(*Claims)
-	if !ok || !token.Valid {
-		return nil, errors.New("invalid token claims")
+	if revoked != nil {
+		isRevoked, err := revoked.IsRevoked(ctx, claims.ID, claims.Sid)
+		if err != nil {
+			return nil, fmt.Errorf("check revocation: %w", err)
+		}
+		if isRevoked {
+			return nil, errors.New("token revoked")
+		}
 	}
 
 	return claims, nil
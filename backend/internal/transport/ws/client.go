@@ -1,12 +1,18 @@
 package ws
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"go.uber.org/zap"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/service"
 )
 
 const (
@@ -17,22 +23,25 @@ const (
 )
 
 type Client struct {
-	hub    *Hub
-	conn   *websocket.Conn
-	send   chan []byte
-	userID string
-	role   string
-	logger *zap.Logger
+	hub       *Hub
+	conn      *websocket.Conn
+	send      chan []byte
+	userID    string
+	sessionID string // one per connection; scopes the assistant's conversation memory
+	role      string
+	logger    *zap.Logger
+	replay    chatReplay
 }
 
 func NewClient(hub *Hub, conn *websocket.Conn, userID, role string, logger *zap.Logger) *Client {
 	return &Client{
-		hub:    hub,
-		conn:   conn,
-		send:   make(chan []byte, 256),
-		userID: userID,
-		role:   role,
-		logger: logger,
+		hub:       hub,
+		conn:      conn,
+		send:      make(chan []byte, 256),
+		userID:    userID,
+		sessionID: uuid.NewString(),
+		role:      role,
+		logger:    logger,
 	}
 }
 
@@ -47,9 +56,18 @@ func (c *Client) handleEvent(event Event) {
 	case EventTypeAgentSearch:
 		c.handleAgentSearch(event)
 
+	case EventTypeChatRequest:
+		c.handleChatRequest(event)
+
 	case EventTypeChatMessage:
 		c.handleChatMessage(event)
 
+	case EventTypeChatRead:
+		c.handleChatRead(event)
+
+	case EventTypeChatReset:
+		c.handleChatReset(event)
+
 	default:
 		c.sendError(event.RequestID, "unknown event type", "UNKNOWN_EVENT")
 	}
@@ -61,19 +79,63 @@ func (c *Client) handleChatMessage(event Event) {
 		return
 	}
 
-	if strings.TrimSpace(payload.ToUserID) == "" {
-		c.sendError(event.RequestID, "toUserId is required", "MISSING_TO_USER")
-		return
-	}
 	if strings.TrimSpace(payload.Text) == "" {
 		c.sendError(event.RequestID, "text is required", "MISSING_TEXT")
 		return
 	}
+	if c.hub.chat == nil {
+		c.sendError(event.RequestID, "chat is unavailable", "CHAT_UNAVAILABLE")
+		return
+	}
+
+	senderID, err := uuid.Parse(c.userID)
+	if err != nil {
+		c.sendError(event.RequestID, "invalid user id", "INVALID_USER")
+		return
+	}
+	cmd := service.SendMessageCmd{SenderID: senderID, Body: payload.Text}
+	for _, f := range []struct {
+		raw string
+		dst **uuid.UUID
+	}{
+		{payload.ConversationID, &cmd.ConversationID},
+		{payload.ListingID, &cmd.ListingID},
+		{payload.ToUserID, &cmd.ToUserID},
+	} {
+		if strings.TrimSpace(f.raw) == "" {
+			continue
+		}
+		id, err := uuid.Parse(f.raw)
+		if err != nil {
+			c.sendError(event.RequestID, "invalid id in chat payload", "INVALID_CHAT_PAYLOAD")
+			return
+		}
+		*f.dst = &id
+	}
+
+	// Persist first; delivery below is best-effort.
+	res, err := c.hub.chat.SendMessage(context.Background(), cmd)
+	if err != nil {
+		c.logger.Warn("chat message rejected", zap.String("userId", c.userID), zap.Error(err))
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			c.sendError(event.RequestID, "conversation or listing not found", "NOT_FOUND")
+		case errors.Is(err, domain.ErrForbidden):
+			c.sendError(event.RequestID, "not a participant", "FORBIDDEN")
+		case errors.Is(err, service.ErrMissingChatPeer), errors.Is(err, service.ErrChatWithSelf):
+			c.sendError(event.RequestID, err.Error(), "MISSING_TO_USER")
+		default:
+			c.sendError(event.RequestID, "failed to send message", "CHAT_FAILED")
+		}
+		return
+	}
 
 	deliver := ChatDeliverPayload{
-		FromUserID: c.userID,
-		Text:       payload.Text,
-		SentAt:     time.Now(),
+		MessageID:      res.Message.ID.String(),
+		ConversationID: res.Message.ConversationID.String(),
+		FromUserID:     c.userID,
+		Text:           res.Message.Body,
+		SentAt:         res.Message.CreatedAt,
 	}
 
 	msg, err := NewEvent(EventTypeChatDeliver, event.RequestID, deliver)
@@ -82,16 +144,18 @@ func (c *Client) handleChatMessage(event Event) {
 		return
 	}
 
-	if err := c.hub.BroadcastToUser(payload.ToUserID, msg); err != nil {
-		c.logger.Warn("chat deliver failed (user offline?)",
-			zap.String("toUserId", payload.ToUserID),
-			zap.Error(err),
-		)
-		c.sendError(event.RequestID, "recipient not connected", "RECIPIENT_OFFLINE")
-		return
+	for _, to := range res.Recipients {
+		if err := c.hub.deliverChat(to.String(), deliver.MessageID, msg); err != nil {
+			// Stored as unread; replayed when the recipient reconnects.
+			c.logger.Debug("chat deliver deferred (user offline?)",
+				zap.String("toUserId", to.String()),
+				zap.Error(err),
+			)
+			c.notifyMissedMessage(to, res.Message)
+		}
 	}
 
-	if err := c.hub.BroadcastToUser(c.userID, msg); err != nil {
+	if err := c.hub.deliverChat(c.userID, deliver.MessageID, msg); err != nil {
 		c.logger.Warn("failed to echo chat message to sender",
 			zap.String("userId", c.userID),
 			zap.Error(err),
@@ -99,9 +163,82 @@ func (c *Client) handleChatMessage(event Event) {
 	}
 }
 
+type chatNoticeData struct {
+	ConversationID uuid.UUID `json:"conversationId"`
+	MessageID      uuid.UUID `json:"messageId"`
+	FromUserID     string    `json:"fromUserId"`
+}
+
+// notifyMissedMessage leaves a notification for a recipient who could not
+// take the message live.
+func (c *Client) notifyMissedMessage(to uuid.UUID, m domain.Message) {
+	if c.hub.notifier == nil {
+		return
+	}
+	body := m.Body
+	if r := []rune(body); len(r) > 140 {
+		body = string(r[:140]) + "…"
+	}
+	c.hub.notifier.Notify(context.Background(), service.NewNotification{
+		UserID: to,
+		Kind:   domain.NotifyChatMessage,
+		Title:  "New message",
+		Body:   body,
+		Data:   chatNoticeData{ConversationID: m.ConversationID, MessageID: m.ID, FromUserID: c.userID},
+	})
+}
+
+func (c *Client) handleChatRead(event Event) {
+	var payload ChatReadPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		c.sendError(event.RequestID, "invalid chat.read payload", "INVALID_CHAT_PAYLOAD")
+		return
+	}
+	convID, err := uuid.Parse(payload.ConversationID)
+	if err != nil {
+		c.sendError(event.RequestID, "conversationId is required", "INVALID_CHAT_PAYLOAD")
+		return
+	}
+	userID, err := uuid.Parse(c.userID)
+	if err != nil {
+		c.sendError(event.RequestID, "invalid user id", "INVALID_USER")
+		return
+	}
+	if c.hub.chat == nil {
+		c.sendError(event.RequestID, "chat is unavailable", "CHAT_UNAVAILABLE")
+		return
+	}
+
+	var at time.Time
+	if payload.ReadAt != nil {
+		at = *payload.ReadAt
+	}
+	if err := c.hub.chat.MarkRead(context.Background(), userID, convID, at); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			c.sendError(event.RequestID, "conversation not found", "NOT_FOUND")
+		case errors.Is(err, domain.ErrForbidden):
+			c.sendError(event.RequestID, "not a participant", "FORBIDDEN")
+		default:
+			c.logger.Error("mark read failed", zap.Error(err))
+			c.sendError(event.RequestID, "failed to mark read", "CHAT_FAILED")
+		}
+		return
+	}
+
+	ack, err := NewEvent(EventTypeChatRead, event.RequestID, payload)
+	if err != nil {
+		c.logger.Error("failed to build chat read event", zap.Error(err))
+		return
+	}
+	if err := c.hub.BroadcastToUser(c.userID, ack); err != nil {
+		c.logger.Warn("failed to ack chat read", zap.String("userId", c.userID), zap.Error(err))
+	}
+}
+
 func (c *Client) ReadPump() {
 	defer func() {
-		c.hub.unregister <- c
+		c.hub.unregisterClient(c)
 		c.conn.Close()
 	}()
 
@@ -158,7 +295,20 @@ func (c *Client) WritePump() {
 	}
 }
 
-// handleChatMessage handles free-form chat requests (small talk + product search)
+// handleChatRequest passes free-form AI chat (small talk + product search)
+// to the worker, scoped to this connection's conversation memory.
+func (c *Client) handleChatRequest(event Event) {
+	var payload ChatRequestPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		c.sendError(event.RequestID, "invalid payload", "INVALID_PAYLOAD")
+		return
+	}
+	if strings.TrimSpace(payload.Text) == "" {
+		c.sendError(event.RequestID, "text is required", "MISSING_TEXT")
+		return
+	}
+	c.hub.publishChatRequest(c.userID, c.sessionID, event.RequestID, payload.Text, payload.Stream)
+}
 
 // handleAgentSearch handles agent search requests
 func (c *Client) handleAgentSearch(event Event) {
@@ -174,7 +324,24 @@ func (c *Client) handleAgentSearch(event Event) {
 	}
 
 	// Publish to pubsub bus for worker to process
-	c.hub.publishAgentRequest(c.userID, event.RequestID, payload.Query)
+	c.hub.publishAgentRequest(c.userID, c.sessionID, event.RequestID, payload.Query, payload.Stream)
+}
+
+// handleChatReset clears the assistant's memory of this session, so the next
+// query starts fresh instead of refining the previous search.
+func (c *Client) handleChatReset(event Event) {
+	c.hub.publishConversationReset(c.userID, c.sessionID)
+
+	ack, err := NewEvent(EventTypeChatReset, event.RequestID, map[string]bool{"ok": true})
+	if err != nil {
+		c.logger.Error("marshal chat.reset ack failed", zap.Error(err))
+		return
+	}
+	select {
+	case c.send <- ack:
+	default:
+		c.logger.Warn("client send buffer full, dropping chat.reset ack")
+	}
 }
 
 // sendError sends an error message to the client
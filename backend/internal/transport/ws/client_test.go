@@ -0,0 +1,94 @@
+package ws
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/pubsub"
+)
+
+func TestAssistantRequestsCarrySession(t *testing.T) {
+	tests := []struct {
+		name    string
+		event   string
+		topic   string
+		payload string
+		request func(pubsub.Message) (sessionID, requestID string, stream bool)
+	}{
+		{
+			name:    "chat.request",
+			event:   EventTypeChatRequest,
+			topic:   "chat.request",
+			payload: `{"text":"any cheaper ones?","stream":true}`,
+			request: func(m pubsub.Message) (string, string, bool) {
+				r := m.Payload.(pubsub.ChatRequest)
+				return r.SessionID, r.RequestID, r.Stream
+			},
+		},
+		{
+			name:    "agent.search",
+			event:   EventTypeAgentSearch,
+			topic:   "agent.request",
+			payload: `{"query":"macbook under $500","stream":true}`,
+			request: func(m pubsub.Message) (string, string, bool) {
+				r := m.Payload.(pubsub.AgentRequest)
+				return r.SessionID, r.RequestID, r.Stream
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := pubsub.New()
+			defer bus.Close()
+			requests := bus.Subscribe(tt.topic)
+			hub := NewHub(bus, nil, nil, zap.NewNop())
+			c := NewClient(hub, nil, "u1", "user", zap.NewNop())
+
+			c.handleEvent(Event{Type: tt.event, RequestID: "r1", Payload: json.RawMessage(tt.payload)})
+
+			select {
+			case m := <-requests:
+				sessionID, requestID, stream := tt.request(m)
+				if sessionID == "" || sessionID != c.sessionID {
+					t.Errorf("sessionId = %q, want the connection's %q", sessionID, c.sessionID)
+				}
+				if requestID != "r1" || !stream {
+					t.Errorf("requestId = %q, stream = %v; want r1, true", requestID, stream)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("nothing published on %s", tt.topic)
+			}
+		})
+	}
+}
+
+func TestChatRequestNeedsText(t *testing.T) {
+	bus := pubsub.New()
+	defer bus.Close()
+	requests := bus.Subscribe("chat.request")
+	hub := NewHub(bus, nil, nil, zap.NewNop())
+	c := NewClient(hub, nil, "u1", "user", zap.NewNop())
+
+	c.handleEvent(Event{Type: EventTypeChatRequest, RequestID: "r1", Payload: json.RawMessage(`{"text":"  "}`)})
+
+	select {
+	case m := <-requests:
+		t.Fatalf("published %+v for an empty message", m.Payload)
+	case msg := <-c.send:
+		var ev struct {
+			Type    string `json:"type"`
+			Payload struct {
+				Code string `json:"code"`
+			} `json:"payload"`
+		}
+		if err := json.Unmarshal(msg, &ev); err != nil {
+			t.Fatal(err)
+		}
+		if ev.Type != EventTypeError || ev.Payload.Code != "MISSING_TEXT" {
+			t.Errorf("got %s %q, want an error with MISSING_TEXT", ev.Type, ev.Payload.Code)
+		}
+	}
+}
@@ -10,12 +10,34 @@ const (
 	EventTypeAgentSearch   = "agent.search"
 	EventTypeAgentResponse = "agent.response"
 
-	// Chat / AI chat
-	EventTypeChatMessage  = "chat.message"  // client -> server (AI chat text)
+	// Streaming (when the request payload sets "stream": true): any number of
+	// deltas, then one done event carrying the full answer and results.
+	EventTypeAgentResponseDelta = "agent.response.delta"
+	EventTypeAgentResponseDone  = "agent.response.done"
+	EventTypeChatResponseDelta  = "chat.response.delta"
+	EventTypeChatResponseDone   = "chat.response.done"
+
+	// AI chat: free-form text (small talk + product search), answered with
+	// chat.response (or chat.response.delta/.done when streamed)
+	EventTypeChatRequest  = "chat.request"  // client -> server
 	EventTypeChatResponse = "chat.response" // server -> client (AI answer + results)
 
+	// User-to-user chat: client -> server, stored and delivered as chat.deliver
+	EventTypeChatMessage = "chat.message"
+
 	// Optional: for user-to-user chat delivery
 	EventTypeChatDeliver = "chat.deliver"
+	EventTypeChatRead    = "chat.read" // client -> server, echoed back to the reader's sessions
+
+	// AI assistant: forget this session's previous searches (client -> server, acked back)
+	EventTypeChatReset = "chat.reset"
+
+	// Server -> client: a favorited listing got cheaper or sold
+	EventTypeListingWatch = "listing.watch"
+	// Server -> client: new listings matching one of the user's saved searches
+	EventTypeSearchMatch = "search.match"
+	// Server -> client: a new in-app notification (offer, report outcome, missed chat message)
+	EventTypeNotificationNew = "notification.new"
 
 	EventTypeError = "error"
 )
@@ -31,15 +53,33 @@ type Event struct {
 
 // AgentSearchPayload is sent by clients to search listings via AI (product-only search)
 type AgentSearchPayload struct {
-	Query string `json:"query"`
+	Query  string `json:"query"`
+	Stream bool   `json:"stream,omitempty"` // answer as agent.response.delta events + agent.response.done
+}
+
+// ChatRequestPayload is sent by clients to talk to the AI assistant. The
+// assistant remembers earlier turns of the same connection.
+type ChatRequestPayload struct {
+	Text   string `json:"text"`
+	Stream bool   `json:"stream,omitempty"` // answer as chat.response.delta events + chat.response.done
 }
 
-// ChatMessagePayload is sent by clients for conversational AI chat
+// ChatMessagePayload is sent by clients for user-to-user chat. Either name an
+// existing conversation, or the listing it is about (sellers writing first
+// also give the buyer's toUserId).
 // Example:
-// { "type": "chat.message", "requestId": "...", "payload": { "text": "Do you have a used textbook for CMPE 202?" } }
+// { "type": "chat.message", "requestId": "...", "payload": { "listingId": "...", "text": "Is this still available?" } }
 type ChatMessagePayload struct {
-	ToUserID string `json:"toUserId"`
-	Text     string `json:"text"`
+	ConversationID string `json:"conversationId,omitempty"`
+	ListingID      string `json:"listingId,omitempty"`
+	ToUserID       string `json:"toUserId,omitempty"`
+	Text           string `json:"text"`
+}
+
+// ChatReadPayload marks a conversation read up to ReadAt (default: now).
+type ChatReadPayload struct {
+	ConversationID string     `json:"conversationId"`
+	ReadAt         *time.Time `json:"readAt,omitempty"`
 }
 
 type AgentResponsePayload struct {
@@ -51,10 +91,48 @@ type ChatResponsePayload struct {
 	Answer  string        `json:"answer"`
 	Results []ListingInfo `json:"results"`
 }
+
+// ResponseDeltaPayload is one chunk of a streamed answer; append chunks in
+// seq order, except that a replace chunk discards the ones before it. The
+// done event's answer replaces them all.
+type ResponseDeltaPayload struct {
+	Seq     int    `json:"seq"`
+	Delta   string `json:"delta"`
+	Replace bool   `json:"replace,omitempty"`
+}
 type ChatDeliverPayload struct {
-	FromUserID string    `json:"fromUserId"`
-	Text       string    `json:"text"`
-	SentAt     time.Time `json:"sentAt"`
+	MessageID      string    `json:"messageId"`
+	ConversationID string    `json:"conversationId"`
+	FromUserID     string    `json:"fromUserId"`
+	Text           string    `json:"text"`
+	SentAt         time.Time `json:"sentAt"`
+}
+
+// ListingWatchPayload tells a user about a listing they favorited. Kind is
+// "price_drop" (with oldPrice) or "sold".
+type ListingWatchPayload struct {
+	ListingID string  `json:"listingId"`
+	Title     string  `json:"title"`
+	Kind      string  `json:"kind"`
+	OldPrice  float64 `json:"oldPrice,omitempty"`
+	Price     float64 `json:"price"`
+}
+
+// SearchMatchPayload carries new listings found for a saved search.
+type SearchMatchPayload struct {
+	SearchID   string        `json:"searchId"`
+	SearchName string        `json:"searchName"`
+	Results    []ListingInfo `json:"results"`
+}
+
+// NotificationPayload is a notification as stored; see GET /v1/notifications.
+type NotificationPayload struct {
+	ID        string          `json:"id"`
+	Kind      string          `json:"kind"`
+	Title     string          `json:"title"`
+	Body      string          `json:"body,omitempty"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	CreatedAt string          `json:"createdAt"`
 }
 
 type PrimaryImage struct {
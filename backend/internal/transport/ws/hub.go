@@ -1,56 +1,214 @@
 package ws
 
 import (
+	"context"
 	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
 
 	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/pubsub"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/service"
 	"go.uber.org/zap"
 )
 
+// Hub tracks every open connection, grouped by user so that a user with the
+// app open in several tabs receives events in all of them.
 type Hub struct {
-	clients    map[string]*Client
+	// mu guards clients. Run mutates it; client goroutines read it through
+	// BroadcastToUser. Sends to client.send happen under the read lock and
+	// close(client.send) under the write lock, so a send never races a close.
+	mu         sync.RWMutex
+	clients    map[string]map[*Client]struct{}
 	register   chan *Client
 	unregister chan *Client
-	bus        *pubsub.Bus
+	pending    chan pendingBatch
+	done       chan struct{} // closed when Run returns
+	bus        pubsub.Broker
+	chat       *service.ChatService // nil when the DB is unavailable
+	notifier   service.Notifier     // nil when the DB is unavailable
 	logger     *zap.Logger
 }
 
-func NewHub(bus *pubsub.Bus, logger *zap.Logger) *Hub {
+func NewHub(bus pubsub.Broker, chat *service.ChatService, notifier service.Notifier, logger *zap.Logger) *Hub {
 	return &Hub{
-		clients:    make(map[string]*Client),
+		clients:    make(map[string]map[*Client]struct{}),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
+		pending:    make(chan pendingBatch),
+		done:       make(chan struct{}),
 		bus:        bus,
+		chat:       chat,
+		notifier:   notifier,
 		logger:     logger,
 	}
 }
 
-func (h *Hub) RegisterClient(client *Client) { h.register <- client }
+// RegisterClient adds client to the hub. It does nothing once Run has
+// returned.
+func (h *Hub) RegisterClient(client *Client) {
+	select {
+	case h.register <- client:
+	case <-h.done:
+	}
+}
+
+func (h *Hub) unregisterClient(client *Client) {
+	select {
+	case h.unregister <- client:
+	case <-h.done:
+	}
+}
 
 func (h *Hub) Run() {
-	agentRespChan := h.bus.Subscribe("agent.response")
-	chatRespChan := h.bus.Subscribe("chat.response") // NEW
+	defer close(h.done)
+
+	// Someone is waiting on every response, so give them a deep buffer. It
+	// still drops when full rather than blocking: the Postgres listener hands
+	// notifications to subscribers one by one, and a stalled hub would hold
+	// up every other topic behind it.
+	respOpts := pubsub.SubscribeOptions{Buffer: 1024}
+	agentRespChan := h.bus.SubscribeWith("agent.response", respOpts)
+	chatRespChan := h.bus.SubscribeWith("chat.response", respOpts)
+	agentDeltaChan := h.bus.SubscribeWith("agent.response.delta", respOpts)
+	chatDeltaChan := h.bus.SubscribeWith("chat.response.delta", respOpts)
+	watchChan := h.bus.Subscribe("listing.watch")
+	matchChan := h.bus.Subscribe("search.match")
+	notificationChan := h.bus.Subscribe("notification.new")
 
 	for {
 		select {
 		case c := <-h.register:
-			h.clients[c.userID] = c
-			h.logger.Info("client registered", zap.String("userId", c.userID), zap.Int("totalClients", len(h.clients)))
+			sessions := h.addClient(c)
+			h.logger.Info("client registered",
+				zap.String("userId", c.userID),
+				zap.Int("userSessions", sessions),
+				zap.Int("totalClients", h.GetClientCount()),
+			)
+			if h.chat != nil {
+				go h.loadPending(c)
+			} else {
+				c.replay.finish(nil)
+			}
+
+		case b := <-h.pending:
+			h.flushPending(b)
 
 		case c := <-h.unregister:
-			if _, ok := h.clients[c.userID]; ok {
-				delete(h.clients, c.userID)
-				close(c.send)
-				h.logger.Info("client unregistered", zap.String("userId", c.userID), zap.Int("totalClients", len(h.clients)))
+			if h.removeClient(c) {
+				h.logger.Info("client unregistered", zap.String("userId", c.userID), zap.Int("totalClients", h.GetClientCount()))
 			}
 
-		case msg := <-agentRespChan:
+		case msg, ok := <-agentRespChan:
+			if !ok {
+				h.logger.Info("pub/sub closed, hub stopping")
+				return
+			}
 			h.handleAgentResponse(msg)
 
-		case msg := <-chatRespChan:
-			h.handleChatResponse(msg) // NEW
+		case msg, ok := <-chatRespChan:
+			if !ok {
+				h.logger.Info("pub/sub closed, hub stopping")
+				return
+			}
+			h.handleChatResponse(msg)
+
+		case msg, ok := <-agentDeltaChan:
+			if !ok {
+				h.logger.Info("pub/sub closed, hub stopping")
+				return
+			}
+			h.handleResponseDelta(EventTypeAgentResponseDelta, msg)
+
+		case msg, ok := <-chatDeltaChan:
+			if !ok {
+				h.logger.Info("pub/sub closed, hub stopping")
+				return
+			}
+			h.handleResponseDelta(EventTypeChatResponseDelta, msg)
+
+		case msg, ok := <-watchChan:
+			if !ok {
+				h.logger.Info("pub/sub closed, hub stopping")
+				return
+			}
+			h.handleListingWatch(msg)
+
+		case msg, ok := <-matchChan:
+			if !ok {
+				h.logger.Info("pub/sub closed, hub stopping")
+				return
+			}
+			h.handleSearchMatch(msg)
+
+		case msg, ok := <-notificationChan:
+			if !ok {
+				h.logger.Info("pub/sub closed, hub stopping")
+				return
+			}
+			h.handleNotification(msg)
+		}
+	}
+}
+
+// addClient adds c to its user's session set and returns the set's new size.
+func (h *Hub) addClient(c *Client) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sessions, ok := h.clients[c.userID]
+	if !ok {
+		sessions = make(map[*Client]struct{})
+		h.clients[c.userID] = sessions
+	}
+	sessions[c] = struct{}{}
+	return len(sessions)
+}
+
+// removeClient drops c and closes its send channel. It reports false if c was
+// already gone, so a second unregister is harmless.
+func (h *Hub) removeClient(c *Client) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sessions, ok := h.clients[c.userID]
+	if !ok {
+		return false
+	}
+	if _, ok := sessions[c]; !ok {
+		return false
+	}
+	delete(sessions, c)
+	if len(sessions) == 0 {
+		delete(h.clients, c.userID)
+	}
+	close(c.send)
+	return true
+}
+
+// hasClient reports whether c is still registered.
+func (h *Hub) hasClient(c *Client) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	_, ok := h.clients[c.userID][c]
+	return ok
+}
+
+// sendToUser queues message on every session of userID without blocking. It
+// returns how many sessions accepted it and how many the user has.
+func (h *Hub) sendToUser(userID string, message []byte) (delivered, sessions int) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for c := range h.clients[userID] {
+		select {
+		case c.send <- message:
+			delivered++
+		default:
+			h.logger.Warn("client send buffer full, dropping message for one session", zap.String("userId", userID))
 		}
 	}
+	return delivered, len(h.clients[userID])
 }
 
 func (h *Hub) handleAgentResponse(msg pubsub.Message) {
@@ -59,25 +217,22 @@ func (h *Hub) handleAgentResponse(msg pubsub.Message) {
 		h.logger.Error("invalid agent response payload")
 		return
 	}
-	client, ok := h.clients[res.UserID]
-	if !ok {
-		h.logger.Warn("client not found for agent response", zap.String("userId", res.UserID))
-		return
-	}
 	wsResults := make([]ListingInfo, len(res.Results))
 	for i, r := range res.Results {
 		wsResults[i] = convertListing(r)
 	}
 	payload := AgentResponsePayload{Answer: res.Answer, Results: wsResults}
-	ev, err := NewEvent(EventTypeAgentResponse, res.RequestID, payload)
+	evType := EventTypeAgentResponse
+	if res.Streamed {
+		evType = EventTypeAgentResponseDone
+	}
+	ev, err := NewEvent(evType, res.RequestID, payload)
 	if err != nil {
 		h.logger.Error("marshal agent response failed", zap.Error(err))
 		return
 	}
-	select {
-	case client.send <- ev:
-	default:
-		h.logger.Warn("client send buffer full, dropping agent response", zap.String("userId", res.UserID))
+	if _, sessions := h.sendToUser(res.UserID, ev); sessions == 0 {
+		h.logger.Warn("client not found for agent response", zap.String("userId", res.UserID))
 	}
 }
 
@@ -87,26 +242,242 @@ func (h *Hub) handleChatResponse(msg pubsub.Message) {
 		h.logger.Error("invalid chat response payload")
 		return
 	}
-	client, ok := h.clients[res.UserID]
-	if !ok {
-		h.logger.Warn("client not found for chat response", zap.String("userId", res.UserID))
-		return
-	}
 	wsResults := make([]ListingInfo, len(res.Results))
 	for i, r := range res.Results {
 		wsResults[i] = convertListing(r)
 	}
 	payload := ChatResponsePayload{Answer: res.Answer, Results: wsResults}
-	ev, err := NewEvent(EventTypeChatResponse, res.RequestID, payload)
+	evType := EventTypeChatResponse
+	if res.Streamed {
+		evType = EventTypeChatResponseDone
+	}
+	ev, err := NewEvent(evType, res.RequestID, payload)
 	if err != nil {
 		h.logger.Error("marshal chat response failed", zap.Error(err))
 		return
 	}
+	if _, sessions := h.sendToUser(res.UserID, ev); sessions == 0 {
+		h.logger.Warn("client not found for chat response", zap.String("userId", res.UserID))
+	}
+}
+
+// handleResponseDelta forwards one chunk of a streamed answer under the
+// original request's id.
+func (h *Hub) handleResponseDelta(evType string, msg pubsub.Message) {
+	d, ok := msg.Payload.(pubsub.ResponseDelta)
+	if !ok {
+		h.logger.Error("invalid response delta payload", zap.String("topic", msg.Topic))
+		return
+	}
+	ev, err := NewEvent(evType, d.RequestID, ResponseDeltaPayload{Seq: d.Seq, Delta: d.Delta, Replace: d.Replace})
+	if err != nil {
+		h.logger.Error("marshal response delta failed", zap.Error(err))
+		return
+	}
+	if _, sessions := h.sendToUser(d.UserID, ev); sessions == 0 {
+		h.logger.Debug("client not found for response delta", zap.String("userId", d.UserID))
+	}
+}
+
+// handleListingWatch tells each connected watcher about the listing change.
+// Watchers who are offline miss it.
+func (h *Hub) handleListingWatch(msg pubsub.Message) {
+	w, ok := msg.Payload.(pubsub.ListingWatchEvent)
+	if !ok {
+		h.logger.Error("invalid listing watch payload")
+		return
+	}
+	ev, err := NewEvent(EventTypeListingWatch, "", ListingWatchPayload{
+		ListingID: w.ListingID, Title: w.Title, Kind: w.Kind, OldPrice: w.OldPrice, Price: w.Price,
+	})
+	if err != nil {
+		h.logger.Error("marshal listing watch failed", zap.Error(err))
+		return
+	}
+	for _, userID := range w.UserIDs {
+		h.sendToUser(userID, ev)
+	}
+}
+
+// handleSearchMatch pushes saved search matches to the user if connected;
+// they stay listed under GET /v1/saved-searches/matches either way.
+func (h *Hub) handleSearchMatch(msg pubsub.Message) {
+	m, ok := msg.Payload.(pubsub.SavedSearchMatch)
+	if !ok {
+		h.logger.Error("invalid search match payload")
+		return
+	}
+	results := make([]ListingInfo, len(m.Results))
+	for i, r := range m.Results {
+		results[i] = convertListing(r)
+	}
+	ev, err := NewEvent(EventTypeSearchMatch, "", SearchMatchPayload{SearchID: m.SearchID, SearchName: m.SearchName, Results: results})
+	if err != nil {
+		h.logger.Error("marshal search match failed", zap.Error(err))
+		return
+	}
+	h.sendToUser(m.UserID, ev)
+}
+
+// handleNotification pushes a new notification to the user's open sessions.
+// Offline users find it under GET /v1/notifications.
+func (h *Hub) handleNotification(msg pubsub.Message) {
+	n, ok := msg.Payload.(pubsub.NotificationEvent)
+	if !ok {
+		h.logger.Error("invalid notification payload")
+		return
+	}
+	ev, err := NewEvent(EventTypeNotificationNew, "", NotificationPayload{
+		ID: n.ID, Kind: n.Kind, Title: n.Title, Body: n.Body, Data: n.Data, CreatedAt: n.CreatedAt,
+	})
+	if err != nil {
+		h.logger.Error("marshal notification failed", zap.Error(err))
+		return
+	}
+	if err := h.BroadcastToUser(n.UserID, ev); err != nil {
+		h.logger.Debug("notification not pushed", zap.String("userId", n.UserID), zap.Error(err))
+	}
+}
+
+// pendingBatch carries unread chat messages loaded for a freshly registered
+// client back into the Run loop, which owns client.send.
+type pendingBatch struct {
+	client *Client
+	events []chatEvent
+}
+
+// chatEvent is a chat.deliver event and the message it carries.
+type chatEvent struct {
+	messageID string
+	event     []byte
+}
+
+// chatReplay keeps a session's live chat deliveries and its replay of unread
+// messages from both sending the same message: one sent while the unread
+// messages load is left out of the replay, and one replayed is not sent
+// again live.
+type chatReplay struct {
+	mu       sync.Mutex
+	done     bool                // the replay went out
+	live     map[string]struct{} // sent live before the replay
+	replayed map[string]struct{} // sent by the replay, not yet seen live
+}
+
+// sendLive reports whether the live delivery of messageID should go out.
+func (r *chatReplay) sendLive(messageID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.done {
+		if r.live == nil {
+			r.live = make(map[string]struct{})
+		}
+		r.live[messageID] = struct{}{}
+		return true
+	}
+	if _, ok := r.replayed[messageID]; ok {
+		delete(r.replayed, messageID)
+		return false
+	}
+	return true
+}
+
+// finish drops the events already sent live and returns the rest, which
+// the caller must send.
+func (r *chatReplay) finish(events []chatEvent) []chatEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []chatEvent
+	for _, e := range events {
+		if _, ok := r.live[e.messageID]; ok {
+			continue
+		}
+		if r.replayed == nil {
+			r.replayed = make(map[string]struct{})
+		}
+		r.replayed[e.messageID] = struct{}{}
+		out = append(out, e)
+	}
+	r.done, r.live = true, nil
+	return out
+}
+
+// loadPending reads the user's unread messages off the hub goroutine so a
+// slow query doesn't stall registration for everyone else. It always hands
+// back a batch, possibly empty, so the session stops tracking live messages.
+func (h *Hub) loadPending(c *Client) {
+	b := pendingBatch{client: c}
+	if userID, err := uuid.Parse(c.userID); err == nil {
+		msgs, err := h.chat.PendingMessages(context.Background(), userID)
+		if err != nil {
+			h.logger.Error("load pending chat messages failed", zap.String("userId", c.userID), zap.Error(err))
+		}
+		for _, m := range msgs {
+			ev, err := NewEvent(EventTypeChatDeliver, "", ChatDeliverPayload{
+				MessageID:      m.ID.String(),
+				ConversationID: m.ConversationID.String(),
+				FromUserID:     m.SenderID.String(),
+				Text:           m.Body,
+				SentAt:         m.CreatedAt,
+			})
+			if err != nil {
+				h.logger.Error("marshal pending chat message failed", zap.Error(err))
+				continue
+			}
+			b.events = append(b.events, chatEvent{messageID: m.ID.String(), event: ev})
+		}
+	}
 	select {
-	case client.send <- ev:
-	default:
-		h.logger.Warn("client send buffer full, dropping chat response", zap.String("userId", res.UserID))
+	case h.pending <- b:
+	case <-h.done:
+	}
+}
+
+// flushPending replays the batch to the one session it was loaded for; the
+// user's other sessions already received these messages live.
+func (h *Hub) flushPending(b pendingBatch) {
+	events := b.client.replay.finish(b.events)
+	// Only Run closes client.send, and we are on Run's goroutine, so the
+	// check below cannot go stale before the sends.
+	if !h.hasClient(b.client) || len(events) == 0 {
+		return
+	}
+	for _, e := range events {
+		select {
+		case b.client.send <- e.event:
+		default:
+			h.logger.Warn("client send buffer full, dropping pending chat messages", zap.String("userId", b.client.userID))
+			return
+		}
 	}
+	h.logger.Info("flushed pending chat messages", zap.String("userId", b.client.userID), zap.Int("count", len(events)))
+}
+
+// deliverChat is BroadcastToUser for a chat.deliver event, skipping sessions
+// whose pending replay already carried the message.
+func (h *Hub) deliverChat(userID, messageID string, message []byte) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	delivered, sessions := 0, len(h.clients[userID])
+	for c := range h.clients[userID] {
+		if !c.replay.sendLive(messageID) {
+			delivered++
+			continue
+		}
+		select {
+		case c.send <- message:
+			delivered++
+		default:
+			h.logger.Warn("client send buffer full, dropping chat message for one session", zap.String("userId", userID))
+		}
+	}
+	if sessions == 0 {
+		return fmt.Errorf("user not connected: %s", userID)
+	}
+	if delivered == 0 {
+		return fmt.Errorf("client send buffer full for user: %s", userID)
+	}
+	return nil
 }
 
 func convertListing(r pubsub.ListingInfo) ListingInfo {
@@ -121,16 +492,14 @@ func convertListing(r pubsub.ListingInfo) ListingInfo {
 	}
 }
 
-// publishAgentRequest (existing)
-func (h *Hub) publishAgentRequest(userID, requestID, query string) {
-	req := pubsub.AgentRequest{UserID: userID, RequestID: requestID, Query: query}
+func (h *Hub) publishAgentRequest(userID, sessionID, requestID, query string, stream bool) {
+	req := pubsub.AgentRequest{UserID: userID, SessionID: sessionID, RequestID: requestID, Query: query, Stream: stream}
 	h.bus.Publish("agent.request", req)
 	h.logger.Debug("published agent request", zap.String("userId", userID), zap.String("requestId", requestID), zap.String("query", query))
 }
 
-// NEW: publish chat message
-func (h *Hub) publishChatRequest(userID, requestID, text string) {
-	req := pubsub.ChatRequest{UserID: userID, RequestID: requestID, Text: text}
+func (h *Hub) publishChatRequest(userID, sessionID, requestID, text string, stream bool) {
+	req := pubsub.ChatRequest{UserID: userID, SessionID: sessionID, RequestID: requestID, Text: text, Stream: stream}
 	h.bus.Publish("chat.request", req)
 	h.logger.Debug("published chat request",
 		zap.String("userId", userID),
@@ -139,19 +508,34 @@ func (h *Hub) publishChatRequest(userID, requestID, text string) {
 	)
 }
 
-func (h *Hub) GetClientCount() int { return len(h.clients) }
+// publishConversationReset tells every worker to drop the session's
+// assistant context.
+func (h *Hub) publishConversationReset(userID, sessionID string) {
+	h.bus.Publish("agent.reset", pubsub.ConversationReset{UserID: userID, SessionID: sessionID})
+	h.logger.Debug("published conversation reset", zap.String("userId", userID), zap.String("sessionId", sessionID))
+}
+
+// GetClientCount returns the number of open connections across all users.
+func (h *Hub) GetClientCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	n := 0
+	for _, sessions := range h.clients {
+		n += len(sessions)
+	}
+	return n
+}
 
+// BroadcastToUser sends message to every open session of userID. It fails only
+// if the user has no sessions or none of them could take the message.
 func (h *Hub) BroadcastToUser(userID string, message []byte) error {
-	client, exists := h.clients[userID]
-	h.logger.Debug("BroadcastToUser", zap.String("userId", userID), zap.Bool("exists", exists))
-	if !exists {
+	delivered, sessions := h.sendToUser(userID, message)
+	h.logger.Debug("BroadcastToUser", zap.String("userId", userID), zap.Int("sessions", sessions), zap.Int("delivered", delivered))
+	if sessions == 0 {
 		return fmt.Errorf("user not connected: %s", userID)
 	}
-
-	select {
-	case client.send <- message:
-		return nil
-	default:
+	if delivered == 0 {
 		return fmt.Errorf("client send buffer full for user: %s", userID)
 	}
+	return nil
 }
@@ -0,0 +1,165 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/domain"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/platform/clock"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/pubsub"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/repository"
+	"github.com/gopinathsjsu/team-project-cmpe202-03-fall2025-campushub/backend/internal/service"
+)
+
+// fakeChatRepo only answers ListUnreadMessages.
+type fakeChatRepo struct {
+	repository.ChatRepo
+	unread []domain.Message
+}
+
+func (r fakeChatRepo) ListUnreadMessages(context.Context, uuid.UUID, int) ([]domain.Message, error) {
+	return r.unread, nil
+}
+
+func newChatHub(unread ...domain.Message) *Hub {
+	chat := service.NewChatService(fakeChatRepo{unread: unread}, nil, clock.Real{})
+	return NewHub(pubsub.New(), chat, nil, zap.NewNop())
+}
+
+// drainMessageIDs returns the message ids of the chat.deliver events queued
+// for c.
+func drainMessageIDs(t *testing.T, c *Client) []string {
+	t.Helper()
+	var ids []string
+	for {
+		select {
+		case raw := <-c.send:
+			var ev struct {
+				Type    string             `json:"type"`
+				Payload ChatDeliverPayload `json:"payload"`
+			}
+			if err := json.Unmarshal(raw, &ev); err != nil {
+				t.Fatal(err)
+			}
+			if ev.Type == EventTypeChatDeliver {
+				ids = append(ids, ev.Payload.MessageID)
+			}
+		default:
+			return ids
+		}
+	}
+}
+
+func TestHubRegisterUnregisterConcurrently(t *testing.T) {
+	bus := pubsub.New()
+	hub := NewHub(bus, nil, nil, zap.NewNop())
+	go hub.Run()
+
+	const users, sessionsPerUser = 8, 6
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var clients []*Client
+	for u := range users {
+		userID := fmt.Sprintf("user-%d", u)
+		for range sessionsPerUser {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				c := NewClient(hub, nil, userID, "user", zap.NewNop())
+				mu.Lock()
+				clients = append(clients, c)
+				mu.Unlock()
+				hub.RegisterClient(c)
+				_ = hub.BroadcastToUser(userID, []byte(`{"type":"ping"}`))
+				hub.unregisterClient(c)
+				hub.unregisterClient(c) // a second unregister is harmless
+			}()
+			go func() {
+				defer wg.Done()
+				_ = hub.BroadcastToUser(userID, []byte(`{"type":"ping"}`))
+				_ = hub.GetClientCount()
+			}()
+		}
+	}
+	wg.Wait()
+	bus.Close()
+
+	select {
+	case <-hub.done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not stop after the bus closed")
+	}
+	if n := hub.GetClientCount(); n != 0 {
+		t.Errorf("%d clients left registered", n)
+	}
+	for _, c := range clients {
+		for range c.send {
+		}
+	}
+}
+
+func TestHubStopsWaitingAfterRun(t *testing.T) {
+	hub := newChatHub(domain.Message{ID: uuid.New()})
+	go hub.Run()
+	hub.bus.(*pubsub.Bus).Close()
+	<-hub.done
+
+	c := NewClient(hub, nil, uuid.NewString(), "user", zap.NewNop())
+	finished := make(chan struct{})
+	go func() {
+		hub.RegisterClient(c)
+		hub.loadPending(c)
+		hub.unregisterClient(c)
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(2 * time.Second):
+		t.Fatal("hub calls blocked after Run returned")
+	}
+}
+
+func TestPendingReplaySkipsLiveMessages(t *testing.T) {
+	m1, m2, m3 := uuid.New(), uuid.New(), uuid.New()
+	hub := newChatHub(domain.Message{ID: m1}, domain.Message{ID: m2})
+	userID := uuid.NewString()
+	c := NewClient(hub, nil, userID, "user", zap.NewNop())
+	hub.addClient(c)
+
+	// m2 arrives live while the unread messages are loading.
+	if err := hub.deliverChat(userID, m2.String(), chatDeliver(t, m2)); err != nil {
+		t.Fatal(err)
+	}
+	go hub.loadPending(c)
+	hub.flushPending(<-hub.pending)
+
+	// m1 was replayed; its live delivery comes too late and is dropped.
+	if err := hub.deliverChat(userID, m1.String(), chatDeliver(t, m1)); err != nil {
+		t.Errorf("late live delivery: %v, want it counted as delivered", err)
+	}
+	if err := hub.deliverChat(userID, m3.String(), chatDeliver(t, m3)); err != nil {
+		t.Fatal(err)
+	}
+
+	got := drainMessageIDs(t, c)
+	want := []string{m2.String(), m1.String(), m3.String()}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("delivered %v, want %v", got, want)
+	}
+}
+
+func chatDeliver(t *testing.T, id uuid.UUID) []byte {
+	t.Helper()
+	ev, err := NewEvent(EventTypeChatDeliver, "", ChatDeliverPayload{MessageID: id.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ev
+}
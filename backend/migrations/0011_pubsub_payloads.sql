@@ -0,0 +1,9 @@
+-- Payloads too large for a NOTIFY (8000 bytes) are parked here by the
+-- postgres pub/sub broker and fetched by id.
+CREATE TABLE IF NOT EXISTS pubsub_payloads (
+  id UUID PRIMARY KEY,
+  topic TEXT NOT NULL,
+  payload JSONB NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_pubsub_payloads_created_at ON pubsub_payloads(created_at);
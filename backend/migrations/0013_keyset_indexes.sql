@@ -0,0 +1,5 @@
+-- Keyset pagination walks these (sort key, id) pairs.
+CREATE INDEX IF NOT EXISTS idx_listings_created_id ON listings(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_listings_price_id ON listings(price, id);
+CREATE INDEX IF NOT EXISTS idx_reports_created_id ON reports(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_users_created_id ON users(created_at, id);
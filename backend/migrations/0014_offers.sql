@@ -0,0 +1,24 @@
+-- Accepting an offer reserves the listing for that buyer.
+ALTER TABLE listings DROP CONSTRAINT IF EXISTS listings_status_check;
+ALTER TABLE listings ADD CONSTRAINT listings_status_check
+  CHECK (status IN ('active','reserved','sold','removed'));
+
+CREATE TABLE IF NOT EXISTS offers (
+  id UUID PRIMARY KEY,
+  listing_id UUID NOT NULL REFERENCES listings(id) ON DELETE CASCADE,
+  buyer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  seller_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  amount NUMERIC(10,2) NOT NULL CHECK (amount > 0),
+  message TEXT NOT NULL DEFAULT '',
+  status TEXT NOT NULL DEFAULT 'pending'
+    CHECK (status IN ('pending','countered','accepted','rejected','withdrawn')),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- One offer under negotiation per buyer per listing.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_offers_active_buyer
+  ON offers(listing_id, buyer_id) WHERE status IN ('pending','countered');
+CREATE INDEX IF NOT EXISTS idx_offers_listing ON offers(listing_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_offers_buyer ON offers(buyer_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_offers_seller ON offers(seller_id, created_at);
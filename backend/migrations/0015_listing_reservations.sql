@@ -0,0 +1,6 @@
+-- A reserved listing is held for one buyer until reserved_until; the API's
+-- sweeper puts it back to active after that.
+ALTER TABLE listings ADD COLUMN IF NOT EXISTS reserved_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE listings ADD COLUMN IF NOT EXISTS reserved_until TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_listings_reserved_until ON listings(reserved_until) WHERE status = 'reserved';
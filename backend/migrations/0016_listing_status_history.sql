@@ -0,0 +1,13 @@
+-- Every listing status change: who made it (NULL for the system, e.g. an
+-- expired reservation) and why.
+CREATE TABLE IF NOT EXISTS listing_status_history (
+  id UUID PRIMARY KEY,
+  listing_id UUID NOT NULL REFERENCES listings(id) ON DELETE CASCADE,
+  from_status TEXT NOT NULL,
+  to_status TEXT NOT NULL,
+  changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  reason TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_listing_status_history_listing ON listing_status_history(listing_id, created_at);
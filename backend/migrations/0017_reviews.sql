@@ -0,0 +1,14 @@
+-- A buyer reviews the seller once per listing they bought.
+CREATE TABLE IF NOT EXISTS reviews (
+  id UUID PRIMARY KEY,
+  listing_id UUID NOT NULL UNIQUE REFERENCES listings(id) ON DELETE CASCADE,
+  seller_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  buyer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+  body TEXT NOT NULL DEFAULT '',
+  reply TEXT,
+  replied_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviews_seller ON reviews(seller_id, created_at);
@@ -0,0 +1,6 @@
+-- Editable profile fields shown on a user's public page.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS bio TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_key TEXT;
+
+-- Profile listing counts.
+CREATE INDEX IF NOT EXISTS idx_listings_seller_status ON listings(seller_id, status);
@@ -0,0 +1,10 @@
+-- Listings a user is watching.
+CREATE TABLE IF NOT EXISTS favorites (
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  listing_id UUID NOT NULL REFERENCES listings(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (user_id, listing_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_favorites_listing ON favorites(listing_id);
+CREATE INDEX IF NOT EXISTS idx_favorites_user_created ON favorites(user_id, created_at);
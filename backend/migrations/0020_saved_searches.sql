@@ -0,0 +1,25 @@
+-- Listing queries a user wants to hear about when new listings match.
+CREATE TABLE IF NOT EXISTS saved_searches (
+  id UUID PRIMARY KEY,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  q TEXT NOT NULL DEFAULT '',
+  category TEXT NOT NULL DEFAULT '',
+  condition TEXT NOT NULL DEFAULT '',
+  price_min NUMERIC(10,2),
+  price_max NUMERIC(10,2),
+  last_checked_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_user ON saved_searches(user_id, created_at);
+
+-- New listings the matcher found for a saved search; the user's in-app list.
+CREATE TABLE IF NOT EXISTS saved_search_matches (
+  search_id UUID NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+  listing_id UUID NOT NULL REFERENCES listings(id) ON DELETE CASCADE,
+  matched_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (search_id, listing_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_search_matches_matched ON saved_search_matches(search_id, matched_at);
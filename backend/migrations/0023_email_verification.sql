@@ -0,0 +1,15 @@
+-- Users confirm their (campus) email before they can list items.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ;
+-- Accounts that predate verification keep working.
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
+
+-- One-time tokens mailed at sign-up, stored as SHA-256 hashes.
+CREATE TABLE IF NOT EXISTS email_verifications (
+  token_hash TEXT PRIMARY KEY,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_verifications_user ON email_verifications(user_id);
@@ -0,0 +1,20 @@
+-- Failed sign-in counters, one row per "account:<email>" or "ip:<addr>".
+-- Rows go away on a successful sign-in (accounts) or an admin unlock.
+CREATE TABLE IF NOT EXISTS login_attempts (
+  key TEXT PRIMARY KEY,
+  failures INT NOT NULL,
+  last_failed_at TIMESTAMPTZ NOT NULL,
+  locked_until TIMESTAMPTZ
+);
+
+-- Security-relevant events, e.g. lockouts and who lifted them.
+CREATE TABLE IF NOT EXISTS audit_log (
+  id UUID PRIMARY KEY,
+  action TEXT NOT NULL,
+  actor_id UUID REFERENCES users(id) ON DELETE SET NULL, -- NULL: done by the system
+  subject TEXT NOT NULL,
+  detail TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created ON audit_log(created_at DESC, id DESC);
@@ -0,0 +1,6 @@
+-- An accepted offer whose reservation ends without a sale is closed too:
+-- expired when the reservation runs out, cancelled when the seller or an
+-- admin ends it.
+ALTER TABLE offers DROP CONSTRAINT IF EXISTS offers_status_check;
+ALTER TABLE offers ADD CONSTRAINT offers_status_check
+  CHECK (status IN ('pending','countered','accepted','rejected','withdrawn','expired','cancelled'));